@@ -33,6 +33,7 @@ import (
 
 	gosightagent "github.com/aaronlmathis/gosight-agent/internal/agent"
 	"github.com/aaronlmathis/gosight-agent/internal/bootstrap"
+	"github.com/aaronlmathis/gosight-agent/internal/sink"
 	"github.com/aaronlmathis/gosight-shared/utils"
 )
 
@@ -51,6 +52,9 @@ func run(configFlag *string) {
 	bootstrap.SetupLogging(cfg)
 	utils.Debug("debug logging is active from main.go")
 
+	// Keep the agent's own footprint within the configured limits
+	bootstrap.ApplyResourceLimits(cfg)
+
 	// Graceful shutdown context
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -73,6 +77,12 @@ func run(configFlag *string) {
 	// Start Agent
 	agent.Start(ctx)
 
+	// SIGUSR1 dumps a local snapshot of all collectors for debugging
+	watchDumpSignal(ctx, agent)
+
+	// SIGHUP reloads the configuration without restarting the agent
+	watchReloadSignal(ctx, agent, configFlag)
+
 	<-ctx.Done()
 
 	utils.Info("Context canceled, beginning agent shutdown...")
@@ -84,6 +94,8 @@ func run(configFlag *string) {
 func main() {
 	versionFlag := flag.Bool("version", false, "print version information and exit")
 	configFlag := flag.String("config", "", "Path to server config file")
+	pingFlag := flag.Bool("ping", false, "send one test metric to the server and report connectivity, then exit")
+	stdoutFlag := flag.Bool("stdout", false, "print payloads as JSON to stdout instead of sending them to the server (dry run)")
 	flag.Parse()
 	if *versionFlag {
 		fmt.Printf(
@@ -92,5 +104,12 @@ func main() {
 		)
 		os.Exit(0)
 	}
+	if *pingFlag {
+		runPing(configFlag)
+		return
+	}
+	if *stdoutFlag {
+		bootstrap.SinkOverride = sink.Stdout
+	}
 	run(configFlag)
 }
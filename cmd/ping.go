@@ -0,0 +1,103 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// cmd/ping.go - one-shot connectivity check (--ping).
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/aaronlmathis/gosight-agent/internal/bootstrap"
+	grpcconn "github.com/aaronlmathis/gosight-agent/internal/grpc"
+	agentidentity "github.com/aaronlmathis/gosight-agent/internal/identity"
+	"github.com/aaronlmathis/gosight-agent/internal/meta"
+	"github.com/aaronlmathis/gosight-agent/internal/metrics/metricsender"
+	"github.com/aaronlmathis/gosight-shared/model"
+)
+
+// pingTimeout bounds the whole connectivity check.
+const pingTimeout = 30 * time.Second
+
+// runPing sends a single test metric to the server and prints a clear
+// success or failure report. It exits non-zero on failure.
+func runPing(configFlag *string) {
+	cfg := bootstrap.LoadAgentConfig(configFlag)
+
+	agentID, err := agentidentity.LoadOrCreateAgentID(cfg.Agent.RegenerateIDOnHostChange)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "FAIL: could not load agent ID: %v\n", err)
+		os.Exit(1)
+	}
+
+	baseMeta := meta.BuildMeta(cfg, nil, agentID, Version)
+	baseMeta.EndpointID = meta.GenerateEndpointID(baseMeta)
+	baseMeta.Kind = "host"
+
+	now := time.Now()
+	payload := &model.MetricPayload{
+		AgentID:    baseMeta.AgentID,
+		HostID:     baseMeta.HostID,
+		Hostname:   baseMeta.Hostname,
+		EndpointID: baseMeta.EndpointID,
+		Timestamp:  now,
+		Metrics: []model.Metric{{
+			Namespace:    "Agent",
+			SubNamespace: "Ping",
+			Name:         "ping",
+			Timestamp:    now,
+			Value:        1,
+			Type:         "gauge",
+			Unit:         "count",
+		}},
+		Meta: baseMeta,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+	defer cancel()
+
+	fmt.Printf("Pinging %s ...\n", cfg.Agent.ServerURL)
+	res, err := metricsender.Ping(ctx, cfg, payload)
+	_ = grpcconn.CloseGRPCConn()
+
+	if res.TLSVersion != "" {
+		fmt.Printf("  TLS:         %s, %s\n", res.TLSVersion, res.CipherSuite)
+		fmt.Printf("  Server cert: %s\n", res.ServerSubject)
+		fmt.Printf("  Issuer:      %s\n", res.ServerIssuer)
+		fmt.Printf("  Expires:     %s\n", res.CertExpires.Format(time.RFC3339))
+	}
+	if res.StatusCode != "" {
+		fmt.Printf("  gRPC status: %s\n", res.StatusCode)
+	}
+
+	if err != nil {
+		fmt.Printf("FAIL: %v\n", err)
+		os.Exit(1)
+	}
+	if res.Rejected > 0 {
+		fmt.Printf("FAIL: server rejected %d data point(s)\n", res.Rejected)
+		os.Exit(1)
+	}
+	fmt.Printf("OK: test metric acknowledged in %s\n", res.Latency.Round(time.Millisecond))
+}
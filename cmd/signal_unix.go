@@ -0,0 +1,88 @@
+//go:build !windows
+
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// cmd/signal_unix.go - on-demand debug snapshot via SIGUSR1 and config reload via SIGHUP.
+
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	gosightagent "github.com/aaronlmathis/gosight-agent/internal/agent"
+	"github.com/aaronlmathis/gosight-agent/internal/bootstrap"
+	"github.com/aaronlmathis/gosight-shared/utils"
+)
+
+// watchDumpSignal writes a one-shot collector snapshot every time the agent
+// receives SIGUSR1. It returns when ctx is canceled.
+func watchDumpSignal(ctx context.Context, agent *gosightagent.Agent) {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGUSR1)
+
+	go func() {
+		defer signal.Stop(sigs)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sigs:
+				utils.Info("SIGUSR1 received, writing debug snapshot")
+				if err := agent.DumpSnapshot(ctx); err != nil {
+					utils.Error("failed to write debug snapshot: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// watchReloadSignal reloads the configuration every time the agent receives
+// SIGHUP: the file is loaded again (flags -> env -> file), logging is
+// re-initialized with the new level and files, and the config is applied to
+// the running agent (see Agent.ApplyConfig). An invalid file is logged and the
+// current configuration is kept. It returns when ctx is canceled.
+func watchReloadSignal(ctx context.Context, agent *gosightagent.Agent, configFlag *string) {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(sigs)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sigs:
+				utils.Info("SIGHUP received, reloading configuration")
+				cfg, err := bootstrap.ReloadAgentConfig(configFlag)
+				if err != nil {
+					utils.Error("config reload failed, keeping current configuration: %v", err)
+					continue
+				}
+				bootstrap.SetupLogging(cfg)
+				agent.ApplyConfig(cfg)
+			}
+		}
+	}()
+}
@@ -0,0 +1,38 @@
+//go:build windows
+
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// cmd/signal_windows.go - SIGUSR1 and SIGHUP do not exist on Windows.
+
+package main
+
+import (
+	"context"
+
+	gosightagent "github.com/aaronlmathis/gosight-agent/internal/agent"
+)
+
+// watchDumpSignal is a no-op on Windows, which has no SIGUSR1.
+func watchDumpSignal(ctx context.Context, agent *gosightagent.Agent) {}
+
+// watchReloadSignal is a no-op on Windows, which has no SIGHUP.
+func watchReloadSignal(ctx context.Context, agent *gosightagent.Agent, configFlag *string) {}
@@ -32,21 +32,25 @@ package gosightagent
 import (
 	"context"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/aaronlmathis/gosight-agent/internal/config"
 	grpcconn "github.com/aaronlmathis/gosight-agent/internal/grpc"
 	agentidentity "github.com/aaronlmathis/gosight-agent/internal/identity"
 	"github.com/aaronlmathis/gosight-agent/internal/logs/logrunner"
 	"github.com/aaronlmathis/gosight-agent/internal/meta"
+	"github.com/aaronlmathis/gosight-agent/internal/metrics/metriccollector/custom"
 	metricrunner "github.com/aaronlmathis/gosight-agent/internal/metrics/metricrunner"
 	"github.com/aaronlmathis/gosight-agent/internal/processes/processrunner"
+	"github.com/aaronlmathis/gosight-agent/internal/traces/tracerunner"
 	"github.com/aaronlmathis/gosight-shared/model"
 	"github.com/aaronlmathis/gosight-shared/utils"
 )
 
 // Agent is a struct that represents the GoSight agent.
 // It contains the configuration, metric runner, log runner, process runner,
-// agent ID, agent version, and metadata.
+// trace runner, agent ID, agent version, and metadata.
 type Agent struct {
 	Config        *config.Config
 	MetricRunner  *metricrunner.MetricRunner
@@ -54,10 +58,22 @@ type Agent struct {
 	AgentVersion  string
 	LogRunner     *logrunner.LogRunner
 	ProcessRunner *processrunner.ProcessRunner
+	TraceRunner   *tracerunner.TraceRunner
 	Meta          *model.Meta
 	Ctx           context.Context
+
+	// The senders run on their own context so they can still export queued
+	// batches after ctx is cancelled; Close stops them once the runners
+	// have drained (see shutdown_drain_timeout)
+	stopSenders context.CancelFunc
+	runWg       sync.WaitGroup
 }
 
+// drainGrace is how long Close waits for the runners beyond the configured
+// shutdown_drain_timeout, covering the final log collection and the export
+// that is in flight when the queues empty.
+const drainGrace = 5 * time.Second
+
 // NewAgent creates a new instance of the GoSight agent.
 // It initializes the agent with the provided configuration, context, and agent version.
 // It retrieves the agent ID and builds the base metadata for the agent.
@@ -66,28 +82,44 @@ type Agent struct {
 func NewAgent(ctx context.Context, cfg *config.Config, agentVersion string) (*Agent, error) {
 
 	// Retrieve (or set) the agent ID
-	agentID, err := agentidentity.LoadOrCreateAgentID()
+	agentID, err := agentidentity.LoadOrCreateAgentID(cfg.Agent.RegenerateIDOnHostChange)
 	if err != nil {
 		utils.Fatal("Failed to get agent ID: %v", err)
 	}
 
+	// Maintenance mode is applied to every payload's meta
+	meta.ConfigureMaintenance(cfg)
+
 	// Build base metadata for the agent and cache it in the Agent struct
 	baseMeta := meta.BuildMeta(cfg, nil, agentID, agentVersion)
 
-	metricRunner, err := metricrunner.NewRunner(ctx, cfg, baseMeta)
+	sendCtx, stopSenders := context.WithCancel(context.WithoutCancel(ctx))
+
+	metricRunner, err := metricrunner.NewRunner(sendCtx, cfg, baseMeta)
 	if err != nil {
+		stopSenders()
 		return nil, fmt.Errorf("failed to create metric runner: %v", err)
 	}
-	logRunner, err := logrunner.NewRunner(ctx, cfg, baseMeta)
+	logRunner, err := logrunner.NewRunner(sendCtx, cfg, baseMeta)
 	if err != nil {
+		stopSenders()
 		return nil, fmt.Errorf("failed to create log runner: %v", err)
 	}
 
-	processRunner, err := processrunner.NewRunner(ctx, cfg, baseMeta)
+	processRunner, err := processrunner.NewRunner(sendCtx, cfg, baseMeta)
 	if err != nil {
+		stopSenders()
 		return nil, fmt.Errorf("failed to create process runner: %v", err)
 	}
 
+	traceRunner, err := tracerunner.NewRunner(sendCtx, cfg, baseMeta)
+	if err != nil {
+		stopSenders()
+		return nil, fmt.Errorf("failed to create trace runner: %v", err)
+	}
+	// Spans pushed to the otlp_socket source are exported by the trace runner
+	custom.SetSpanSink(traceRunner.Submit)
+
 	return &Agent{
 		Ctx:           ctx,
 		Config:        cfg,
@@ -96,11 +128,13 @@ func NewAgent(ctx context.Context, cfg *config.Config, agentVersion string) (*Ag
 		AgentVersion:  agentVersion,
 		LogRunner:     logRunner,
 		ProcessRunner: processRunner,
+		TraceRunner:   traceRunner,
 		Meta:          baseMeta,
+		stopSenders:   stopSenders,
 	}, nil
 }
 
-// Start initializes and starts the metric, log, and process runners.
+// Start initializes and starts the metric, log, process, and trace runners.
 // It runs each runner in a separate goroutine.
 // The context is used to manage the lifecycle of the runners.
 // The function logs the start of each runner and handles any errors that may occur.
@@ -108,23 +142,92 @@ func (a *Agent) Start(ctx context.Context) {
 
 	// Start runner.
 	utils.Debug("Agent attempting to start metricrunner.")
-	go a.MetricRunner.Run(ctx)
+	a.run(func() { a.MetricRunner.Run(ctx) })
 
 	utils.Debug("Agent attempting to start metricrunner.")
-	go a.LogRunner.Run(ctx)
+	a.run(func() { a.LogRunner.Run(ctx) })
 
 	utils.Debug("Agent attempting to start processrunner.")
-	go a.ProcessRunner.Run(ctx)
+	a.run(func() { a.ProcessRunner.Run(ctx) })
+
+	utils.Debug("Agent attempting to start tracerunner.")
+	a.run(func() { a.TraceRunner.Run(ctx) })
+
+}
 
+// run starts a runner's Run loop in a goroutine tracked by Close.
+func (a *Agent) run(fn func()) {
+	a.runWg.Add(1)
+	go func() {
+		defer a.runWg.Done()
+		fn()
+	}()
+}
+
+// waitRunners waits for the Run loops to return, which they do once their
+// queues are drained after the run context is cancelled, but no longer than
+// deadline.
+func (a *Agent) waitRunners(deadline time.Time) {
+	done := make(chan struct{})
+	go func() {
+		a.runWg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Until(deadline)):
+		utils.Warn("Runners did not finish draining in time; closing connections anyway")
+	}
+}
+
+// waitSenders waits, no longer than deadline, for the sender workers to
+// finish the exports they are in. The runners stop the worker pools once
+// their queues are empty, but a batch taken off the queue just before is
+// still being exported and would be lost if the senders were stopped.
+func (a *Agent) waitSenders(deadline time.Time) {
+	type waiter interface{ Wait(time.Duration) bool }
+	var names []string
+	var senders []waiter
+	if a.MetricRunner != nil && a.MetricRunner.MetricSender != nil {
+		names, senders = append(names, "metric"), append(senders, a.MetricRunner.MetricSender)
+	}
+	if a.LogRunner != nil && a.LogRunner.LogSender != nil {
+		names, senders = append(names, "log"), append(senders, a.LogRunner.LogSender)
+	}
+	if a.ProcessRunner != nil && a.ProcessRunner.ProcessSender != nil {
+		names, senders = append(names, "process"), append(senders, a.ProcessRunner.ProcessSender)
+	}
+	if a.TraceRunner != nil && a.TraceRunner.TraceSender != nil {
+		names, senders = append(names, "trace"), append(senders, a.TraceRunner.TraceSender)
+	}
+
+	for i, s := range senders {
+		if !s.Wait(time.Until(deadline)) {
+			utils.Warn("%s sender workers did not finish exporting in time; closing connections anyway", names[i])
+		}
+	}
 }
 
 // Close stops all runners and closes the gRPC connection.
 // It waits for all runners to finish before closing the connection.
 func (a *Agent) Close() {
+	// Let the runners flush their queues and the senders finish the exports
+	// in flight, then stop the senders
+	config.RLock()
+	deadline := time.Now().Add(a.Config.Agent.ShutdownDrainTimeout + drainGrace)
+	config.RUnlock()
+	a.waitRunners(deadline)
+	a.waitSenders(deadline)
+	if a.stopSenders != nil {
+		a.stopSenders()
+	}
+
 	// Stop All Runners
 	a.MetricRunner.Close()
 	a.LogRunner.Close()
 	a.ProcessRunner.Close()
+	a.TraceRunner.Close()
 
 	err := grpcconn.CloseGRPCConn()
 	if err != nil {
@@ -30,12 +30,16 @@ along with GoSight. If not, see https://www.gnu.org/licenses/.
 package gosightagent
 
 import (
+	"bytes"
 	"context"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/aaronlmathis/gosight-agent/internal/config"
 	"github.com/aaronlmathis/gosight-agent/internal/logs/logrunner"
+	"github.com/aaronlmathis/gosight-agent/internal/metrics/metriccollector"
 	metricrunner "github.com/aaronlmathis/gosight-agent/internal/metrics/metricrunner"
 	"github.com/aaronlmathis/gosight-agent/internal/processes/processrunner"
 	"github.com/aaronlmathis/gosight-shared/model"
@@ -140,3 +144,40 @@ func TestAgent_Close(t *testing.T) {
 		})
 	}
 }
+
+// countingCollector counts calls to Collect.
+type countingCollector struct{ calls int }
+
+func (c *countingCollector) Name() string { return "counting" }
+
+func (c *countingCollector) Collect(_ context.Context) ([]model.Metric, error) {
+	c.calls++
+	return []model.Metric{{Namespace: "Test", Name: "calls", Value: float64(c.calls)}}, nil
+}
+
+func TestWriteSnapshotUsesLastCycle(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Agent.MetricCollection.Interval = 10 * time.Second
+
+	reg, err := metriccollector.NewRegistry(cfg)
+	if err != nil {
+		t.Fatalf("NewRegistry: %v", err)
+	}
+	counting := &countingCollector{}
+	reg.Collectors = map[string]metriccollector.MetricCollector{"counting": counting}
+	if _, err := reg.Collect(context.Background()); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+
+	a := &Agent{Config: cfg, MetricRunner: &metricrunner.MetricRunner{MetricRegistry: reg}}
+	var buf bytes.Buffer
+	if err := a.WriteSnapshot(context.Background(), &buf); err != nil {
+		t.Fatalf("WriteSnapshot: %v", err)
+	}
+	if counting.calls != 1 {
+		t.Errorf("expected the snapshot not to call the collector, got %d calls", counting.calls)
+	}
+	if !strings.Contains(buf.String(), "test..calls") {
+		t.Errorf("expected the last cycle's metric in the snapshot, got:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,158 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/agent/dump.go
+
+package gosightagent
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aaronlmathis/gosight-agent/internal/processes/processcollector"
+	"github.com/aaronlmathis/gosight-shared/model"
+	"github.com/aaronlmathis/gosight-shared/utils"
+)
+
+// DumpSnapshot writes each metric collector's result from the most recent
+// collection cycle and a one-shot process collection to the configured dump
+// file (agent.dump_file) or to stderr if none is set. Nothing is sent to the
+// server. The metric collectors are not called: an extra collection would
+// race the runner's cycle, shift rate windows and drain buffered sources.
+// Log collectors are skipped for the same reason.
+func (a *Agent) DumpSnapshot(ctx context.Context) error {
+	var w io.Writer = os.Stderr
+
+	if path := a.Config.Agent.DumpFile; path != "" {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to open dump file %s: %w", path, err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	return a.WriteSnapshot(ctx, w)
+}
+
+// WriteSnapshot pretty-prints the last cycle's metrics and the current
+// processes to w.
+func (a *Agent) WriteSnapshot(ctx context.Context, w io.Writer) error {
+	fmt.Fprintf(w, "=== GoSight agent snapshot %s ===\n", time.Now().Format(time.RFC3339))
+	fmt.Fprintf(w, "agent_id: %s\nversion:  %s\n", a.AgentID, a.AgentVersion)
+	if a.Meta != nil {
+		fmt.Fprintf(w, "hostname: %s\nhost_id:  %s\n", a.Meta.Hostname, a.Meta.HostID)
+	}
+
+	if a.MetricRunner != nil && a.MetricRunner.MetricRegistry != nil {
+		registry := a.MetricRunner.MetricRegistry
+		results, errs := registry.LastCollected()
+		active := registry.Active()
+		names := make([]string, 0, len(active))
+		for name := range active {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			fmt.Fprintf(w, "\n--- collector: %s ---\n", name)
+			if err := errs[name]; err != nil {
+				fmt.Fprintf(w, "error: %v\n", err)
+				continue
+			}
+			metrics, ok := results[name]
+			if !ok {
+				fmt.Fprintf(w, "not collected yet\n")
+				continue
+			}
+			writeMetrics(w, metrics)
+		}
+	}
+
+	fmt.Fprintf(w, "\n--- processes ---\n")
+	snapshot, err := processcollector.CollectProcesses(ctx, a.Config)
+	if err != nil {
+		fmt.Fprintf(w, "error: %v\n", err)
+	} else {
+		writeProcesses(w, snapshot.Processes)
+	}
+
+	return nil
+}
+
+// writeMetrics prints one metric per line, sorted by name, with its dimensions.
+func writeMetrics(w io.Writer, metrics []model.Metric) {
+	sort.SliceStable(metrics, func(i, j int) bool {
+		return fullMetricName(metrics[i]) < fullMetricName(metrics[j])
+	})
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, m := range metrics {
+		fmt.Fprintf(tw, "%s\t%g %s\t%s\n", fullMetricName(m), m.Value, m.Unit, formatDims(m.Dimensions))
+	}
+	if err := tw.Flush(); err != nil {
+		utils.Debug("failed to flush snapshot writer: %v", err)
+	}
+}
+
+// writeProcesses prints a process table.
+func writeProcesses(w io.Writer, procs []model.ProcessInfo) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "PID\tPPID\tUSER\tCPU%\tMEM%\tTHREADS\tCMD")
+	for _, p := range procs {
+		cmd := p.Cmdline
+		if cmd == "" {
+			cmd = p.Executable
+		}
+		fmt.Fprintf(tw, "%d\t%d\t%s\t%.1f\t%.1f\t%d\t%s\n",
+			p.PID, p.PPID, p.User, p.CPUPercent, p.MemPercent, p.Threads, utils.Truncate(cmd, 120))
+	}
+	if err := tw.Flush(); err != nil {
+		utils.Debug("failed to flush snapshot writer: %v", err)
+	}
+}
+
+func fullMetricName(m model.Metric) string {
+	return strings.ToLower(m.Namespace + "." + m.SubNamespace + "." + m.Name)
+}
+
+func formatDims(dims map[string]string) string {
+	if len(dims) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(dims))
+	for k := range dims {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, k+"="+dims[k])
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
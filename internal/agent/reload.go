@@ -0,0 +1,122 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/agent/reload.go
+
+package gosightagent
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aaronlmathis/gosight-agent/internal/config"
+	grpcconn "github.com/aaronlmathis/gosight-agent/internal/grpc"
+	"github.com/aaronlmathis/gosight-agent/internal/meta"
+	"github.com/aaronlmathis/gosight-shared/model"
+	"github.com/aaronlmathis/gosight-shared/utils"
+)
+
+// ApplyConfig swaps in a newly loaded configuration. The config is updated in
+// place, under config.Update, so runners and senders holding the
+// *config.Config see the new values.
+// Enabled metric and log collectors are diffed against the running ones (see
+// MetricRegistry.Reload and LogRegistry.Reload) and custom tags are re-applied
+// to the base meta, so payloads from the next cycle carry them.
+// The log redaction rules are rebuilt as well, so new patterns apply from
+// the next log cycle.
+// The shared gRPC connection is only re-dialed when a connection-relevant
+// setting (server URL, proxy, TLS files) changed, so routine pushes of
+// intervals or tags do not cause connection blips.
+//
+// Every changed setting is reported as a config_changed log event so hosts
+// keep an audit trail of their configuration (see configChangeEntry).
+func (a *Agent) ApplyConfig(newCfg *config.Config) {
+	var changes []string
+	var diff map[string]string
+
+	// Runner cycles and senders read the config and base meta under the
+	// read lock, so they see either the old or the new values
+	config.Update(func() {
+		changes = config.ConnectionChanges(a.Config, newCfg)
+		diff = config.Changes(a.Config, newCfg)
+
+		*a.Config = *newCfg
+		meta.ConfigureMaintenance(a.Config)
+
+		if a.MetricRunner != nil && a.MetricRunner.MetricRegistry != nil {
+			if err := a.MetricRunner.MetricRegistry.Reload(a.Config); err != nil {
+				utils.Error("Metric collectors not reloaded, keeping the running set: %v", err)
+			}
+		}
+		if a.LogRunner != nil && a.LogRunner.LogRegistry != nil {
+			a.LogRunner.LogRegistry.Reload(a.Config)
+		}
+		if a.LogRunner != nil {
+			if err := a.LogRunner.ReloadRedactor(a.Config); err != nil {
+				utils.Error("Log redaction not reloaded, keeping the running rules: %v", err)
+			}
+		}
+		if a.Meta != nil {
+			a.Meta.Tags = meta.BuildMeta(a.Config, nil, a.AgentID, a.AgentVersion).Tags
+		}
+	})
+
+	if len(diff) > 0 && a.LogRunner != nil {
+		a.LogRunner.Emit(configChangeEntry(diff, time.Now()))
+	}
+
+	if len(changes) == 0 {
+		utils.Info("Config applied; connection settings unchanged, keeping gRPC connection")
+		return
+	}
+	utils.Info("Connection settings changed (%s); re-dialing server", strings.Join(changes, ", "))
+	grpcconn.Redial()
+}
+
+// configChangeEntry describes a configuration change as a log entry. Fields
+// holds "changed_keys" (comma-separated) and one "old -> new" entry per
+// changed setting, with secret values redacted.
+func configChangeEntry(diff map[string]string, ts time.Time) model.LogEntry {
+	keys := config.ChangedKeys(diff)
+
+	fields := make(map[string]string, len(diff)+1)
+	for k, v := range diff {
+		fields[k] = v
+	}
+	fields["changed_keys"] = strings.Join(keys, ",")
+
+	return model.LogEntry{
+		Timestamp: ts,
+		Level:     "info",
+		Message:   fmt.Sprintf("Agent configuration changed: %s", strings.Join(keys, ", ")),
+		Source:    "gosight-agent",
+		Category:  "gosight",
+		Fields:    fields,
+		Labels: map[string]string{
+			"event": "config_changed",
+		},
+		Meta: &model.LogMeta{
+			Platform: "gosight",
+			AppName:  "gosight-agent",
+		},
+	}
+}
@@ -0,0 +1,104 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+package gosightagent
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aaronlmathis/gosight-agent/internal/config"
+	"github.com/aaronlmathis/gosight-agent/internal/logs/logrunner"
+	"github.com/aaronlmathis/gosight-agent/internal/meta"
+	"github.com/aaronlmathis/gosight-agent/internal/metrics/metricrunner"
+	"github.com/aaronlmathis/gosight-shared/model"
+)
+
+// TestApplyConfigWhileRunning reloads the config while the metric runner
+// cycles and its senders convert payloads. Run with -race.
+func TestApplyConfigWhileRunning(t *testing.T) {
+	newCfg := func(i int) *config.Config {
+		cfg := &config.Config{}
+		cfg.Agent.Sink = "stdout"
+		cfg.Agent.MetricCollection.Interval = 10 * time.Millisecond
+		cfg.Agent.MetricCollection.Workers = 1
+		cfg.Agent.MetricCollection.Sources = []string{"mem"}
+		cfg.CustomTags = map[string]string{"reload": fmt.Sprint(i)}
+		cfg.Agent.ResourceAttributeMapping = map[string]string{"reload": "gosight.reload"}
+		return cfg
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cfg := newCfg(0)
+	baseMeta := meta.BuildMeta(cfg, nil, "test-agent", "test")
+	runner, err := metricrunner.NewRunner(ctx, cfg, baseMeta)
+	if err != nil {
+		t.Fatalf("NewRunner: %v", err)
+	}
+	a := &Agent{Config: cfg, MetricRunner: runner, Meta: baseMeta, AgentID: "test-agent", AgentVersion: "test"}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		runner.Run(ctx)
+	}()
+
+	for i := 1; i <= 20; i++ {
+		a.ApplyConfig(newCfg(i))
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	if got := a.Meta.Tags["reload"]; got != "20" {
+		t.Errorf("expected the last reload's tags, got reload=%q", got)
+	}
+}
+
+// TestApplyConfigReloadsRedactor checks that redaction patterns added by a
+// reload apply to the next log cycle, and that an invalid pattern keeps the
+// running rules.
+func TestApplyConfigReloadsRedactor(t *testing.T) {
+	cfg := &config.Config{}
+	a := &Agent{Config: cfg, LogRunner: &logrunner.LogRunner{Config: cfg}}
+
+	newCfg := &config.Config{}
+	newCfg.Agent.LogCollection.Redact.Patterns = []config.RedactPattern{{Name: "pin", Regex: `pin=\d+`, Replacement: "pin=***"}}
+	a.ApplyConfig(newCfg)
+
+	entry := model.LogEntry{Message: "login pin=1234"}
+	a.LogRunner.Redactor.Entry(&entry)
+	if entry.Message != "login pin=***" {
+		t.Fatalf("expected the reloaded pattern to apply, got %q", entry.Message)
+	}
+
+	badCfg := &config.Config{}
+	badCfg.Agent.LogCollection.Redact.Patterns = []config.RedactPattern{{Name: "bad", Regex: "("}}
+	a.ApplyConfig(badCfg)
+
+	entry = model.LogEntry{Message: "login pin=5678"}
+	a.LogRunner.Redactor.Entry(&entry)
+	if entry.Message != "login pin=***" {
+		t.Fatalf("expected the running rules to be kept, got %q", entry.Message)
+	}
+}
@@ -33,10 +33,25 @@ import (
 	"github.com/aaronlmathis/gosight-shared/utils"
 )
 
+// SinkOverride, when set (e.g. "stdout" from the --stdout flag), replaces
+// agent.sink from the file and environment on every load.
+var SinkOverride string
+
 // LoadAgentConfig loads the agent configuration from a file, environment variables, and command-line flags.
 // It applies the overrides in the following order: command-line flags > environment variables > config file.
-// The function returns a pointer to the loaded configuration.
+// The function returns a pointer to the loaded configuration, and exits listing every problem if it is invalid.
 func LoadAgentConfig(configFlag *string) *config.Config {
+	cfg, err := ReloadAgentConfig(configFlag)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	return cfg
+}
+
+// ReloadAgentConfig loads and validates the configuration exactly like
+// LoadAgentConfig but returns an error instead of exiting, so a running agent
+// (e.g. on SIGHUP) can keep its current configuration when the new one is invalid.
+func ReloadAgentConfig(configFlag *string) (*config.Config, error) {
 
 	// Resolve config path
 	configPath := resolvePath(*configFlag, "GOSIGHT_AGENT_CONFIG", "./config/config.yaml")
@@ -44,12 +59,20 @@ func LoadAgentConfig(configFlag *string) *config.Config {
 
 	cfg, err := config.LoadConfig(configPath)
 	if err != nil {
-		log.Fatalf("Failed to load config: %v", err)
+		return nil, err
 	}
 
 	config.ApplyEnvOverrides(cfg)
+	if SinkOverride != "" {
+		cfg.Agent.Sink = SinkOverride
+	}
 
-	return cfg
+	// Validate after overrides so env-provided values are checked too
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
 }
 
 // resolvePath resolves the path for a given flag value, environment variable, and fallback value.
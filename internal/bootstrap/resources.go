@@ -0,0 +1,59 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// agent/internal/bootstrap/resources.go
+// Applies agent footprint limits (GOMAXPROCS, CPU affinity, nice).
+package bootstrap
+
+import (
+	"runtime"
+
+	"github.com/aaronlmathis/gosight-agent/internal/config"
+	"github.com/aaronlmathis/gosight-shared/utils"
+)
+
+// ApplyResourceLimits caps the agent's own CPU footprint as configured under
+// agent.resources, so it does not contend with the workload it monitors.
+// Failures are logged and the agent continues with its defaults.
+func ApplyResourceLimits(cfg *config.Config) {
+	res := cfg.Agent.Resources
+
+	if res.GOMAXPROCS > 0 {
+		prev := runtime.GOMAXPROCS(res.GOMAXPROCS)
+		utils.Info("GOMAXPROCS set to %d (was %d)", res.GOMAXPROCS, prev)
+	}
+
+	if len(res.CPUAffinity) > 0 {
+		if err := setCPUAffinity(res.CPUAffinity); err != nil {
+			utils.Warn("Failed to set CPU affinity to %v: %v", res.CPUAffinity, err)
+		} else {
+			utils.Info("CPU affinity set to %v", res.CPUAffinity)
+		}
+	}
+
+	if res.Nice != 0 {
+		if err := setNice(res.Nice); err != nil {
+			utils.Warn("Failed to set nice level %d: %v", res.Nice, err)
+		} else {
+			utils.Info("Nice level set to %d", res.Nice)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+//go:build linux
+
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// agent/internal/bootstrap/resources_linux.go
+package bootstrap
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"golang.org/x/sys/unix"
+)
+
+// On Linux both the affinity mask and the nice value are per thread, so they
+// are applied to every existing thread of the process. Threads the Go runtime
+// starts later are cloned from these and inherit the settings.
+
+// setCPUAffinity pins the agent to the given CPU numbers.
+func setCPUAffinity(cpus []int) error {
+	var set unix.CPUSet
+	for _, c := range cpus {
+		if c < 0 || c >= len(set)*64 {
+			return fmt.Errorf("invalid CPU %d", c)
+		}
+		set.Set(c)
+	}
+	return forEachThread(func(tid int) error {
+		return unix.SchedSetaffinity(tid, &set)
+	})
+}
+
+// setNice sets the agent's scheduling priority (-20..19; raising it is
+// always allowed, lowering it needs CAP_SYS_NICE).
+func setNice(nice int) error {
+	return forEachThread(func(tid int) error {
+		return unix.Setpriority(unix.PRIO_PROCESS, tid, nice)
+	})
+}
+
+// forEachThread calls fn with the ID of each thread of the current process.
+func forEachThread(fn func(tid int) error) error {
+	entries, err := os.ReadDir("/proc/self/task")
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		tid, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+		// A thread may exit while we iterate
+		if err := fn(tid); err != nil && err != unix.ESRCH {
+			return err
+		}
+	}
+	return nil
+}
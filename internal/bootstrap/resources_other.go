@@ -0,0 +1,40 @@
+//go:build !linux
+
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// agent/internal/bootstrap/resources_other.go
+package bootstrap
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// setCPUAffinity is only implemented on Linux.
+func setCPUAffinity(cpus []int) error {
+	return fmt.Errorf("CPU affinity is not supported on %s", runtime.GOOS)
+}
+
+// setNice is only implemented on Linux.
+func setNice(nice int) error {
+	return fmt.Errorf("nice level is not supported on %s", runtime.GOOS)
+}
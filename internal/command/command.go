@@ -31,8 +31,9 @@ import (
 )
 
 // HandleCommand processes incoming command requests based on their type.
-// It supports "shell" commands for executing shell commands and "ansible"
-// commands for running Ansible playbooks.
+// It supports "shell" commands for executing shell commands, "ansible"
+// commands for running Ansible playbooks and "maintenance" commands for
+// toggling maintenance mode.
 func HandleCommand(ctx context.Context, cmd *proto.CommandRequest) *proto.CommandResponse {
 
 	switch cmd.CommandType {
@@ -40,6 +41,8 @@ func HandleCommand(ctx context.Context, cmd *proto.CommandRequest) *proto.Comman
 		return runShellCommand(ctx, cmd.Command, cmd.Args...)
 	case "ansible":
 		return runAnsiblePlaybook(ctx, cmd.Command)
+	case "maintenance":
+		return runMaintenance(cmd.Command, cmd.Args...)
 
 	default:
 		utils.Warn("Unknown command type: %s", cmd.CommandType)
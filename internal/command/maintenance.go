@@ -0,0 +1,72 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// agent/internal/command/maintenance.go
+
+package command
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aaronlmathis/gosight-agent/internal/meta"
+	"github.com/aaronlmathis/gosight-shared/proto"
+	"github.com/aaronlmathis/gosight-shared/utils"
+)
+
+// runMaintenance handles the "maintenance" command. Command is "on", "off" or
+// "status"; "on" takes an optional duration argument (e.g. "2h"), otherwise
+// agent.maintenance.default_duration applies (zero meaning until "off").
+func runMaintenance(action string, args ...string) *proto.CommandResponse {
+	switch action {
+	case "on":
+		var d time.Duration
+		if len(args) > 0 {
+			var err error
+			d, err = time.ParseDuration(args[0])
+			if err != nil || d < 0 {
+				return &proto.CommandResponse{Success: false, ErrorMessage: fmt.Sprintf("invalid maintenance duration %q", args[0])}
+			}
+		}
+		until := meta.StartMaintenance(d)
+		if until.IsZero() {
+			utils.Info("Maintenance mode on until turned off")
+			return &proto.CommandResponse{Success: true, Output: "maintenance mode on until turned off"}
+		}
+		utils.Info("Maintenance mode on until %s", until.Format(time.RFC3339))
+		return &proto.CommandResponse{Success: true, Output: "maintenance mode on until " + until.Format(time.RFC3339)}
+	case "off":
+		meta.StopMaintenance()
+		utils.Info("Maintenance mode off")
+		out := "maintenance mode off"
+		if meta.InMaintenance(time.Now()) {
+			out += " (maintenance file still present)"
+		}
+		return &proto.CommandResponse{Success: true, Output: out}
+	case "status":
+		if meta.InMaintenance(time.Now()) {
+			return &proto.CommandResponse{Success: true, Output: "maintenance mode on"}
+		}
+		return &proto.CommandResponse{Success: true, Output: "maintenance mode off"}
+	default:
+		return &proto.CommandResponse{Success: false, ErrorMessage: fmt.Sprintf("unknown maintenance action %q (want on, off or status)", action)}
+	}
+}
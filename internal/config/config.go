@@ -41,14 +41,109 @@ import (
 // batch size, buffer size, number of workers, and maximum message size.
 
 type LogCollectionConfig struct {
-	Interval    time.Duration     `yaml:"interval"`
-	Sources     []string          `yaml:"sources"`
-	Services    []string          `yaml:"services"`
-	BatchSize   int               `yaml:"batch_size"`
-	BufferSize  int               `yaml:"buffer_size"`
-	Workers     int               `yaml:"workers"`
-	MessageMax  int               `yaml:"message_max"`
-	EventViewer EventViewerConfig `yaml:"eventviewer"`
+	Interval    time.Duration        `yaml:"interval"`
+	Sources     []string             `yaml:"sources"`
+	Services    []string             `yaml:"services"`
+	BatchSize   int                  `yaml:"batch_size"`
+	BufferSize  int                  `yaml:"buffer_size"`
+	Workers     int                  `yaml:"workers"`
+	MessageMax  int                  `yaml:"message_max"`
+	EventViewer EventViewerConfig    `yaml:"eventviewer"`
+	Files       FileLogConfig        `yaml:"files"`
+	Journald    JournaldConfig       `yaml:"journald"`
+	Audit       AuditLogConfig       `yaml:"audit"`
+	Repeat      RepeatCollapseConfig `yaml:"repeat"`
+	Listeners   ListenerLogConfig    `yaml:"listeners"`
+	Redact      RedactConfig         `yaml:"redact"`
+
+	// ImmediateLevel ships entries at or above this level (e.g. "error")
+	// within ImmediateCheckInterval (default 1s) in their own batches instead
+	// of at the next Interval. Empty = disabled.
+	ImmediateLevel         string        `yaml:"immediate_level"`
+	ImmediateCheckInterval time.Duration `yaml:"immediate_check_interval"`
+}
+
+// RedactConfig masks secrets in log entries before they are written to the
+// audit log or shipped. Patterns are applied to the message, to the Fields
+// listed (or all fields with "*") and to the raw event XML of eventviewer
+// entries. Builtin (default true) adds bearer tokens, URL passwords,
+// password/token assignments, AWS access keys, emails and card numbers
+// ahead of Patterns.
+type RedactConfig struct {
+	Builtin  bool            `yaml:"builtin"`
+	Patterns []RedactPattern `yaml:"patterns"`
+	Fields   []string        `yaml:"fields"`
+}
+
+// RedactPattern replaces every match of Regex with Replacement, which may
+// refer to capture groups ($1, ${name}). Replacement defaults to "[REDACTED]".
+type RedactPattern struct {
+	Name        string `yaml:"name"`
+	Regex       string `yaml:"regex"`
+	Replacement string `yaml:"replacement"`
+}
+
+// ListenerLogConfig configures the listeners source, which rescans the host's
+// TCP/UDP listening sockets every ScanInterval (default 1m) and logs every
+// listener that appeared or disappeared since the previous scan.
+type ListenerLogConfig struct {
+	ScanInterval time.Duration `yaml:"scan_interval"`
+}
+
+// RepeatCollapseConfig collapses identical consecutive lines from the listed
+// sources (journald, security, kmsg, file) into one entry with a repeat_count
+// field. A run is reported after Window (default 5s) or MaxCount repeats
+// (default 1000), whichever comes first, or as soon as a different line arrives.
+type RepeatCollapseConfig struct {
+	Sources  []string      `yaml:"sources"`
+	Window   time.Duration `yaml:"window"`
+	MaxCount int           `yaml:"max_count"`
+}
+
+// AuditLogConfig keeps a local, rotated copy of the listed log sources
+// (e.g. "security") in addition to shipping them, whether or not the server
+// is reachable. MaxSizeMB defaults to 100 and MaxBackups to 10; MaxAge 0
+// keeps rotated files until MaxBackups is exceeded.
+type AuditLogConfig struct {
+	Sources    []string      `yaml:"sources"`
+	Path       string        `yaml:"path"`
+	MaxSizeMB  int           `yaml:"max_size_mb"`
+	MaxBackups int           `yaml:"max_backups"`
+	MaxAge     time.Duration `yaml:"max_age"`
+}
+
+// JournaldConfig defines journald-specific options.
+// Escalate maps a unit name or glob (matched against the systemd unit and
+// the syslog identifier, e.g. "sshd.service", "audit*") to an escalation.
+// CursorFile, if set, stores the position of the last entry read so a
+// restart resumes there instead of at the end of the journal.
+// Priorities (0=emerg .. 7=debug, default 0-4) and Units (_SYSTEMD_UNIT
+// values, any of which may match; empty = all units) select the entries read.
+// Kernel messages are dropped unless IncludeKernel is set.
+type JournaldConfig struct {
+	Escalate      map[string]LogEscalation `yaml:"escalate"`
+	CursorFile    string                   `yaml:"cursor_file"`
+	Priorities    []int                    `yaml:"priorities"`
+	Units         []string                 `yaml:"units"`
+	IncludeKernel bool                     `yaml:"include_kernel"`
+}
+
+// LogEscalation forces the level of matching log entries (default "error")
+// and adds labels to them (default critical: "true").
+type LogEscalation struct {
+	Level string            `yaml:"level"`
+	Tags  map[string]string `yaml:"tags"`
+}
+
+// FileLogConfig defines the configuration for the "file" log source.
+// Paths are glob patterns that are re-evaluated every DiscoveryInterval, so
+// matching files created later are tailed and removed files are released.
+// When StateFile is set, per-file read offsets are saved there so a restart
+// resumes each file where it left off instead of at its end.
+type FileLogConfig struct {
+	Paths             []string      `yaml:"paths"`
+	DiscoveryInterval time.Duration `yaml:"discovery_interval"`
+	StateFile         string        `yaml:"state_file"`
 }
 
 // EventViewerConfig defines the configuration for Windows Event Log collection
@@ -56,6 +151,7 @@ type EventViewerConfig struct {
 	CollectAll      bool     `yaml:"collect_all"`      // Whether to collect from all available channels
 	Channels        []string `yaml:"channels"`         // List of channels to collect from if CollectAll is false
 	ExcludeChannels []string `yaml:"exclude_channels"` // Channels to explicitly exclude
+	IncludeRawXML   bool     `yaml:"include_raw_xml"`  // Keep the rendered event XML in Meta.Extra["raw_xml"]
 }
 
 // MetricCollectionConfig defines the configuration for metric collection
@@ -65,6 +161,259 @@ type MetricCollectionConfig struct {
 	Interval time.Duration `yaml:"interval"`
 	Sources  []string      `yaml:"sources"`
 	Workers  int           `yaml:"workers"`
+
+	// AlignTimestamps stamps every metric in a cycle with the tick time instead
+	// of the moment each collector ran. TimestampRound optionally truncates that
+	// time to a boundary: "second" or "interval" (empty = no rounding).
+	AlignTimestamps bool   `yaml:"align_timestamps"`
+	TimestampRound  string `yaml:"timestamp_round"`
+
+	// RateTimestamp stamps rate metrics (CPU percent, network rates) at the
+	// "start" or "end" (default) of the window they were measured over.
+	RateTimestamp string `yaml:"rate_timestamp"`
+
+	// CollectorIntervals overrides Interval per collector name (e.g. disk:
+	// 30s, cpu: 2s); collectors not listed use Interval.
+	CollectorIntervals map[string]time.Duration `yaml:"collector_intervals"`
+
+	// ClockStep selects how rates spanning a wall clock step are handled:
+	// "monotonic" (default) measures them over the monotonic interval,
+	// "reset" drops them.
+	ClockStep string `yaml:"clock_step"`
+
+	// CPUPerCoreMaxCores skips per-core CPU metrics on machines with more
+	// logical cores than this (0 = no limit); aggregates are still sent.
+	CPUPerCoreMaxCores int `yaml:"cpu_per_core_max_cores"`
+
+	// CPUDistinctTotalName emits total CPU usage as usage_percent_total so it
+	// no longer shares the usage_percent name with the per-core series.
+	CPUDistinctTotalName bool `yaml:"cpu_distinct_total_name"`
+
+	// StreamFallback sends metrics over the legacy command stream when OTLP
+	// export fails repeatedly, switching back once OTLP succeeds again.
+	StreamFallback bool `yaml:"stream_fallback"`
+
+	// DefaultDimensions are added to metrics that have no dimensions at all
+	// (e.g. scope: host) so they don't collide across scopes on the backend.
+	DefaultDimensions map[string]string `yaml:"default_dimensions"`
+
+	// SeriesFingerprint adds a series_id dimension holding a stable hash of
+	// each metric's name and dimensions, for backends that pre-aggregate.
+	SeriesFingerprint bool `yaml:"series_fingerprint"`
+
+	// ContainerLabelFields promotes container labels to Meta fields, keyed by
+	// label (e.g. com.company.team: service). Fields: service, application,
+	// environment, version, deployment_id. Other labels stay label.* dimensions.
+	ContainerLabelFields map[string]string `yaml:"container_label_fields"`
+
+	// FifoPath is the named pipe read by the "fifo" source.
+	FifoPath string `yaml:"fifo_path"`
+
+	// OTLPSocketPath is the unix socket the "otlp_socket" source serves the
+	// OTLP metrics gRPC service on.
+	OTLPSocketPath string `yaml:"otlp_socket_path"`
+
+	WindowsServices WindowsServicesConfig `yaml:"windows_services"`
+
+	// NTPDaemon selects the time daemon queried by the "ntp" source:
+	// "auto" (default), "chrony" or "ntpd".
+	NTPDaemon string `yaml:"ntp_daemon"`
+
+	Disk DiskConfig `yaml:"disk"`
+
+	SMART SMARTConfig `yaml:"smart"`
+
+	Prometheus PrometheusConfig `yaml:"prometheus"`
+
+	StatsD StatsDConfig `yaml:"statsd"`
+
+	Systemd SystemdConfig `yaml:"systemd"`
+
+	// Thresholds drop metrics whose value is in a "normal" band, so only
+	// abnormal values are shipped. The first matching filter applies.
+	Thresholds []ThresholdFilter `yaml:"thresholds"`
+
+	// Relabel rules drop metrics or rewrite their dimensions before they are
+	// split into host and container payloads. They run in order, each on
+	// the result of the previous one.
+	Relabel []RelabelRule `yaml:"relabel"`
+
+	// Critical enables an out-of-band fast path for a few critical metrics.
+	Critical CriticalMetricsConfig `yaml:"critical"`
+
+	// MaxSeries caps the number of distinct series (name + dimensions) sent
+	// per interval (0 = unlimited). New series beyond the cap are dropped and
+	// counted in agent.cardinality_dropped_total; known series keep flowing.
+	MaxSeries int `yaml:"max_series"`
+
+	// CollectorBackoff slows down collectors that keep failing.
+	CollectorBackoff CollectorBackoffConfig `yaml:"collector_backoff"`
+}
+
+// CollectorBackoffConfig moves a collector that fails FailureThreshold times
+// in a row (0 = never) to a probe cadence of ProbeInterval (default 1m) until
+// it succeeds again, after which it runs at the normal interval.
+type CollectorBackoffConfig struct {
+	FailureThreshold int           `yaml:"failure_threshold"`
+	ProbeInterval    time.Duration `yaml:"probe_interval"`
+}
+
+// CriticalMetricsConfig re-collects the metrics matched by Rules every
+// CheckInterval (default 5s) and sends a metric immediately when it crosses
+// or recovers from its threshold, instead of waiting for the next interval.
+// Only collectors that produced a matching metric in the last regular cycle
+// and keep no state between calls are re-run.
+type CriticalMetricsConfig struct {
+	CheckInterval time.Duration  `yaml:"check_interval"`
+	Rules         []CriticalRule `yaml:"rules"`
+}
+
+// CriticalRule marks a metric as crossed when its value is above Above or
+// below Below. Match is a glob like ThresholdFilter.Match.
+type CriticalRule struct {
+	Match string   `yaml:"match"`
+	Above *float64 `yaml:"above"`
+	Below *float64 `yaml:"below"`
+}
+
+// Crossed reports whether v is outside the rule's normal range.
+func (r CriticalRule) Crossed(v float64) bool {
+	return (r.Above != nil && v > *r.Above) || (r.Below != nil && v < *r.Below)
+}
+
+// RelabelRule is one metric relabeling step. Match is a glob like
+// ThresholdFilter.Match selecting the metrics the rule applies to (empty =
+// all). Action is one of:
+//   - "drop": drop the metric; with Regex, only if the value of Dimension
+//     (empty when missing) fully matches Regex.
+//   - "rename_dimension": rename Dimension to NewName.
+//   - "set_dimension": set Dimension to Value, adding it if missing.
+//   - "remove_dimension": delete Dimension.
+type RelabelRule struct {
+	Match     string `yaml:"match"`
+	Action    string `yaml:"action"`
+	Dimension string `yaml:"dimension"`
+	Regex     string `yaml:"regex"`
+	NewName   string `yaml:"new_name"`
+	Value     string `yaml:"value"`
+}
+
+// ThresholdFilter ignores values of matching metrics within [IgnoreMin, IgnoreMax].
+// Match is a glob over the lowercased "namespace.subnamespace.name"
+// (e.g. "system.disk.used_percent"). A missing bound is open-ended.
+type ThresholdFilter struct {
+	Match     string   `yaml:"match"`
+	IgnoreMin *float64 `yaml:"ignore_min"`
+	IgnoreMax *float64 `yaml:"ignore_max"`
+}
+
+// Ignores reports whether v falls inside the filter's ignore range.
+// A filter with neither bound set ignores nothing.
+func (f ThresholdFilter) Ignores(v float64) bool {
+	if f.IgnoreMin == nil && f.IgnoreMax == nil {
+		return false
+	}
+	if f.IgnoreMin != nil && v < *f.IgnoreMin {
+		return false
+	}
+	if f.IgnoreMax != nil && v > *f.IgnoreMax {
+		return false
+	}
+	return true
+}
+
+// WindowsServicesConfig selects the services reported by the "winservices" source.
+type WindowsServicesConfig struct {
+	Services     []string `yaml:"services"`       // Service names (not display names) to report
+	AllAutoStart bool     `yaml:"all_auto_start"` // Also report every automatic-start service
+}
+
+// DiskConfig filters the partitions reported by the "disk" source. A nil
+// exclude list uses the collector's defaults; an empty one excludes nothing.
+type DiskConfig struct {
+	ExcludeFSTypes     []string      `yaml:"exclude_fstypes"`     // fstype globs, default tmpfs, devtmpfs, overlay, squashfs
+	ExcludeMountpoints []string      `yaml:"exclude_mountpoints"` // mountpoint globs; also exclude everything mounted below
+	UsageTimeout       time.Duration `yaml:"usage_timeout"`       // per-partition usage timeout, default 5s
+}
+
+// SMARTConfig selects the block devices reported by the "smart" source.
+type SMARTConfig struct {
+	Devices []string `yaml:"devices"` // e.g. /dev/sda, /dev/nvme0; empty = smartctl --scan
+}
+
+// PrometheusConfig lists the exporters scraped by the "prometheus" source.
+type PrometheusConfig struct {
+	Targets []ScrapeTarget `yaml:"targets"`
+}
+
+// ScrapeTarget is one Prometheus text exposition endpoint. Interval 0 scrapes
+// every time the collector runs; Timeout defaults to 10s. Job defaults to the
+// URL's host:port and is sent as the job dimension.
+type ScrapeTarget struct {
+	URL      string        `yaml:"url"`
+	Job      string        `yaml:"job"`
+	Interval time.Duration `yaml:"interval"`
+	Timeout  time.Duration `yaml:"timeout"`
+
+	// Basic auth credentials, if the exporter requires them
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+
+	// TLS settings for https targets; CertFile/KeyFile enable mTLS
+	CAFile             string `yaml:"ca_file"`
+	CertFile           string `yaml:"cert_file"`
+	KeyFile            string `yaml:"key_file"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify"`
+}
+
+// StatsDConfig configures the "statsd" source's UDP listener.
+type StatsDConfig struct {
+	Address string `yaml:"address"` // host:port, default 127.0.0.1:8125
+}
+
+// SystemdConfig selects the units reported by the "systemd" source.
+type SystemdConfig struct {
+	Units []string `yaml:"units"` // unit name globs, e.g. "*.service"; empty = *.service
+}
+
+// TraceCollectionConfig configures the trace pipeline, which exports spans
+// submitted to the trace runner over OTLP. Workers defaults to 1 and
+// QueueSize (payloads buffered while the server is unreachable) to 100.
+type TraceCollectionConfig struct {
+	Workers   int `yaml:"workers"`
+	QueueSize int `yaml:"queue_size"`
+}
+
+// SpoolConfig enables the on-disk spool for metric and log exports that fail
+// while the server is unreachable. Dir holds one spool per signal (empty =
+// disabled). MaxSizeMB caps each spool (default 100), evicting the oldest
+// records; MaxAge drops records older than this instead of replaying them
+// (0 = keep). ReplayBatch is how many spooled records are re-sent before
+// each live export once the server is back (default 10), so the backlog
+// drains alongside live data instead of ahead of it.
+type SpoolConfig struct {
+	Dir         string        `yaml:"dir"`
+	MaxSizeMB   int           `yaml:"max_size_mb"`
+	MaxAge      time.Duration `yaml:"max_age"`
+	ReplayBatch int           `yaml:"replay_batch"`
+}
+
+// MaxBytes returns the per-signal spool cap in bytes.
+func (c SpoolConfig) MaxBytes() int64 {
+	mb := c.MaxSizeMB
+	if mb <= 0 {
+		mb = 100
+	}
+	return int64(mb) << 20
+}
+
+// ReplayBatchSize returns how many spooled records are re-sent before each
+// live export.
+func (c SpoolConfig) ReplayBatchSize() int {
+	if c.ReplayBatch <= 0 {
+		return 10
+	}
+	return c.ReplayBatch
 }
 
 // ProcessCollectionConfig defines the configuration for process collection
@@ -73,6 +422,97 @@ type MetricCollectionConfig struct {
 type ProcessCollectionConfig struct {
 	Interval time.Duration `yaml:"interval"`
 	Workers  int           `yaml:"workers"`
+
+	// EnvAllowlist lists environment variable names (e.g. JAVA_OPTS) to read from
+	// collected processes and attach as labels. Only processes owned by the
+	// agent's user are read unless the agent runs privileged.
+	EnvAllowlist []string `yaml:"env_allowlist"`
+
+	// Watch lists process names to track across snapshots. A change of PID or
+	// start time is reported as a restart (System.Process.restarted).
+	Watch []string `yaml:"watch"`
+
+	// MinAge leaves processes younger than this out of snapshots, cutting the
+	// churn of transient children (0 = keep all). Processes whose name or
+	// executable matches a MinAgeInclude glob are always kept.
+	MinAge        time.Duration `yaml:"min_age"`
+	MinAgeInclude []string      `yaml:"min_age_include"`
+
+	// PerUser aggregates CPU and memory usage of all processes by owning user
+	// and reports it as System.User metrics with a "user" dimension. With
+	// PerUserOnly the full process snapshots are no longer sent.
+	PerUser     bool `yaml:"per_user"`
+	PerUserOnly bool `yaml:"per_user_only"`
+
+	// TopN keeps only the TopN processes by TopBy ("cpu", the default, or
+	// "mem") in snapshots, plus those at or above MinCPUPercent or
+	// MinMemPercent and those whose name matches an Include glob. With only
+	// thresholds set, just the processes above them (and Include) are kept.
+	// SummarizeOther appends a pseudo-process "other" totalling the rest.
+	TopN           int      `yaml:"top_n"`
+	TopBy          string   `yaml:"top_by"`
+	MinCPUPercent  float64  `yaml:"min_cpu_percent"`
+	MinMemPercent  float64  `yaml:"min_mem_percent"`
+	Include        []string `yaml:"include"`
+	SummarizeOther bool     `yaml:"summarize_other"`
+}
+
+// MaintenanceConfig controls maintenance mode. It is on while File exists
+// (see meta.InMaintenance for its format) or while a window started by the
+// "maintenance" command lasts. DefaultDuration is the command's window when it
+// gives none; zero means until turned off.
+type MaintenanceConfig struct {
+	File            string        `yaml:"file"`
+	DefaultDuration time.Duration `yaml:"default_duration"`
+}
+
+// DeploymentIDConfig lists where the deployment id is read from at startup.
+// The first non-empty source wins: Value, the Env variable, the first line of
+// File, then (if ConfigHash) a short hash of the loaded configuration.
+type DeploymentIDConfig struct {
+	Value      string `yaml:"value"`
+	Env        string `yaml:"env"`
+	File       string `yaml:"file"`
+	ConfigHash bool   `yaml:"config_hash"`
+}
+
+// ResourceConfig limits the agent's own CPU footprint on shared hosts.
+// Zero values leave the corresponding setting unchanged. CPUAffinity and
+// Nice are applied on Linux only.
+type ResourceConfig struct {
+	GOMAXPROCS  int   `yaml:"gomaxprocs"`
+	CPUAffinity []int `yaml:"cpu_affinity"`
+	Nice        int   `yaml:"nice"`
+}
+
+// Default caps used when LimitsConfig fields are left at zero.
+const (
+	DefaultMaxFileTailers   = 256
+	DefaultMaxScrapeTargets = 256
+)
+
+// LimitsConfig caps how many dynamic sources the agent will start, so a broad
+// glob or discovery rule cannot exhaust file descriptors. Zero means the default.
+type LimitsConfig struct {
+	MaxFileTailers   int `yaml:"max_file_tailers"`
+	MaxScrapeTargets int `yaml:"max_scrape_targets"`
+}
+
+// FileTailers returns the effective cap on tailed log files.
+func (l LimitsConfig) FileTailers() int {
+	return limitOrDefault(l.MaxFileTailers, DefaultMaxFileTailers)
+}
+
+// ScrapeTargets returns the effective cap on scrape targets.
+func (l LimitsConfig) ScrapeTargets() int {
+	return limitOrDefault(l.MaxScrapeTargets, DefaultMaxScrapeTargets)
+}
+
+func limitOrDefault(v, def int) int {
+	if v <= 0 {
+		return def
+	}
+	return v
 }
 
 // Config holds the configuration for the GoSight agent.
@@ -93,16 +533,26 @@ type Config struct {
 		AccessLogFile string `yaml:"access_log_file"`
 		DebugLogFile  string `yaml:"debug_log_file"`
 		LogLevel      string `yaml:"log_level"`
+
+		// PayloadDebug logs the JSON of every outgoing OTLP request at debug level.
+		PayloadDebug         bool `yaml:"payload_debug"`
+		PayloadDebugMaxBytes int  `yaml:"payload_debug_max_bytes"`
 	}
 
+	// Podman and Docker collectors run when listed in metric_collection.sources
+	// and Enabled (default true). APIVersion pins the engine API version used
+	// in requests (e.g. v4.0.0 for Podman, 1.41 for Docker); empty uses the
+	// built-in default for Podman and negotiation for Docker.
 	Podman struct {
-		Socket  string `yaml:"socket"`
-		Enabled bool   `yaml:"enabled"`
+		Socket     string `yaml:"socket"`
+		Enabled    bool   `yaml:"enabled"`
+		APIVersion string `yaml:"api_version"`
 	}
 
 	Docker struct {
-		Socket  string `yaml:"socket"`
-		Enabled bool   `yaml:"enabled"`
+		Socket     string `yaml:"socket"`
+		Enabled    bool   `yaml:"enabled"`
+		APIVersion string `yaml:"api_version"`
 	}
 
 	CustomTags map[string]string `yaml:"custom_tags"` // static tags to be sent with every metric
@@ -112,11 +562,95 @@ type Config struct {
 		Interval     time.Duration `yaml:"interval"`
 		HostOverride string        `yaml:"host"`
 
+		// UseFQDN reports the fully qualified domain name instead of the short
+		// hostname. HostOverride still takes precedence.
+		UseFQDN bool `yaml:"use_fqdn"`
+
+		// ProxyURL is an optional proxy used to reach the server from
+		// segmented networks: http:// or https:// (CONNECT) or socks5://,
+		// with optional user:pass@. Servers matching NO_PROXY bypass it.
+		ProxyURL string `yaml:"proxy_url"`
+
+		// WaitForReady makes metric and log export calls wait for the
+		// connection to become ready, up to the call deadline, instead of
+		// failing fast while it is reconnecting.
+		WaitForReady bool `yaml:"wait_for_ready"`
+
+		// ReconnectAfter is how long the shared connection may stay in
+		// TRANSIENT_FAILURE before it is torn down and re-dialed. 0 leaves
+		// recovery to gRPC's own reconnect backoff.
+		ReconnectAfter time.Duration `yaml:"reconnect_after"`
+
+		// Sink selects where payloads go: "" or "grpc" sends them to the
+		// server, "stdout" prints them as JSON without connecting (dry run).
+		// It is read at startup only.
+		Sink string `yaml:"sink"`
+
+		// InstanceName distinguishes several agents running on the same host.
+		// It is sent as the agent_instance tag and folded into the EndpointID.
+		InstanceName string `yaml:"instance_name"`
+
+		// MetadataFile is an optional JSON/YAML file with on-prem topology
+		// (region, zone, rack, ...) mapped onto the agent's metadata.
+		MetadataFile string `yaml:"metadata_file"`
+
+		// CloudDetection queries the AWS, GCP and Azure instance metadata
+		// services once at startup to fill the cloud.* resource attributes.
+		CloudDetection bool `yaml:"cloud_detection"`
+
+		// DeploymentID identifies the deploy/build the agent runs under and is
+		// sent as the deployment.id resource attribute on all signals.
+		DeploymentID DeploymentIDConfig `yaml:"deployment_id"`
+
+		// Maintenance marks all outgoing signals with maintenance=true so the
+		// server can suppress alerts during planned work.
+		Maintenance MaintenanceConfig `yaml:"maintenance"`
+
+		// RegenerateIDOnHostChange generates a new agent ID when the machine ID
+		// differs from the one the stored ID was created on, so VMs cloned from
+		// a golden image get unique IDs. Defaults to true.
+		RegenerateIDOnHostChange bool `yaml:"regenerate_id_on_host_change"`
+
+		// ResourceAttributeMapping maps meta tag keys to OTLP resource attribute
+		// names (e.g. team: service.team), replacing default attributes of the
+		// same name, so output can follow an organization's conventions.
+		ResourceAttributeMapping map[string]string `yaml:"resource_attribute_mapping"`
+
+		// ScopeVersion overrides the OTLP InstrumentationScope version of
+		// exported metrics and logs, which defaults to the agent version.
+		ScopeVersion string `yaml:"scope_version"`
+
+		// CycleDeadline is how long a runner's collection cycle may take before
+		// an overrun is logged. Zero means each runner's own interval.
+		CycleDeadline time.Duration `yaml:"cycle_deadline"`
+
+		// CycleMarkers logs a debug-level marker at the start and end of every
+		// metric and log collection cycle, with collected/queued/dropped counts.
+		CycleMarkers bool `yaml:"cycle_markers"`
+
+		// DefaultDimensions are merged into the Dimensions of every metric, for
+		// backends that key dashboards on dimensions rather than resource
+		// attributes. Dimensions set by a collector win on key collision.
+		DefaultDimensions map[string]string `yaml:"default_dimensions"`
+
 		MetricCollection  MetricCollectionConfig  `yaml:"metric_collection"`
 		LogCollection     LogCollectionConfig     `yaml:"log_collection"`
 		ProcessCollection ProcessCollectionConfig `yaml:"process_collection"`
+		TraceCollection   TraceCollectionConfig   `yaml:"trace_collection"`
+		Spool             SpoolConfig             `yaml:"spool"`
+		Limits            LimitsConfig            `yaml:"limits"`
+		Resources         ResourceConfig          `yaml:"resources"`
 
 		Environment string `yaml:"environment"`
+
+		// DumpFile is where an on-demand debug snapshot (SIGUSR1) is written.
+		// If empty, the snapshot is written to stderr.
+		DumpFile string `yaml:"dump_file"`
+
+		// ShutdownDrainTimeout bounds how long the worker pools may keep
+		// exporting queued batches after a shutdown signal before the
+		// connections are closed. 0 discards whatever is still queued.
+		ShutdownDrainTimeout time.Duration `yaml:"shutdown_drain_timeout"`
 	}
 }
 
@@ -132,6 +666,13 @@ func LoadConfig(path string) (*Config, error) {
 		return nil, err
 	}
 	var cfg Config
+	cfg.Agent.RegenerateIDOnHostChange = true // default on unless set in the file
+	cfg.Podman.Enabled = true
+	cfg.Docker.Enabled = true
+	cfg.Agent.ShutdownDrainTimeout = 10 * time.Second
+	cfg.Agent.CloudDetection = true
+	cfg.Agent.ReconnectAfter = 30 * time.Second
+	cfg.Agent.LogCollection.Redact.Builtin = true
 	if err := yaml.Unmarshal(data, &cfg); err != nil {
 		return nil, err
 	}
@@ -160,16 +701,35 @@ func ApplyEnvOverrides(cfg *Config) {
 			fmt.Printf("Invalid GOSIGHT_INTERVAL format: %s\n", val)
 		}
 	}
+	if val := os.Getenv("GOSIGHT_PROXY_URL"); val != "" {
+		cfg.Agent.ProxyURL = val
+		fmt.Printf("Env override: GOSIGHT_PROXY_URL set\n")
+	}
 	if val := os.Getenv("GOSIGHT_HOST"); val != "" {
 		cfg.Agent.HostOverride = val
 		fmt.Printf("Env override: GOSIGHT_HOST = %s\n", val)
 	}
 
+	if val := os.Getenv("GOSIGHT_INSTANCE_NAME"); val != "" {
+		cfg.Agent.InstanceName = val
+		fmt.Printf("Env override: GOSIGHT_INSTANCE_NAME = %s\n", val)
+	}
+
+	if val := os.Getenv("GOSIGHT_METADATA_FILE"); val != "" {
+		cfg.Agent.MetadataFile = val
+		fmt.Printf("Env override: GOSIGHT_METADATA_FILE = %s\n", val)
+	}
+
 	if val := os.Getenv("GOSIGHT_ENVIRONMENT"); val != "" {
 		cfg.Agent.Environment = val
 		fmt.Printf("Env override: GOSIGHT_ENVIRONMENT = %s\n", val)
 	}
 
+	if val := os.Getenv("GOSIGHT_DUMP_FILE"); val != "" {
+		cfg.Agent.DumpFile = val
+		fmt.Printf("Env override: GOSIGHT_DUMP_FILE = %s\n", val)
+	}
+
 	// Log paths
 	if val := os.Getenv("GOSIGHT_ERROR_LOG_FILE"); val != "" {
 		cfg.Logs.ErrorLogFile = val
@@ -0,0 +1,175 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+// internal/config/diff.go
+// diff.go compares two configurations to decide what a reload must restart.
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ConnectionChanges returns the names of connection-relevant settings that
+// differ between old and cur. A non-empty result means the gRPC connection
+// must be re-dialed; otherwise a reload can be applied without touching it.
+// Compression and transport options are not configurable today, so only the
+// server address, proxy, and TLS files are compared.
+func ConnectionChanges(old, cur *Config) []string {
+	if old == nil || cur == nil {
+		return []string{"config"}
+	}
+
+	var changed []string
+	check := func(name, a, b string) {
+		if a != b {
+			changed = append(changed, name)
+		}
+	}
+
+	check("agent.server_url", old.Agent.ServerURL, cur.Agent.ServerURL)
+	check("agent.proxy_url", old.Agent.ProxyURL, cur.Agent.ProxyURL)
+	check("tls.ca_file", old.TLS.CAFile, cur.TLS.CAFile)
+	check("tls.cert_file", old.TLS.CertFile, cur.TLS.CertFile)
+	check("tls.key_file", old.TLS.KeyFile, cur.TLS.KeyFile)
+
+	return changed
+}
+
+// redacted replaces the values of secret settings in change descriptions.
+const redacted = "[redacted]"
+
+// secretKeyParts marks settings whose values must not be reported. proxy_url
+// is included because it may carry user:password.
+var secretKeyParts = []string{"password", "secret", "token", "credential", "api_key", "proxy_url"}
+
+// IsSecretKey reports whether the dotted setting name holds a secret.
+func IsSecretKey(key string) bool {
+	k := strings.ToLower(key)
+	for _, part := range secretKeyParts {
+		if strings.Contains(k, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// Changes returns every setting that differs between old and cur, keyed by
+// its dotted YAML name (e.g. "agent.metric_collection.interval"), with a
+// "old -> new" description. Values of secret settings are redacted.
+func Changes(old, cur *Config) map[string]string {
+	a, b := flatten(old), flatten(cur)
+	changes := make(map[string]string)
+	for k, v := range b {
+		if prev, ok := a[k]; !ok || prev != v {
+			changes[k] = describeChange(k, a[k], v)
+		}
+	}
+	for k, v := range a {
+		if _, ok := b[k]; !ok {
+			changes[k] = describeChange(k, v, "")
+		}
+	}
+	return changes
+}
+
+// ChangedKeys returns the sorted names of the settings in changes.
+func ChangedKeys(changes map[string]string) []string {
+	keys := make([]string, 0, len(changes))
+	for k := range changes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func describeChange(key, from, to string) string {
+	if IsSecretKey(key) {
+		return redacted + " -> " + redacted
+	}
+	return fmt.Sprintf("%q -> %q", from, to)
+}
+
+// flatten renders every leaf setting of cfg as a string keyed by its dotted
+// YAML name. Map entries are addressed as "<map>.<key>".
+func flatten(cfg *Config) map[string]string {
+	out := make(map[string]string)
+	if cfg != nil {
+		flattenValue(reflect.ValueOf(*cfg), "", out)
+	}
+	return out
+}
+
+func flattenValue(v reflect.Value, prefix string, out map[string]string) {
+	if v.Type() == reflect.TypeOf(time.Duration(0)) {
+		out[prefix] = time.Duration(v.Int()).String()
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = strings.ToLower(f.Name)
+			}
+			flattenValue(v.Field(i), joinKey(prefix, name), out)
+		}
+	case reflect.Map:
+		for _, k := range v.MapKeys() {
+			flattenValue(v.MapIndex(k), joinKey(prefix, fmt.Sprint(k.Interface())), out)
+		}
+	case reflect.Slice, reflect.Array:
+		// Lists of scalars are compared as a whole, others element by element
+		switch v.Type().Elem().Kind() {
+		case reflect.Struct, reflect.Map, reflect.Pointer, reflect.Interface, reflect.Slice:
+			for i := 0; i < v.Len(); i++ {
+				flattenValue(v.Index(i), joinKey(prefix, fmt.Sprint(i)), out)
+			}
+		default:
+			out[prefix] = fmt.Sprint(v.Interface())
+		}
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			flattenValue(v.Elem(), prefix, out)
+		}
+	default:
+		out[prefix] = fmt.Sprint(v.Interface())
+	}
+}
+
+func joinKey(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
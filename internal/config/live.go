@@ -0,0 +1,46 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// internal/config/live.go
+
+package config
+
+import "sync"
+
+// live guards the configuration shared by a running agent, and the base
+// metadata built from it, while a reload replaces them in place. Readers
+// hold it for the duration of a collection cycle or payload conversion and
+// must not block on other goroutines (e.g. a full send queue) while doing so.
+var live sync.RWMutex
+
+// RLock locks the shared configuration for reading.
+func RLock() { live.RLock() }
+
+// RUnlock undoes a single RLock call.
+func RUnlock() { live.RUnlock() }
+
+// Update runs fn with the shared configuration locked for writing. fn may
+// replace the configuration in place and rebuild metadata derived from it.
+func Update(fn func()) {
+	live.Lock()
+	defer live.Unlock()
+	fn()
+}
@@ -0,0 +1,296 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// internal/config/validate.go
+// validate.go checks a loaded configuration so mistakes are reported at
+// startup instead of surfacing as a mysteriously broken agent.
+
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// MetricSources are the names accepted in agent.metric_collection.sources.
+// Keep in sync with the collectors built by metriccollector.NewRegistry.
+var MetricSources = []string{
+	"cpu", "mem", "disk", "host", "net", "conn", "zfs", "btrfs", "gpu", "mdstat", "meminfo",
+	"swaps", "smart", "fifo", "otlp_socket", "winservices", "ntp", "podman", "docker", "prometheus",
+	"statsd", "systemd", "sensors",
+}
+
+// LogSources are the names accepted in agent.log_collection.sources.
+// Keep in sync with the collectors built by logcollector.NewRegistry.
+var LogSources = []string{
+	"journald", "security", "kmsg", "file", "listeners", "eventviewer",
+}
+
+// Validate checks the configuration for values that would leave the agent
+// unable to run or silently broken: a missing or malformed server_url,
+// non-positive intervals, worker counts and sizes, unknown collector names,
+// malformed relabel rules, unreadable TLS files and state files (journald cursor_file, files
+// state_file) whose directory is not writable. With sink: stdout the server
+// and TLS settings are not checked. It reports every problem found, not just
+// the first.
+func (c *Config) Validate() error {
+	var problems []string
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	// Printing to stdout needs neither a server nor TLS files
+	dryRun := c.Agent.Sink == "stdout"
+	switch c.Agent.Sink {
+	case "", "grpc", "stdout":
+	default:
+		add("agent.sink must be grpc or stdout, got %q", c.Agent.Sink)
+	}
+
+	if err := validateServerURL(c.Agent.ServerURL); err != nil && !dryRun {
+		add("agent.server_url: %v", err)
+	}
+
+	mc := c.Agent.MetricCollection
+	if mc.Interval <= 0 {
+		add("agent.metric_collection.interval must be positive, got %v", mc.Interval)
+	}
+	if mc.Workers <= 0 {
+		add("agent.metric_collection.workers must be positive, got %d", mc.Workers)
+	}
+	for _, name := range unknownNames(mc.Sources, MetricSources) {
+		add("agent.metric_collection.sources: unknown collector %q (known: %s)", name, strings.Join(MetricSources, ", "))
+	}
+	for i, t := range mc.Prometheus.Targets {
+		if u, err := url.Parse(t.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			add("agent.metric_collection.prometheus.targets[%d].url must be an http(s) URL, got %q", i, t.URL)
+		}
+		if t.Interval < 0 || t.Timeout < 0 {
+			add("agent.metric_collection.prometheus.targets[%d]: interval and timeout must not be negative", i)
+		}
+	}
+	for _, pattern := range append(append([]string{}, mc.Disk.ExcludeFSTypes...), mc.Disk.ExcludeMountpoints...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			add("agent.metric_collection.disk: invalid exclude pattern %q", pattern)
+		}
+	}
+	if mc.Disk.UsageTimeout < 0 {
+		add("agent.metric_collection.disk.usage_timeout must not be negative, got %v", mc.Disk.UsageTimeout)
+	}
+	for i, rule := range mc.Relabel {
+		if err := rule.validate(); err != nil {
+			add("agent.metric_collection.relabel[%d]: %v", i, err)
+		}
+	}
+
+	lc := c.Agent.LogCollection
+	if len(lc.Sources) > 0 {
+		if lc.Interval <= 0 {
+			add("agent.log_collection.interval must be positive, got %v", lc.Interval)
+		}
+		if lc.Workers <= 0 {
+			add("agent.log_collection.workers must be positive, got %d", lc.Workers)
+		}
+		if lc.BatchSize <= 0 {
+			add("agent.log_collection.batch_size must be positive, got %d", lc.BatchSize)
+		}
+		if lc.BufferSize <= 0 {
+			add("agent.log_collection.buffer_size must be positive, got %d", lc.BufferSize)
+		}
+	}
+	for i, p := range lc.Redact.Patterns {
+		if p.Name == "" {
+			add("agent.log_collection.redact.patterns[%d].name is required", i)
+		}
+		if _, err := regexp.Compile(p.Regex); err != nil || p.Regex == "" {
+			add("agent.log_collection.redact.patterns[%d].regex is not a valid regular expression: %q", i, p.Regex)
+		}
+	}
+	for _, prio := range lc.Journald.Priorities {
+		if prio < 0 || prio > 7 {
+			add("agent.log_collection.journald.priorities: %d is not a syslog priority (0-7)", prio)
+		}
+	}
+	for _, name := range unknownNames(lc.Sources, LogSources) {
+		add("agent.log_collection.sources: unknown collector %q (known: %s)", name, strings.Join(LogSources, ", "))
+	}
+
+	pc := c.Agent.ProcessCollection
+	if pc.Interval <= 0 {
+		add("agent.process_collection.interval must be positive, got %v", pc.Interval)
+	}
+	if pc.Workers <= 0 {
+		add("agent.process_collection.workers must be positive, got %d", pc.Workers)
+	}
+	if pc.TopN < 0 {
+		add("agent.process_collection.top_n must not be negative, got %d", pc.TopN)
+	}
+	if pc.TopBy != "" && pc.TopBy != "cpu" && pc.TopBy != "mem" {
+		add("agent.process_collection.top_by must be cpu or mem, got %q", pc.TopBy)
+	}
+
+	if !dryRun {
+		for _, f := range []struct{ key, path string }{
+			{"tls.ca_file", c.TLS.CAFile},
+			{"tls.cert_file", c.TLS.CertFile},
+			{"tls.key_file", c.TLS.KeyFile},
+		} {
+			if err := checkReadable(f.path); err != nil {
+				add("%s: %v", f.key, err)
+			}
+		}
+		if (c.TLS.CertFile == "") != (c.TLS.KeyFile == "") {
+			add("tls.cert_file and tls.key_file must be set together")
+		}
+	}
+
+	if c.Agent.Spool.ReplayBatch < 0 {
+		add("agent.spool.replay_batch must not be negative, got %d", c.Agent.Spool.ReplayBatch)
+	}
+
+	for _, f := range []struct{ key, path string }{
+		{"agent.log_collection.journald.cursor_file", lc.Journald.CursorFile},
+		{"agent.log_collection.files.state_file", lc.Files.StateFile},
+	} {
+		if err := checkDirWritable(f.path); err != nil {
+			add("%s: %v", f.key, err)
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid configuration (%d problems):\n  - %s", len(problems), strings.Join(problems, "\n  - "))
+}
+
+// validate checks that the rule's action is known and has the fields it needs.
+func (r RelabelRule) validate() error {
+	if r.Match != "" {
+		if _, err := path.Match(r.Match, ""); err != nil {
+			return fmt.Errorf("invalid match %q: %v", r.Match, err)
+		}
+	}
+	switch r.Action {
+	case "drop":
+		if r.Regex == "" {
+			return nil
+		}
+		if r.Dimension == "" {
+			return fmt.Errorf("drop with regex needs a dimension")
+		}
+		if _, err := regexp.Compile(r.Regex); err != nil {
+			return fmt.Errorf("invalid regex %q: %v", r.Regex, err)
+		}
+	case "rename_dimension":
+		if r.Dimension == "" || r.NewName == "" {
+			return fmt.Errorf("rename_dimension needs dimension and new_name")
+		}
+	case "set_dimension", "remove_dimension":
+		if r.Dimension == "" {
+			return fmt.Errorf("%s needs a dimension", r.Action)
+		}
+	default:
+		return fmt.Errorf("unknown action %q (drop, rename_dimension, set_dimension, remove_dimension)", r.Action)
+	}
+	return nil
+}
+
+// validateServerURL accepts host:port, optionally behind a gRPC target
+// scheme (e.g. dns:///host:port).
+func validateServerURL(raw string) error {
+	if strings.TrimSpace(raw) == "" {
+		return fmt.Errorf("must be set (host:port)")
+	}
+
+	target := raw
+	if strings.Contains(raw, "://") {
+		u, err := url.Parse(raw)
+		if err != nil {
+			return fmt.Errorf("cannot parse %q: %v", raw, err)
+		}
+		target = u.Host
+		if target == "" {
+			target = strings.TrimPrefix(u.Path, "/")
+		}
+	}
+
+	host, port, err := net.SplitHostPort(target)
+	if err != nil {
+		return fmt.Errorf("expected host:port, got %q: %v", raw, err)
+	}
+	if host == "" {
+		return fmt.Errorf("missing host in %q", raw)
+	}
+	if n, err := strconv.Atoi(port); err != nil || n <= 0 || n > 65535 {
+		return fmt.Errorf("invalid port in %q", raw)
+	}
+	return nil
+}
+
+// unknownNames returns the entries of names that are not in known.
+func unknownNames(names, known []string) []string {
+	valid := make(map[string]struct{}, len(known))
+	for _, k := range known {
+		valid[k] = struct{}{}
+	}
+	var unknown []string
+	for _, n := range names {
+		if _, ok := valid[n]; !ok {
+			unknown = append(unknown, n)
+		}
+	}
+	return unknown
+}
+
+// checkReadable returns an error if path is set but cannot be opened.
+func checkReadable(path string) error {
+	if path == "" {
+		return nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// checkDirWritable returns an error if path is set but a file cannot be
+// created next to it, which the atomic save (temp file + rename) needs.
+func checkDirWritable(path string) error {
+	if path == "" {
+		return nil
+	}
+	dir := filepath.Dir(path)
+	f, err := os.CreateTemp(dir, ".gosight-check-*")
+	if err != nil {
+		return fmt.Errorf("directory %s is not writable: %v", dir, err)
+	}
+	name := f.Name()
+	f.Close()
+	return os.Remove(name)
+}
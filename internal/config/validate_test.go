@@ -0,0 +1,97 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func validConfig() *Config {
+	cfg := &Config{}
+	cfg.Agent.ServerURL = "localhost:50051"
+	cfg.Agent.MetricCollection.Interval = 2 * time.Second
+	cfg.Agent.MetricCollection.Workers = 2
+	cfg.Agent.MetricCollection.Sources = []string{"cpu", "mem"}
+	cfg.Agent.LogCollection.Sources = []string{"journald"}
+	cfg.Agent.LogCollection.Interval = 30 * time.Second
+	cfg.Agent.LogCollection.Workers = 2
+	cfg.Agent.LogCollection.BatchSize = 50
+	cfg.Agent.LogCollection.BufferSize = 500
+	cfg.Agent.ProcessCollection.Interval = 2 * time.Second
+	cfg.Agent.ProcessCollection.Workers = 2
+	return cfg
+}
+
+func TestValidate(t *testing.T) {
+	if err := validConfig().Validate(); err != nil {
+		t.Fatalf("expected a valid config, got %v", err)
+	}
+
+	cfg := validConfig()
+	cfg.Agent.ServerURL = ""
+	cfg.Agent.MetricCollection.Interval = -time.Second
+	cfg.Agent.MetricCollection.Sources = []string{"cpu", "cpuu"}
+	cfg.Agent.LogCollection.BatchSize = 0
+	cfg.TLS.CAFile = filepath.Join(t.TempDir(), "missing-ca.crt")
+	cfg.Agent.LogCollection.Journald.CursorFile = filepath.Join(t.TempDir(), "no-such-dir", "cursor")
+	cfg.Agent.MetricCollection.Relabel = []RelabelRule{
+		{Action: "drop", Dimension: "fstype", Regex: "tmpfs"},
+		{Action: "rename", Dimension: "core"},
+	}
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("expected validation errors")
+	}
+	for _, want := range []string{
+		"agent.server_url",
+		"agent.metric_collection.interval",
+		`unknown collector "cpuu"`,
+		"agent.log_collection.batch_size",
+		"tls.ca_file",
+		"cursor_file",
+		`relabel[1]: unknown action "rename"`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to mention %q, got:\n%v", want, err)
+		}
+	}
+	if !strings.Contains(err.Error(), "7 problems") {
+		t.Errorf("expected all 7 problems to be reported, got:\n%v", err)
+	}
+}
+
+func TestValidateServerURL(t *testing.T) {
+	for _, ok := range []string{"localhost:50051", "10.0.0.1:443", "[::1]:4317", "dns:///gosight.example.com:443"} {
+		if err := validateServerURL(ok); err != nil {
+			t.Errorf("%q: unexpected error %v", ok, err)
+		}
+	}
+	for _, bad := range []string{"", "localhost", ":50051", "host:port", "host:70000"} {
+		if err := validateServerURL(bad); err == nil {
+			t.Errorf("%q: expected an error", bad)
+		}
+	}
+}
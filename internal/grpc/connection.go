@@ -24,18 +24,24 @@ along with GoSight. If not, see https://www.gnu.org/licenses/.
 package grpcconn
 
 import (
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/aaronlmathis/gosight-agent/internal/config"
 	agentutils "github.com/aaronlmathis/gosight-agent/internal/utils"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/encoding/gzip"
 	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
 )
 
+// MaxMessageSize is the largest gRPC message the agent sends or accepts.
+const MaxMessageSize = 32 * 1024 * 1024
+
 var (
 	conn   *grpc.ClientConn
 	connMu sync.Mutex
@@ -87,20 +93,71 @@ func GetGRPCConn(cfg *config.Config) (*grpc.ClientConn, error) {
 		grpc.WithWriteBufferSize(8 * 1024 * 1024),
 		grpc.WithDefaultCallOptions(
 			grpc.UseCompressor(gzip.Name),
-			grpc.MaxCallRecvMsgSize(32*1024*1024),
-			grpc.MaxCallSendMsgSize(32*1024*1024),
+			grpc.MaxCallRecvMsgSize(MaxMessageSize),
+			grpc.MaxCallSendMsgSize(MaxMessageSize),
 		),
 	}
 
-	c, err := grpc.NewClient(cfg.Agent.ServerURL, opts...)
+	// Tunnel through an HTTP CONNECT proxy if one is configured
+	if cfg.Agent.ProxyURL != "" {
+		dialer, err := proxyDialer(cfg.Agent.ProxyURL)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.WithContextDialer(dialer))
+	}
+
+	c, err := grpc.NewClient(dialTarget(cfg.Agent.ServerURL, cfg.Agent.ProxyURL != ""), opts...)
 	if err != nil {
 		return nil, err
 	}
 
 	conn = c
+	go monitorConn(c, cfg.Agent.ReconnectAfter)
 	return conn, nil
 }
 
+// dialTarget returns the gRPC target for serverURL. Through a proxy the
+// default dns resolver would hand the dialer resolved IPs, so the proxy could
+// neither resolve the name itself nor apply NO_PROXY to it; the passthrough
+// resolver passes host:port to the dialer unchanged.
+func dialTarget(serverURL string, proxied bool) string {
+	if !proxied {
+		return serverURL
+	}
+	if host, ok := strings.CutPrefix(serverURL, "dns:///"); ok {
+		return "passthrough:///" + host
+	}
+	if strings.Contains(serverURL, ":///") {
+		return serverURL
+	}
+	return "passthrough:///" + serverURL
+}
+
+// ExportCallOptions returns the per-call options for OTLP export calls.
+// With agent.wait_for_ready set, an export issued while the connection is
+// briefly not ready blocks until it is (bounded by the call's deadline)
+// instead of failing immediately with Unavailable. It is called from the
+// sender workers, so it reads the config under the reload lock.
+func ExportCallOptions(cfg *config.Config) []grpc.CallOption {
+	config.RLock()
+	defer config.RUnlock()
+	if cfg.Agent.WaitForReady {
+		return []grpc.CallOption{grpc.WaitForReady(true)}
+	}
+	return nil
+}
+
+// Unreachable reports whether an export error means the server could not be
+// reached (Unavailable or DeadlineExceeded), as opposed to a rejected request.
+func Unreachable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	}
+	return false
+}
+
 // CloseGRPCConn closes the connection (for shutdown)
 func CloseGRPCConn() error {
 	connMu.Lock()
@@ -121,7 +178,7 @@ func GetPauseUntil() time.Time {
 }
 
 // PauseConnections schedules a global pause of duration d,
-// and broadcasts one “disconnect” event on disconnectCh.
+// and broadcasts one “disconnect” event to every sender.
 func PauseConnections(d time.Duration) {
 	// set the pause deadline
 	pauseMu.Lock()
@@ -131,16 +188,26 @@ func PauseConnections(d time.Duration) {
 	// Immediately tear down the shared gRPC connection
 	_ = CloseGRPCConn()
 
+	// A deliberate disconnect is not an outage: reconnect promptly once resumed
+	coordMu.Lock()
+	resetBackoffLocked()
+	coordMu.Unlock()
+
+	// Closing the channel wakes every listener at once; later callers of
+	// DisconnectNotify get a fresh channel for the next event
 	disconnectMu.Lock()
-	// non-blocking broadcast into the buffered channel
-	select {
-	case disconnectCh <- struct{}{}:
-	default:
-		// if the buffer is full, we’ve already signaled — no need to block
-	}
+	close(disconnectCh)
+	disconnectCh = make(chan struct{})
 	disconnectMu.Unlock()
 }
 
+// Redial tears down the shared connection so every sender re-dials with the
+// current configuration (e.g. after the server URL or TLS files changed).
+// Unlike a server-initiated disconnect it does not pause reconnection.
+func Redial() {
+	PauseConnections(0)
+}
+
 // WaitForResume blocks until time.Now() ≥ pauseUntil.
 // Even if pauseUntil was extended mid-sleep, this will re-check.
 func WaitForResume() {
@@ -158,8 +225,10 @@ func WaitForResume() {
 	}
 }
 
-// DisconnectNotify returns the channel that will get
-// one event each time PauseConnections is called.
+// DisconnectNotify returns a channel that is closed the next time
+// PauseConnections is called.
 func DisconnectNotify() <-chan struct{} {
+	disconnectMu.Lock()
+	defer disconnectMu.Unlock()
 	return disconnectCh
 }
@@ -0,0 +1,138 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// agent/internal/grpc/coordinator.go
+// Shares reconnect backoff between the senders using the singleton connection.
+package grpcconn
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	initialBackoff = 1 * time.Second
+	maxBackoff     = 15 * time.Minute
+
+	// probeTimeout is how long the sender holding the probe turn may take to
+	// report back before another sender is allowed to probe.
+	probeTimeout = 30 * time.Second
+)
+
+// The metrics, logs and process senders all ride on the same ClientConn, so a
+// server outage hits them at the same time. Rather than each sender running
+// its own backoff, the first failure opens a shared retry window: the other
+// senders join it instead of extending it, one sender probes when it expires,
+// and a successful probe wakes everyone up together.
+var (
+	coordMu      sync.Mutex
+	down         bool
+	backoff      time.Duration
+	retryAt      time.Time
+	probeStarted time.Time
+	upCh         = make(chan struct{})
+)
+
+// ReportFailure records that a sender failed to connect (stage describes
+// what failed, e.g. "metrics dial"). If a retry window is already open the
+// failure joins it; otherwise a new window is opened with an exponentially
+// growing backoff and the failure is logged via ReconnectFailed.
+func ReportFailure(stage string) {
+	coordMu.Lock()
+	now := time.Now()
+	if down && now.Before(retryAt) {
+		coordMu.Unlock()
+		return
+	}
+
+	if down {
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	} else {
+		down = true
+		backoff = initialBackoff
+		upCh = make(chan struct{})
+	}
+	retryAt = now.Add(backoff)
+	probeStarted = time.Time{}
+	b := backoff
+	coordMu.Unlock()
+
+	ReconnectFailed(stage, b)
+}
+
+// ReportSuccess records that a sender connected, ending the outage and
+// waking every sender waiting in WaitForRetry.
+func ReportSuccess() {
+	coordMu.Lock()
+	defer coordMu.Unlock()
+	resetBackoffLocked()
+}
+
+// resetBackoffLocked clears the outage state. coordMu must be held.
+func resetBackoffLocked() {
+	if !down {
+		return
+	}
+	down = false
+	backoff = 0
+	retryAt = time.Time{}
+	probeStarted = time.Time{}
+	close(upCh)
+}
+
+// WaitForRetry blocks until the caller should try to connect again: when the
+// connection has recovered, or when the retry window has expired and the
+// caller has been given the probe turn. It returns false if ctx is cancelled.
+func WaitForRetry(ctx context.Context) bool {
+	for {
+		coordMu.Lock()
+		if !down {
+			coordMu.Unlock()
+			return true
+		}
+
+		now := time.Now()
+		wait := retryAt.Sub(now)
+		if wait <= 0 {
+			if probeStarted.IsZero() || now.Sub(probeStarted) >= probeTimeout {
+				probeStarted = now
+				coordMu.Unlock()
+				return true
+			}
+			// Someone else is probing; wait for its result
+			wait = probeTimeout - now.Sub(probeStarted)
+		}
+		up := upCh
+		coordMu.Unlock()
+
+		select {
+		case <-up:
+			return true
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
@@ -0,0 +1,75 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+package grpcconn
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSharedBackoff(t *testing.T) {
+	t.Cleanup(ReportSuccess)
+
+	ReportFailure("metrics dial")
+	coordMu.Lock()
+	first := retryAt
+	coordMu.Unlock()
+
+	// A second sender failing inside the window joins it instead of extending it
+	ReportFailure("logs dial")
+	coordMu.Lock()
+	joined := retryAt
+	coordMu.Unlock()
+	if !joined.Equal(first) {
+		t.Fatalf("retry window moved from %v to %v", first, joined)
+	}
+
+	// Waiters are released together when the connection recovers
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	done := make(chan bool, 2)
+	for i := 0; i < 2; i++ {
+		go func() { done <- WaitForRetry(ctx) }()
+	}
+	time.Sleep(50 * time.Millisecond)
+	ReportSuccess()
+
+	for i := 0; i < 2; i++ {
+		if !<-done {
+			t.Fatal("WaitForRetry returned false before ctx was cancelled")
+		}
+	}
+}
+
+func TestWaitForRetryCancelled(t *testing.T) {
+	t.Cleanup(ReportSuccess)
+
+	ReportFailure("process stream")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if WaitForRetry(ctx) {
+		t.Fatal("WaitForRetry returned true for a cancelled context during backoff")
+	}
+}
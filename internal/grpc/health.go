@@ -0,0 +1,99 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// agent/internal/grpc/health.go
+// Watches the shared connection and re-dials it when it stays broken.
+package grpcconn
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/aaronlmathis/gosight-shared/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+var healthRedials atomic.Uint64
+
+// monitorConn follows c's connectivity state until it is shut down. When c
+// stays in TRANSIENT_FAILURE for longer than threshold it is torn down with
+// Redial, so every sender re-dials instead of backing off against a dead
+// connection. A zero threshold only follows the state.
+func monitorConn(c *grpc.ClientConn, threshold time.Duration) {
+	var failingSince time.Time
+
+	for {
+		state := c.GetState()
+		if state == connectivity.Shutdown {
+			return
+		}
+
+		if state != connectivity.TransientFailure || threshold <= 0 {
+			failingSince = time.Time{}
+			c.WaitForStateChange(context.Background(), state)
+			continue
+		}
+
+		if failingSince.IsZero() {
+			failingSince = time.Now()
+		}
+		remaining := threshold - time.Since(failingSince)
+		if remaining <= 0 {
+			if !isCurrent(c) {
+				return
+			}
+			utils.Warn("gRPC connection in %s for over %v; re-dialing", state, threshold)
+			healthRedials.Add(1)
+			Redial()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), remaining)
+		c.WaitForStateChange(ctx, state)
+		cancel()
+	}
+}
+
+// isCurrent reports whether c is still the shared connection.
+func isCurrent(c *grpc.ClientConn) bool {
+	connMu.Lock()
+	defer connMu.Unlock()
+	return conn == c
+}
+
+// ConnState returns the shared connection's connectivity state, and false if
+// there is no connection.
+func ConnState() (connectivity.State, bool) {
+	connMu.Lock()
+	defer connMu.Unlock()
+	if conn == nil {
+		return 0, false
+	}
+	return conn.GetState(), true
+}
+
+// HealthRedials returns how many times the health monitor tore down a
+// connection stuck in TRANSIENT_FAILURE.
+func HealthRedials() uint64 {
+	return healthRedials.Load()
+}
@@ -0,0 +1,67 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+package grpcconn
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func TestMonitorRedialsStuckConnection(t *testing.T) {
+	// A port nothing listens on keeps the connection in TRANSIENT_FAILURE
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	c, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	connMu.Lock()
+	conn = c
+	connMu.Unlock()
+	t.Cleanup(func() { _ = CloseGRPCConn() })
+
+	before := HealthRedials()
+	notify := DisconnectNotify()
+	c.Connect()
+	go monitorConn(c, 100*time.Millisecond)
+
+	select {
+	case <-notify:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("connection was not re-dialed (state %s)", c.GetState())
+	}
+	if HealthRedials() != before+1 {
+		t.Errorf("expected one health redial, got %d", HealthRedials()-before)
+	}
+	if _, ok := ConnState(); ok {
+		t.Error("expected the stuck connection to be torn down")
+	}
+}
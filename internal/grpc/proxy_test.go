@@ -0,0 +1,141 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+package grpcconn
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// fakeConnectProxy accepts one CONNECT request, reports the requested target
+// and echoes everything sent through the tunnel.
+func fakeConnectProxy(t *testing.T) (addr string, target <-chan string) {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	targets := make(chan string, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		req, err := http.ReadRequest(bufio.NewReader(conn))
+		if err != nil || req.Method != http.MethodConnect {
+			return
+		}
+		targets <- req.Host
+		io.WriteString(conn, "HTTP/1.1 200 Connection established\r\n\r\n")
+		io.Copy(conn, conn)
+	}()
+	return l.Addr().String(), targets
+}
+
+func TestProxyDialerConnect(t *testing.T) {
+	t.Setenv("NO_PROXY", "")
+	t.Setenv("no_proxy", "")
+	addr, targets := fakeConnectProxy(t)
+
+	dial, err := proxyDialer("http://" + addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, err := dial(ctx, "gosight.example:4317")
+	if err != nil {
+		t.Fatalf("dial through proxy: %v", err)
+	}
+	defer conn.Close()
+
+	if got := <-targets; got != "gosight.example:4317" {
+		t.Errorf("proxy asked to connect to %q", got)
+	}
+	if _, err := conn.Write([]byte("ping")); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 4)
+	if _, err := io.ReadFull(conn, buf); err != nil || string(buf) != "ping" {
+		t.Errorf("tunnel echoed %q, %v", buf, err)
+	}
+}
+
+func TestProxyNoProxy(t *testing.T) {
+	t.Setenv("NO_PROXY", ".internal,10.0.0.0/8")
+	bypass := noProxy("socks5://proxy.example:1080")
+
+	for addr, want := range map[string]bool{
+		"gosight.internal:4317": true,
+		"10.1.2.3:4317":         true,
+		"gosight.example:4317":  false,
+	} {
+		if got := bypass(addr); got != want {
+			t.Errorf("bypass(%s) = %v, want %v", addr, got, want)
+		}
+	}
+
+	if _, err := proxyDialer("ftp://proxy.example"); err == nil {
+		t.Error("expected an error for an unsupported scheme")
+	}
+}
+
+// The proxy must be asked for the server's hostname, not an address the
+// agent resolved itself.
+func TestProxyReceivesHostname(t *testing.T) {
+	t.Setenv("NO_PROXY", "")
+	t.Setenv("no_proxy", "")
+	addr, targets := fakeConnectProxy(t)
+
+	dial, err := proxyDialer("http://" + addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := grpc.NewClient(dialTarget("gosight.example:4317", true),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(dial))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	c.Connect()
+
+	select {
+	case got := <-targets:
+		if got != "gosight.example:4317" {
+			t.Errorf("proxy asked to connect to %q, want the hostname", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("proxy was never dialed")
+	}
+}
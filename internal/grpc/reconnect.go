@@ -0,0 +1,80 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// agent/internal/grpc/reconnect.go
+// Tracks reconnection attempts and rate-limits the related log lines.
+package grpcconn
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aaronlmathis/gosight-shared/utils"
+	"google.golang.org/grpc/connectivity"
+)
+
+// reconnectLogInterval is the minimum time between reconnect log lines.
+const reconnectLogInterval = time.Minute
+
+var (
+	reconnectAttempts atomic.Uint64
+
+	reconnectLogMu   sync.Mutex
+	lastReconnectLog time.Time
+	suppressedLogs   int
+)
+
+// ReconnectFailed records a failed connection attempt by one of the senders
+// (stage describes what failed, e.g. "metrics dial") and logs it. During a
+// long outage the senders retry independently, so only one line is logged per
+// reconnectLogInterval, summarizing the attempts suppressed in between.
+func ReconnectFailed(stage string, backoff time.Duration) {
+	total := reconnectAttempts.Add(1)
+
+	reconnectLogMu.Lock()
+	defer reconnectLogMu.Unlock()
+
+	if time.Since(lastReconnectLog) < reconnectLogInterval {
+		suppressedLogs++
+		return
+	}
+
+	if suppressedLogs > 0 {
+		utils.Info("Server offline (%s): retrying in %s (%d more failed attempts since last report, %d total)", stage, backoff, suppressedLogs, total)
+	} else {
+		utils.Info("Server offline (%s): retrying in %s", stage, backoff)
+	}
+	lastReconnectLog = time.Now()
+	suppressedLogs = 0
+}
+
+// ReconnectAttempts returns the number of failed connection attempts since start.
+func ReconnectAttempts() uint64 {
+	return reconnectAttempts.Load()
+}
+
+// Connected reports whether the shared connection exists and is ready.
+func Connected() bool {
+	connMu.Lock()
+	defer connMu.Unlock()
+	return conn != nil && conn.GetState() == connectivity.Ready
+}
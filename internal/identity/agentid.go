@@ -27,6 +27,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 
 	"github.com/aaronlmathis/gosight-shared/utils"
 	"github.com/google/uuid"
@@ -34,12 +35,39 @@ import (
 
 // LoadOrCreateAgentID returns a stable UUID stored on disk.
 // It creates a new one on first run and saves it to disk.
-func LoadOrCreateAgentID() (string, error) {
+//
+// The machine ID the agent ID was created on is stored next to it. When
+// regenerateOnHostChange is set and the current machine ID differs (the
+// agent ID was baked into a cloned VM image), a new agent ID is generated so
+// clones do not collide. See machineID for the source used per platform.
+func LoadOrCreateAgentID(regenerateOnHostChange bool) (string, error) {
 	path := getAgentIDPath()
+	hostPath := path + ".host"
+	current := machineID()
 
 	if data, err := os.ReadFile(path); err == nil && len(data) > 0 {
-		utils.Debug("Loaded agent ID from disk: %s", string(data))
-		return string(data), nil
+		if !regenerateOnHostChange || current == "" {
+			utils.Debug("Loaded agent ID from disk: %s", string(data))
+			return string(data), nil
+		}
+
+		stored, err := os.ReadFile(hostPath)
+		switch {
+		case os.IsNotExist(err):
+			// Agent ID predates host tracking; adopt the current machine.
+			if err := os.WriteFile(hostPath, []byte(current), 0600); err != nil {
+				utils.Warn("Failed to record machine ID for agent ID: %v", err)
+			}
+			return string(data), nil
+		case err != nil:
+			utils.Warn("Failed to read %s: %v", hostPath, err)
+			return string(data), nil
+		case strings.TrimSpace(string(stored)) == current:
+			utils.Debug("Loaded agent ID from disk: %s", string(data))
+			return string(data), nil
+		}
+
+		utils.Warn("Machine ID changed since agent ID %s was created (cloned image?); generating a new agent ID", string(data))
 	}
 
 	id := uuid.NewString()
@@ -50,6 +78,11 @@ func LoadOrCreateAgentID() (string, error) {
 	if err := os.WriteFile(path, []byte(id), 0600); err != nil {
 		return "", err
 	}
+	if current != "" {
+		if err := os.WriteFile(hostPath, []byte(current), 0600); err != nil {
+			utils.Warn("Failed to record machine ID for agent ID: %v", err)
+		}
+	}
 
 	return id, nil
 }
@@ -0,0 +1,215 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/logs/logaudit/audit.go
+
+// Package logaudit keeps a local copy of selected log sources on the host,
+// independent of whether they can be shipped, for compliance retention. Entries
+// are appended as JSON lines to a file that is rotated by size; rotated files
+// are pruned by count and age.
+package logaudit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aaronlmathis/gosight-agent/internal/config"
+	"github.com/aaronlmathis/gosight-shared/model"
+)
+
+const (
+	defaultMaxSizeMB  = 100
+	defaultMaxBackups = 10
+
+	// rotatedSuffix is appended to the file name, after a dot, on rotation.
+	rotatedSuffix = "20060102T150405.000000000"
+)
+
+// record is the on-disk form of an audited entry.
+type record struct {
+	Collector string `json:"collector"`
+	model.LogEntry
+}
+
+// Sink is a size-rotated JSON-lines audit file.
+type Sink struct {
+	path       string
+	sources    map[string]bool
+	maxSize    int64
+	maxBackups int
+	maxAge     time.Duration
+
+	mu   sync.Mutex
+	f    *os.File
+	size int64
+}
+
+// Open opens (creating if needed) the audit file described by cfg.
+// It returns nil, nil when no audit sources are configured.
+func Open(cfg config.AuditLogConfig) (*Sink, error) {
+	if len(cfg.Sources) == 0 {
+		return nil, nil
+	}
+	if cfg.Path == "" {
+		return nil, fmt.Errorf("log_collection.audit.path is required when audit sources are set")
+	}
+
+	s := &Sink{
+		path:       cfg.Path,
+		sources:    make(map[string]bool, len(cfg.Sources)),
+		maxSize:    int64(cfg.MaxSizeMB) * 1024 * 1024,
+		maxBackups: cfg.MaxBackups,
+		maxAge:     cfg.MaxAge,
+	}
+	if s.maxSize <= 0 {
+		s.maxSize = defaultMaxSizeMB * 1024 * 1024
+	}
+	if s.maxBackups <= 0 {
+		s.maxBackups = defaultMaxBackups
+	}
+	for _, src := range cfg.Sources {
+		s.sources[src] = true
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create audit log dir: %w", err)
+	}
+	if err := s.openFile(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Audits reports whether entries from the named log collector are audited.
+func (s *Sink) Audits(collector string) bool {
+	return s != nil && s.sources[collector]
+}
+
+// Write appends entries from collector to the audit file and syncs it,
+// rotating first if the file would exceed its maximum size.
+func (s *Sink) Write(collector string, entries []model.LogEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	var buf []byte
+	for _, e := range entries {
+		line, err := json.Marshal(record{Collector: collector, LogEntry: e})
+		if err != nil {
+			return fmt.Errorf("failed to encode audit entry: %w", err)
+		}
+		buf = append(buf, line...)
+		buf = append(buf, '\n')
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.size > 0 && s.size+int64(len(buf)) > s.maxSize {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := s.f.Write(buf)
+	s.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return s.f.Sync()
+}
+
+// Close closes the audit file.
+func (s *Sink) Close() error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.f.Close()
+}
+
+// openFile opens the current audit file for appending.
+func (s *Sink) openFile() error {
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log %s: %w", s.path, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	s.f = f
+	s.size = info.Size()
+	return nil
+}
+
+// rotate renames the current file aside, opens a fresh one and prunes old
+// rotated files. s.mu must be held.
+func (s *Sink) rotate() error {
+	if err := s.f.Close(); err != nil {
+		return err
+	}
+	rotated := s.path + "." + time.Now().UTC().Format(rotatedSuffix)
+	if err := os.Rename(s.path, rotated); err != nil {
+		return fmt.Errorf("failed to rotate audit log: %w", err)
+	}
+	if err := s.openFile(); err != nil {
+		return err
+	}
+	s.prune()
+	return nil
+}
+
+// prune removes rotated files beyond maxBackups or older than maxAge.
+func (s *Sink) prune() {
+	matches, err := filepath.Glob(s.path + ".*")
+	if err != nil {
+		return
+	}
+	var rotated []string
+	for _, m := range matches {
+		if _, err := time.Parse(rotatedSuffix, strings.TrimPrefix(m, s.path+".")); err == nil {
+			rotated = append(rotated, m)
+		}
+	}
+	// Newest first; the suffix sorts chronologically
+	sort.Sort(sort.Reverse(sort.StringSlice(rotated)))
+
+	for i, m := range rotated {
+		if i >= s.maxBackups {
+			os.Remove(m)
+			continue
+		}
+		if s.maxAge > 0 {
+			if info, err := os.Stat(m); err == nil && time.Since(info.ModTime()) > s.maxAge {
+				os.Remove(m)
+			}
+		}
+	}
+}
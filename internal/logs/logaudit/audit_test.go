@@ -0,0 +1,68 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+package logaudit
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aaronlmathis/gosight-agent/internal/config"
+	"github.com/aaronlmathis/gosight-shared/model"
+)
+
+func TestRotateAndPrune(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	s, err := Open(config.AuditLogConfig{Sources: []string{"security"}, Path: path, MaxBackups: 2})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer s.Close()
+
+	if !s.Audits("security") || s.Audits("journald") {
+		t.Fatalf("unexpected audited sources")
+	}
+
+	// Force a rotation on every write
+	s.maxSize = 1
+	entry := []model.LogEntry{{Message: strings.Repeat("x", 64)}}
+	for i := 0; i < 5; i++ {
+		if err := s.Write("security", entry); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+
+	rotated, _ := filepath.Glob(path + ".*")
+	if len(rotated) != 2 {
+		t.Errorf("expected 2 rotated files to be kept, got %d: %v", len(rotated), rotated)
+	}
+}
+
+func TestOpenDisabled(t *testing.T) {
+	s, err := Open(config.AuditLogConfig{})
+	if err != nil || s != nil {
+		t.Fatalf("expected disabled sink, got %v, %v", s, err)
+	}
+	if s.Audits("security") {
+		t.Error("nil sink should audit nothing")
+	}
+}
@@ -0,0 +1,34 @@
+//go:build windows
+
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// internal/logs/logcollector/file/fileid_windows.go
+
+package filecollector
+
+import "os"
+
+// fileID returns 0: os.FileInfo carries no file index on Windows, so rotation
+// while the agent is down is only detected when the file shrank.
+func fileID(info os.FileInfo) uint64 {
+	return 0
+}
@@ -0,0 +1,363 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// internal/logs/logcollector/file/filetail.go
+// Package filecollector provides a log collector that tails arbitrary files
+// matched by glob patterns. Patterns are re-evaluated periodically so files
+// created after startup (e.g. by new app deployments) are picked up and files
+// that disappear are released, without an agent restart.
+package filecollector
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aaronlmathis/gosight-agent/internal/config"
+	"github.com/aaronlmathis/gosight-agent/internal/logs/logcollector/repeat"
+	agentutils "github.com/aaronlmathis/gosight-agent/internal/utils"
+	"github.com/aaronlmathis/gosight-shared/model"
+	"github.com/aaronlmathis/gosight-shared/utils"
+	"github.com/nxadm/tail"
+)
+
+// defaultDiscoveryInterval is used when log_collection.files.discovery_interval is unset.
+const defaultDiscoveryInterval = 30 * time.Second
+
+// FileTailCollector tails every file matching the configured glob patterns.
+type FileTailCollector struct {
+	patterns   []string
+	interval   time.Duration
+	maxFiles   int
+	maxMsgSize int
+	batchSize  int
+	repeat     config.RepeatCollapseConfig
+
+	stateFile string
+
+	mu      sync.Mutex
+	tailers map[string]*tail.Tail
+
+	// offsets has its own lock: tailer goroutines update it per line and
+	// must not wait on mu, which discover holds while stopping tailers.
+	offMu   sync.Mutex
+	offsets map[string]fileOffset
+
+	lines chan model.LogEntry
+	stop  chan struct{}
+	wg    sync.WaitGroup
+	once  sync.Once
+}
+
+// NewFileTailCollector creates the collector and starts discovery. Files that
+// exist at startup are tailed from their saved offset, or from their end when
+// none is saved; files discovered later are read from the beginning so
+// nothing a new deployment writes is missed.
+func NewFileTailCollector(cfg *config.Config) *FileTailCollector {
+	lc := cfg.Agent.LogCollection
+
+	interval := lc.Files.DiscoveryInterval
+	if interval <= 0 {
+		interval = defaultDiscoveryInterval
+	}
+	batchSize := lc.BatchSize
+	if batchSize <= 0 {
+		batchSize = 50
+	}
+
+	c := &FileTailCollector{
+		patterns:   lc.Files.Paths,
+		interval:   interval,
+		maxFiles:   cfg.Agent.Limits.FileTailers(),
+		maxMsgSize: lc.MessageMax,
+		batchSize:  batchSize,
+		repeat:     lc.Repeat,
+		stateFile:  lc.Files.StateFile,
+		tailers:    make(map[string]*tail.Tail),
+		offsets:    loadOffsets(lc.Files.StateFile),
+		lines:      make(chan model.LogEntry, batchSize*10),
+		stop:       make(chan struct{}),
+	}
+
+	if len(c.patterns) == 0 {
+		utils.Warn("File log collector enabled but log_collection.files.paths is empty")
+		return c
+	}
+
+	c.discover(true)
+
+	c.wg.Add(1)
+	go c.runDiscovery()
+
+	return c
+}
+
+// Name returns the name of the collector.
+func (c *FileTailCollector) Name() string {
+	return "file"
+}
+
+// runDiscovery re-evaluates the glob patterns every interval.
+func (c *FileTailCollector) runDiscovery() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			c.discover(false)
+			c.saveState()
+		}
+	}
+}
+
+// discover expands the patterns, starts tailers for new matches (up to the
+// file tailer cap) and stops tailers whose files no longer match.
+func (c *FileTailCollector) discover(initial bool) {
+	matched := make(map[string]struct{})
+	for _, pattern := range c.patterns {
+		paths, err := filepath.Glob(pattern)
+		if err != nil {
+			utils.Warn("Invalid file log pattern %q: %v", pattern, err)
+			continue
+		}
+		for _, p := range paths {
+			matched[p] = struct{}{}
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Stop tailers for files that have gone away
+	for path, t := range c.tailers {
+		if _, ok := matched[path]; ok {
+			continue
+		}
+		utils.Info("Log file %s no longer matches; stopping tailer", path)
+		_ = t.Stop()
+		t.Cleanup()
+		delete(c.tailers, path)
+		c.offMu.Lock()
+		delete(c.offsets, path)
+		c.offMu.Unlock()
+	}
+
+	// Start tailers for new files, in a stable order so the cap is deterministic
+	var newPaths []string
+	for path := range matched {
+		if _, ok := c.tailers[path]; !ok {
+			newPaths = append(newPaths, path)
+		}
+	}
+	sort.Strings(newPaths)
+	if remaining := c.maxFiles - len(c.tailers); c.maxFiles > 0 && remaining <= 0 {
+		// ApplyLimit treats a limit <= 0 as unlimited
+		if len(newPaths) > 0 {
+			utils.Warn("%d new log files not tailed: already at the limit of %d (raise agent.limits.max_file_tailers to allow more)",
+				len(newPaths), c.maxFiles)
+		}
+		newPaths = nil
+	} else {
+		newPaths = agentutils.ApplyLimit("file tailers", "max_file_tailers", newPaths, remaining)
+	}
+
+	for _, path := range newPaths {
+		c.offMu.Lock()
+		saved, haveSaved := c.offsets[path]
+		c.offMu.Unlock()
+		t, err := tail.TailFile(path, tail.Config{
+			Location:  startLocation(path, saved, haveSaved, initial),
+			ReOpen:    true,
+			MustExist: true,
+			Follow:    true,
+			Logger:    tail.DiscardingLogger,
+		})
+		if err != nil {
+			utils.Warn("Failed to tail %s: %v", path, err)
+			continue
+		}
+		c.tailers[path] = t
+
+		c.wg.Add(1)
+		go c.runTailer(path, t)
+		utils.Info("Started tailing log file: %s", path)
+	}
+}
+
+// runTailer forwards lines from one tailer onto the shared lines channel,
+// collapsing repeated lines when enabled for the "file" source.
+func (c *FileTailCollector) runTailer(path string, t *tail.Tail) {
+	defer c.wg.Done()
+
+	collapser := repeat.New(c.repeat, "file")
+	flush := time.NewTicker(time.Second)
+	defer flush.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case now := <-flush.C:
+			c.forward(path, collapser.Flush(now))
+		case line, ok := <-t.Lines:
+			if !ok {
+				return
+			}
+			if line.Err != nil {
+				utils.Warn("Error reading line from %s: %v", path, line.Err)
+				continue
+			}
+			c.trackOffset(path, line.SeekInfo.Offset)
+			if strings.TrimSpace(line.Text) == "" {
+				continue
+			}
+
+			c.forward(path, collapser.Add(c.buildLogEntry(path, line)))
+		}
+	}
+}
+
+// trackOffset records the read position in path. The file identity is
+// re-read when it is unknown or the offset went backwards, which is what
+// tail's reopen after a rotation or truncation looks like.
+func (c *FileTailCollector) trackOffset(path string, offset int64) {
+	if c.stateFile == "" {
+		return
+	}
+	c.offMu.Lock()
+	defer c.offMu.Unlock()
+
+	cur, ok := c.offsets[path]
+	if !ok || cur.ID == 0 || offset < cur.Offset {
+		cur.ID = statID(path)
+	}
+	cur.Offset = offset
+	c.offsets[path] = cur
+}
+
+// saveState persists the read offsets to the state file, if configured.
+func (c *FileTailCollector) saveState() {
+	if c.stateFile == "" {
+		return
+	}
+	c.offMu.Lock()
+	snapshot := make(map[string]fileOffset, len(c.offsets))
+	for path, off := range c.offsets {
+		snapshot[path] = off
+	}
+	c.offMu.Unlock()
+
+	if err := saveOffsets(c.stateFile, snapshot); err != nil {
+		utils.Warn("Failed to save file log state %s: %v", c.stateFile, err)
+	}
+}
+
+// forward puts entries on the lines channel, dropping them if it is full.
+func (c *FileTailCollector) forward(path string, entries []model.LogEntry) {
+	for _, entry := range entries {
+		select {
+		case c.lines <- entry:
+		default:
+			utils.Warn("Log buffer full for %s. Dropping log entry", path)
+		}
+	}
+}
+
+// buildLogEntry converts a tailed line into a LogEntry.
+func (c *FileTailCollector) buildLogEntry(path string, line *tail.Line) model.LogEntry {
+	msg := line.Text
+	if c.maxMsgSize > 0 && len(msg) > c.maxMsgSize {
+		msg = msg[:c.maxMsgSize] + " [truncated]"
+	}
+
+	ts := line.Time
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+
+	return model.LogEntry{
+		Timestamp: ts,
+		Level:     "info",
+		Message:   msg,
+		Source:    "file",
+		Category:  "application",
+		Labels: map[string]string{
+			"log_path": path,
+		},
+		Meta: &model.LogMeta{
+			Platform: "file",
+			AppName:  filepath.Base(filepath.Dir(path)),
+			Path:     path,
+		},
+	}
+}
+
+// Collect drains buffered lines into batches of at most BatchSize entries.
+func (c *FileTailCollector) Collect(_ context.Context) ([][]model.LogEntry, error) {
+	var batches [][]model.LogEntry
+	batch := make([]model.LogEntry, 0, c.batchSize)
+
+drain:
+	for {
+		select {
+		case entry := <-c.lines:
+			batch = append(batch, entry)
+			if len(batch) >= c.batchSize {
+				batches = append(batches, batch)
+				batch = make([]model.LogEntry, 0, c.batchSize)
+			}
+		default:
+			break drain
+		}
+	}
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+	return batches, nil
+}
+
+// Close stops discovery and all tailers.
+func (c *FileTailCollector) Close() error {
+	c.once.Do(func() {
+		close(c.stop)
+
+		c.mu.Lock()
+		for path, t := range c.tailers {
+			_ = t.Stop()
+			t.Cleanup()
+			delete(c.tailers, path)
+		}
+		c.mu.Unlock()
+
+		c.wg.Wait()
+		c.saveState()
+		utils.Info("File log collector closed")
+	})
+	return nil
+}
@@ -0,0 +1,62 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+package filecollector
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aaronlmathis/gosight-agent/internal/config"
+)
+
+func TestDiscoverStopsAtFileTailerLimit(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("line\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 3; i++ {
+		write(fmt.Sprintf("app%d.log", i))
+	}
+
+	cfg := &config.Config{}
+	cfg.Agent.LogCollection.Files.Paths = []string{filepath.Join(dir, "*.log")}
+	cfg.Agent.LogCollection.Files.DiscoveryInterval = time.Hour
+	cfg.Agent.Limits.MaxFileTailers = 2
+	c := NewFileTailCollector(cfg)
+	defer c.Close()
+
+	if n := len(c.tailers); n != 2 {
+		t.Fatalf("after startup: %d tailers, want 2", n)
+	}
+
+	// Already at the limit: a new file must not start another tailer.
+	write("app3.log")
+	c.discover(false)
+	if n := len(c.tailers); n != 2 {
+		t.Errorf("after discovery at the limit: %d tailers, want 2", n)
+	}
+}
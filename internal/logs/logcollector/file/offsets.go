@@ -0,0 +1,110 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// internal/logs/logcollector/file/offsets.go
+// offsets.go tracks how far each tailed file has been read and persists it,
+// so a restart resumes where the agent stopped.
+
+package filecollector
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+
+	"github.com/aaronlmathis/gosight-shared/utils"
+	"github.com/nxadm/tail"
+)
+
+// fileOffset is the read position in one file. ID identifies the file (its
+// inode on Unix) so a file rotated while the agent was down is detected.
+type fileOffset struct {
+	ID     uint64 `json:"id"`
+	Offset int64  `json:"offset"`
+}
+
+// loadOffsets reads the saved offsets. A missing file yields none; an
+// unreadable one is logged and ignored.
+func loadOffsets(path string) map[string]fileOffset {
+	offsets := make(map[string]fileOffset)
+	if path == "" {
+		return offsets
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return offsets
+	}
+	if err == nil {
+		err = json.Unmarshal(data, &offsets)
+	}
+	if err != nil {
+		utils.Warn("Ignoring unreadable file log state %s: %v", path, err)
+		return make(map[string]fileOffset)
+	}
+	return offsets
+}
+
+// saveOffsets writes offsets atomically (temp file + rename).
+func saveOffsets(path string, offsets map[string]fileOffset) error {
+	data, err := json.Marshal(offsets)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// startLocation decides where tailing of path begins. A saved offset is used
+// if the file is still the same one and has not shrunk below it; a file that
+// was replaced (rotated) is read from the start. Without a saved offset,
+// files present at startup are tailed from their end and files discovered
+// later from the start.
+func startLocation(path string, saved fileOffset, haveSaved, initial bool) *tail.SeekInfo {
+	if haveSaved {
+		if info, err := os.Stat(path); err == nil {
+			if fileID(info) == saved.ID && saved.Offset <= info.Size() {
+				return &tail.SeekInfo{Offset: saved.Offset, Whence: io.SeekStart}
+			}
+			return &tail.SeekInfo{Offset: 0, Whence: io.SeekStart}
+		}
+	}
+	if initial {
+		return &tail.SeekInfo{Offset: 0, Whence: io.SeekEnd}
+	}
+	return &tail.SeekInfo{Offset: 0, Whence: io.SeekStart}
+}
+
+// statID returns the identity of the file currently at path, or 0.
+func statID(path string) uint64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return fileID(info)
+}
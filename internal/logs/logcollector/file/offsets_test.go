@@ -0,0 +1,69 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+package filecollector
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStartLocationAndOffsetsRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "app.log")
+	if err := os.WriteFile(logPath, []byte("one\ntwo\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	id := statID(logPath)
+
+	// No saved offset: startup tails from the end, later discovery from the start.
+	if loc := startLocation(logPath, fileOffset{}, false, true); loc.Whence != io.SeekEnd {
+		t.Errorf("initial without state: whence = %d, want SeekEnd", loc.Whence)
+	}
+	if loc := startLocation(logPath, fileOffset{}, false, false); loc.Whence != io.SeekStart || loc.Offset != 0 {
+		t.Errorf("discovered without state: got %+v, want start", loc)
+	}
+
+	// Same file: resume at the saved offset.
+	if loc := startLocation(logPath, fileOffset{ID: id, Offset: 4}, true, true); loc.Whence != io.SeekStart || loc.Offset != 4 {
+		t.Errorf("same file: got %+v, want offset 4", loc)
+	}
+
+	// Shrunk below the saved offset (truncated): read from the start.
+	if loc := startLocation(logPath, fileOffset{ID: id, Offset: 100}, true, true); loc.Offset != 0 || loc.Whence != io.SeekStart {
+		t.Errorf("truncated file: got %+v, want start", loc)
+	}
+
+	stateFile := filepath.Join(dir, "state.json")
+	want := map[string]fileOffset{logPath: {ID: id, Offset: 4}}
+	if err := saveOffsets(stateFile, want); err != nil {
+		t.Fatal(err)
+	}
+	got := loadOffsets(stateFile)
+	if got[logPath] != want[logPath] {
+		t.Errorf("loadOffsets = %+v, want %+v", got, want)
+	}
+	if len(loadOffsets(filepath.Join(dir, "missing.json"))) != 0 {
+		t.Error("missing state file should yield no offsets")
+	}
+}
@@ -0,0 +1,108 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// internal/logs/logcollector/linux/escalate.go
+// Escalates journal entries from configured units (e.g. security daemons).
+
+package linuxcollector
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/aaronlmathis/gosight-agent/internal/config"
+	"github.com/aaronlmathis/gosight-shared/model"
+)
+
+// Defaults applied when an escalation leaves Level or Tags empty.
+const defaultEscalationLevel = "error"
+
+var defaultEscalationTags = map[string]string{"critical": "true"}
+
+// unitEscalation is a compiled escalation rule.
+type unitEscalation struct {
+	pattern string
+	level   string
+	tags    map[string]string
+}
+
+// compileEscalations turns the configured unit-pattern map into rules sorted
+// by pattern, so overlapping patterns resolve deterministically.
+func compileEscalations(cfg map[string]config.LogEscalation) []unitEscalation {
+	rules := make([]unitEscalation, 0, len(cfg))
+	for pattern, esc := range cfg {
+		rule := unitEscalation{pattern: pattern, level: esc.Level, tags: esc.Tags}
+		if rule.level == "" {
+			rule.level = defaultEscalationLevel
+		}
+		if len(rule.tags) == 0 {
+			rule.tags = defaultEscalationTags
+		}
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].pattern < rules[j].pattern })
+	return rules
+}
+
+// exactUnits returns the patterns that contain no glob characters. These are
+// added as journal matches so their entries are read regardless of priority.
+func exactUnits(rules []unitEscalation) []string {
+	var units []string
+	for _, r := range rules {
+		if !strings.ContainsAny(r.pattern, "*?[") {
+			units = append(units, r.pattern)
+		}
+	}
+	return units
+}
+
+// escalate forces the level and adds the tags of the first rule whose
+// pattern matches the entry's systemd unit (or syslog identifier).
+func escalate(entry *model.LogEntry, rules []unitEscalation) {
+	if len(rules) == 0 {
+		return
+	}
+	unit := entry.Fields["SYSTEMD_UNIT"]
+	ident := entry.Fields["SYSLOG_IDENTIFIER"]
+
+	for _, r := range rules {
+		if !matchUnit(r.pattern, unit) && !matchUnit(r.pattern, ident) {
+			continue
+		}
+		entry.Level = r.level
+		if entry.Labels == nil {
+			entry.Labels = make(map[string]string)
+		}
+		for k, v := range r.tags {
+			entry.Labels[k] = v
+		}
+		return
+	}
+}
+
+func matchUnit(pattern, name string) bool {
+	if name == "" {
+		return false
+	}
+	ok, _ := path.Match(pattern, name)
+	return ok
+}
@@ -35,6 +35,8 @@ import (
 	"unicode/utf8"
 
 	"github.com/aaronlmathis/gosight-agent/internal/config"
+	"github.com/aaronlmathis/gosight-agent/internal/logs/logcollector/repeat"
+	agentutils "github.com/aaronlmathis/gosight-agent/internal/utils"
 	"github.com/aaronlmathis/gosight-shared/model"
 	"github.com/aaronlmathis/gosight-shared/utils"
 	"github.com/coreos/go-systemd/v22/sdjournal"
@@ -53,6 +55,14 @@ type JournaldCollector struct {
 	cleanupErr error
 	batchSize  int
 	maxSize    int
+
+	// cursorFile persists the cursor of the last entry read, so a restart
+	// resumes where the agent stopped. Empty = always start at the tail.
+	cursorFile string
+
+	escalations []unitEscalation // units whose entries are escalated (see escalate.go)
+
+	includeKernel bool // forward kernel messages (SYSLOG_IDENTIFIER=kernel)
 }
 
 // Name returns the name of the collector.
@@ -69,47 +79,45 @@ func NewJournaldCollector(cfg *config.Config) *JournaldCollector {
 		return &JournaldCollector{} // Return disabled collector
 	}
 
-	// Filter for relevant priorities (e.g., INFO and higher)
-	// Adjust priorities as needed (0=emerg, 1=alert, 2=crit, 3=err, 4=warn, 5=notice, 6=info, 7=debug)
-	// Example: Include warning and higher
-	for _, prio := range []string{"0", "1", "2", "3", "4"} {
-		match := sdjournal.Match{Field: sdjournal.SD_JOURNAL_FIELD_PRIORITY, Value: prio}
-		if err := j.AddMatch(match.String()); err != nil {
-			utils.Warn("Failed to add journal priority match %s: %v", prio, err)
-			// Continue anyway, might just get more logs
+	// Filter on the configured priorities and units. Entries from escalated
+	// units are read at every priority, so an info-level message from e.g.
+	// sshd can still be escalated. Glob patterns cannot be expressed as
+	// journal matches and only see the configured priorities.
+	jcfg := cfg.Agent.LogCollection.Journald
+	escalations := compileEscalations(jcfg.Escalate)
+	for _, term := range journalFilter(jcfg.Priorities, jcfg.Units, jcfg.IncludeKernel, exactUnits(escalations)) {
+		for _, match := range term {
+			if err := j.AddMatch(match.String()); err != nil {
+				utils.Warn("Failed to add journal match %s: %v", match.String(), err)
+				// Continue anyway, might just get more logs
+			}
 		}
-		// Disjunction means OR - we want logs with PRIORITY=0 OR PRIORITY=1 OR ...
+		// Disjunction means OR - the next term is an alternative to this one
 		if err := j.AddDisjunction(); err != nil {
 			utils.Warn("Failed to add journal disjunction: %v", err)
 		}
 	}
-	// Add more filters if needed (e.g., specific units)
-	// j.AddMatch("_SYSTEMD_UNIT=nginx.service")
 
-	// Seek to end to skip historical logs
-	if err := j.SeekTail(); err != nil {
-		utils.Error("Failed to seek journal to tail: %v. Collector might report old logs.", err)
-		// Attempt to continue, but logs might be duplicated or old
-	} else {
-		// Seeking to the tail places the cursor *at* the last entry.
-		// We need to move *past* it to only get new entries.
-		// Calling Next() achieves this. Ignore result/error, just advance position.
-		_, _ = j.Previous() // Move to the last entry
-		// Note: Seeking tail and then immediately moving previous places cursor just before last entry
-		// Waiting for the next event after this should fetch truly new logs.
-		// Or alternatively, keep the j.Next() from the original code after SeekTail if that works better.
-		// Let's stick with SeekTail and rely on Wait() picking up the next *new* event.
+	// Resume after the last entry read before the agent stopped, or seek to
+	// the end to skip historical logs
+	cursorFile := cfg.Agent.LogCollection.Journald.CursorFile
+	if !seekSavedCursor(j, cursorFile) {
+		seekTail(j)
 	}
 
 	collector := &JournaldCollector{
-		Config:  cfg,
-		journal: j,
+		Config:      cfg,
+		journal:     j,
+		escalations: escalations,
 		// Buffer size: batchSize * some multiplier or configurable
 		lines: make(chan model.LogEntry, cfg.Agent.LogCollection.BatchSize*10),
 		stop:  make(chan struct{}),
 
-		batchSize: cfg.Agent.LogCollection.BatchSize,
-		maxSize:   cfg.Agent.LogCollection.MessageMax,
+		batchSize:  cfg.Agent.LogCollection.BatchSize,
+		maxSize:    cfg.Agent.LogCollection.MessageMax,
+		cursorFile: cursorFile,
+
+		includeKernel: jcfg.IncludeKernel,
 	}
 
 	// Start the background reader goroutine
@@ -127,6 +135,7 @@ func (j *JournaldCollector) runReader() {
 		// Ensure journal is closed if goroutine exits unexpectedly
 		j.mu.Lock()
 		if j.journal != nil {
+			j.saveCursor()
 			utils.Debug("Closing journal handle in runReader defer.")
 			j.journal.Close()
 			j.journal = nil
@@ -143,6 +152,13 @@ func (j *JournaldCollector) runReader() {
 	// timely checking of the stop channel.
 	waitTimeout := 2 * time.Second // Check stop channel every 2 seconds
 
+	// Collapses repeated lines when enabled for "journald"; runs that end are
+	// flushed after each Wait, so at most waitTimeout late
+	collapser := repeat.New(j.Config.Agent.LogCollection.Repeat, "journald")
+
+	// read is set once entries were read since the cursor was last saved
+	read := false
+
 	for {
 		// Wait blocks until the journal changes, or the timeout occurs.
 		// Returns 1 if journal changed, 0 if timeout, -1 on error.
@@ -163,6 +179,10 @@ func (j *JournaldCollector) runReader() {
 			return // Exit loop on error
 		}
 
+		if !j.forward(collapser.Flush(time.Now())) {
+			return
+		}
+
 		// If Wait timed out (ret == 0) or journal changed (ret == 1),
 		// try processing entries. This loop handles the case where multiple
 		// entries arrived during the Wait or timeout.
@@ -174,9 +194,14 @@ func (j *JournaldCollector) runReader() {
 				return // Exit loop on error
 			}
 			if n == 0 {
-				// No more new entries currently available
+				// No more new entries currently available; remember how far we got
+				if read {
+					j.saveCursor()
+					read = false
+				}
 				break // Exit inner processing loop, go back to Wait
 			}
+			read = true
 
 			// Successfully read an entry, get its data
 			entry, err := j.journal.GetEntry()
@@ -185,30 +210,39 @@ func (j *JournaldCollector) runReader() {
 				continue // Skip this entry, try next
 			}
 
-			// Filter out kernel messages if desired (as in original code)
-			// Could be made configurable
-			if entry.Fields["SYSLOG_IDENTIFIER"] == "kernel" {
+			// Kernel messages are left to the kmsg source unless asked for
+			if !j.includeKernel && entry.Fields["SYSLOG_IDENTIFIER"] == "kernel" {
 				continue
 			}
 
 			// Parse and build the log entry
-			log := buildLogEntry(entry, j.maxSize)
-
-			// Send parsed entry to buffer channel, non-blockingly
-			select {
-			case j.lines <- log:
-				// Successfully sent
-			case <-j.stop: // Check stop again in case it happened during processing
-				utils.Info("Stop signal received while processing journal entry.")
+			log := buildLogEntry(entry, j.maxSize, j.escalations)
+
+			if !j.forward(collapser.Add(log)) {
 				return
-			default:
-				// Buffer full, drop log and warn
-				utils.Warn("Journald log buffer full. Dropping log entry: %s", log.Message)
 			}
 		} // End inner processing loop
 	} // End outer wait loop
 }
 
+// forward sends parsed entries to the buffer channel, non-blockingly.
+// It returns false if the collector was stopped meanwhile.
+func (j *JournaldCollector) forward(entries []model.LogEntry) bool {
+	for _, log := range entries {
+		select {
+		case j.lines <- log:
+			// Successfully sent
+		case <-j.stop: // Check stop again in case it happened during processing
+			utils.Info("Stop signal received while processing journal entry.")
+			return false
+		default:
+			// Buffer full, drop log and warn
+			utils.Warn("Journald log buffer full. Dropping log entry: %s", log.Message)
+		}
+	}
+	return true
+}
+
 // Collect drains the internal 'lines' channel and batches the entries.
 func (j *JournaldCollector) Collect(ctx context.Context) ([][]model.LogEntry, error) {
 	// Check if collector is disabled (e.g., journal handle is nil)
@@ -282,7 +316,8 @@ collectLoop:
 	return allBatches, nil
 }
 
-// Close stops the background reader and closes the journal handle.
+// Close stops the background reader and closes the journal handle. The
+// reader saves the final cursor to the cursor file before closing the journal.
 // Implements io.Closer.
 func (j *JournaldCollector) Close() error {
 	j.once.Do(func() {
@@ -308,6 +343,102 @@ func (j *JournaldCollector) Close() error {
 	return j.cleanupErr
 }
 
+// seekSavedCursor positions j just past the entry recorded in cursorFile. It
+// returns false, after logging a warning if the file was unusable, when the
+// caller should fall back to the tail.
+func seekSavedCursor(j *sdjournal.Journal, cursorFile string) bool {
+	if cursorFile == "" {
+		return false
+	}
+	cursor, err := agentutils.LoadCursor(cursorFile)
+	if err != nil {
+		utils.Warn("Failed to read journald cursor file %s: %v. Starting at the end of the journal.", cursorFile, err)
+		return false
+	}
+	if cursor == "" {
+		return false
+	}
+	if err := j.SeekCursor(cursor); err != nil {
+		utils.Warn("Invalid journald cursor in %s: %v. Starting at the end of the journal.", cursorFile, err)
+		return false
+	}
+
+	// SeekCursor positions before the entry; step onto it so the reader's
+	// first Next() returns the entry after it. If the entry itself has been
+	// rotated away, the journal lands on the closest later entry, which has
+	// not been read yet, so step back again.
+	if n, err := j.Next(); err != nil || n == 0 {
+		return true
+	}
+	if err := j.TestCursor(cursor); err != nil {
+		_, _ = j.Previous()
+	}
+	utils.Info("Resuming journald collection from saved cursor in %s", cursorFile)
+	return true
+}
+
+// seekTail positions j at the end of the journal so only new entries are read.
+func seekTail(j *sdjournal.Journal) {
+	if err := j.SeekTail(); err != nil {
+		utils.Error("Failed to seek journal to tail: %v. Collector might report old logs.", err)
+		// Attempt to continue, but logs might be duplicated or old
+		return
+	}
+	// Seeking to the tail places the cursor after the last entry; moving to
+	// it means Wait() followed by Next() picks up the next *new* event.
+	_, _ = j.Previous()
+}
+
+// saveCursor writes the cursor of the current entry to the cursor file. It
+// must be called from the reader goroutine, which owns the journal handle.
+func (j *JournaldCollector) saveCursor() {
+	if j.cursorFile == "" || j.journal == nil {
+		return
+	}
+	cursor, err := j.journal.GetCursor()
+	if err != nil || cursor == "" {
+		return
+	}
+	if err := agentutils.SaveCursor(j.cursorFile, cursor); err != nil {
+		utils.Warn("Failed to save journald cursor to %s: %v", j.cursorFile, err)
+	}
+}
+
+// defaultJournalPriorities are read when journald.priorities is empty:
+// emerg through warning.
+var defaultJournalPriorities = []int{0, 1, 2, 3, 4}
+
+// journalFilter builds the journal match terms for the collector. Matches in
+// a term on the same field are OR'd and on different fields AND'd; the terms
+// themselves are OR'd. With a units filter, kernel messages (which have no
+// unit) get a term of their own when includeKernel is set.
+func journalFilter(priorities []int, units []string, includeKernel bool, escalated []string) [][]sdjournal.Match {
+	if len(priorities) == 0 {
+		priorities = defaultJournalPriorities
+	}
+	prio := make([]sdjournal.Match, 0, len(priorities))
+	for _, p := range priorities {
+		prio = append(prio, sdjournal.Match{Field: sdjournal.SD_JOURNAL_FIELD_PRIORITY, Value: strconv.Itoa(p)})
+	}
+
+	terms := [][]sdjournal.Match{prio}
+	if len(units) > 0 {
+		term := append([]sdjournal.Match{}, prio...)
+		for _, unit := range units {
+			term = append(term, sdjournal.Match{Field: sdjournal.SD_JOURNAL_FIELD_SYSTEMD_UNIT, Value: unit})
+		}
+		terms = [][]sdjournal.Match{term}
+		if includeKernel {
+			kernel := append([]sdjournal.Match{}, prio...)
+			terms = append(terms, append(kernel, sdjournal.Match{Field: sdjournal.SD_JOURNAL_FIELD_TRANSPORT, Value: "kernel"}))
+		}
+	}
+	for _, unit := range escalated {
+		terms = append(terms, []sdjournal.Match{{Field: sdjournal.SD_JOURNAL_FIELD_SYSTEMD_UNIT, Value: unit}})
+	}
+	return terms
+}
+
 // mapPriorityToLevel maps systemd journal priority levels to log levels.
 func mapPriorityToLevel(priority string) string {
 	switch priority {
@@ -331,7 +462,9 @@ func mapPriorityToLevel(priority string) string {
 // buildLogEntry constructs a LogEntry from a systemd journal entry.
 // Fields: cleaned journald fields (no `_` prefix)
 // Meta.Extra: original raw journald fields for advanced filtering/debugging
-func buildLogEntry(entry *sdjournal.JournalEntry, maxSize int) model.LogEntry {
+// Entries from units listed under log_collection.journald.escalate get a
+// forced level and extra labels (see escalate).
+func buildLogEntry(entry *sdjournal.JournalEntry, maxSize int, escalations []unitEscalation) model.LogEntry {
 
 	timestamp := time.Unix(0, int64(entry.RealtimeTimestamp)*int64(time.Microsecond))
 
@@ -392,7 +525,7 @@ func buildLogEntry(entry *sdjournal.JournalEntry, maxSize int) model.LogEntry {
 		}
 	}
 
-	return model.LogEntry{
+	log := model.LogEntry{
 		Timestamp: timestamp,
 		Level:     mapPriorityToLevel(entry.Fields["PRIORITY"]),
 		Message:   msg,
@@ -412,6 +545,9 @@ func buildLogEntry(entry *sdjournal.JournalEntry, maxSize int) model.LogEntry {
 			Extra:         extra, // Keep all raw fields for potential future use
 		},
 	}
+	escalate(&log, escalations)
+
+	return log
 }
 
 // parsePID converts a string representation of a PID to an integer.
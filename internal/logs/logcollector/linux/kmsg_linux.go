@@ -0,0 +1,306 @@
+//go:build linux
+// +build linux
+
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+// internal/logs/logcollector/linux/kmsg_linux.go
+// KmsgCollector follows the kernel ring buffer through /dev/kmsg, which
+// supports non-destructive reads (unlike klogctl) and delivers one record
+// per read. OOM kills, hardware and filesystem errors are reported here,
+// and the journald collector skips kernel messages.
+package linuxcollector
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/aaronlmathis/gosight-agent/internal/config"
+	"github.com/aaronlmathis/gosight-agent/internal/logs/logcollector/repeat"
+	"github.com/aaronlmathis/gosight-shared/model"
+	"github.com/aaronlmathis/gosight-shared/utils"
+	"golang.org/x/sys/unix"
+)
+
+const kmsgPath = "/dev/kmsg"
+
+// kmsgFacilities names the syslog facilities in record priorities.
+var kmsgFacilities = []string{
+	"kern", "user", "mail", "daemon", "auth", "syslog", "lpr", "news",
+	"uucp", "cron", "authpriv", "ftp", "ntp", "security", "console", "solaris-cron",
+	"local0", "local1", "local2", "local3", "local4", "local5", "local6", "local7",
+}
+
+// KmsgCollector reads kernel messages from /dev/kmsg. Only records written
+// after the agent started are read.
+type KmsgCollector struct {
+	Config     *config.Config
+	maxMsgSize int
+	batchSize  int
+	bootTime   time.Time
+
+	file  *os.File
+	lines chan model.LogEntry
+	stop  chan struct{}
+	wg    sync.WaitGroup
+	once  sync.Once
+}
+
+// NewKmsgCollector opens /dev/kmsg positioned after the last existing record
+// and starts reading. If the device cannot be opened (e.g. missing
+// CAP_SYSLOG with kernel.dmesg_restrict=1), the collector is disabled.
+func NewKmsgCollector(cfg *config.Config) *KmsgCollector {
+	batchSize := cfg.Agent.LogCollection.BatchSize
+	if batchSize <= 0 {
+		batchSize = 50
+	}
+	c := &KmsgCollector{
+		Config:     cfg,
+		maxMsgSize: cfg.Agent.LogCollection.MessageMax,
+		batchSize:  batchSize,
+		bootTime:   kmsgBootTime(),
+		lines:      make(chan model.LogEntry, batchSize*10),
+		stop:       make(chan struct{}),
+	}
+
+	// Non-blocking so reads go through the runtime poller and Close unblocks them
+	f, err := os.OpenFile(kmsgPath, os.O_RDONLY|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		utils.Warn("Cannot open %s: %v. Kernel log collector disabled.", kmsgPath, err)
+		return c
+	}
+	if _, err := f.Seek(0, io.SeekEnd); err != nil {
+		utils.Warn("Failed to seek to the end of %s: %v", kmsgPath, err)
+	}
+	c.file = f
+
+	c.wg.Add(1)
+	go c.run()
+
+	utils.Info("Started reading kernel messages from %s", kmsgPath)
+	return c
+}
+
+// kmsgBootTime returns the wall-clock time of the monotonic clock's zero,
+// which /dev/kmsg timestamps are relative to.
+func kmsgBootTime() time.Time {
+	var ts unix.Timespec
+	if err := unix.ClockGettime(unix.CLOCK_MONOTONIC, &ts); err != nil {
+		return time.Time{}
+	}
+	return time.Now().Add(-time.Duration(ts.Nano()))
+}
+
+// run reads one record per read until Close.
+func (c *KmsgCollector) run() {
+	defer c.wg.Done()
+
+	records := make(chan string, 64)
+	go func() {
+		defer close(records)
+		buf := make([]byte, 8192)
+		for {
+			n, err := c.file.Read(buf)
+			if err != nil {
+				// EPIPE means records were overwritten before we read them
+				if errors.Is(err, syscall.EPIPE) {
+					utils.Debug("Kernel ring buffer overran; some messages were lost")
+					continue
+				}
+				if !errors.Is(err, os.ErrClosed) {
+					utils.Error("Error reading %s: %v", kmsgPath, err)
+				}
+				return
+			}
+			select {
+			case records <- string(buf[:n]):
+			case <-c.stop:
+				return
+			}
+		}
+	}()
+
+	collapser := repeat.New(c.Config.Agent.LogCollection.Repeat, "kmsg")
+	flush := time.NewTicker(time.Second)
+	defer flush.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case now := <-flush.C:
+			c.forward(collapser.Flush(now))
+		case rec, ok := <-records:
+			if !ok {
+				return
+			}
+			entry, ok := parseKmsgRecord(rec, c.bootTime, c.maxMsgSize)
+			if !ok {
+				continue
+			}
+			c.forward(collapser.Add(entry))
+		}
+	}
+}
+
+// forward buffers entries, dropping them if Collect has fallen behind.
+func (c *KmsgCollector) forward(entries []model.LogEntry) {
+	for _, entry := range entries {
+		select {
+		case c.lines <- entry:
+		default:
+			utils.Warn("Kernel log buffer full. Dropping log entry: %s", entry.Message)
+		}
+	}
+}
+
+// parseKmsgRecord parses a /dev/kmsg record:
+//
+//	PRI,SEQ,TIMESTAMP_USEC,FLAGS[,...];MESSAGE
+//	 KEY=VALUE
+//
+// PRI combines facility and level (facility*8 + level). The optional
+// continuation lines (e.g. SUBSYSTEM=, DEVICE=) become Fields.
+func parseKmsgRecord(rec string, bootTime time.Time, maxMsgSize int) (model.LogEntry, bool) {
+	rec = strings.TrimRight(rec, "\n")
+	header, body, ok := strings.Cut(rec, ";")
+	if !ok {
+		return model.LogEntry{}, false
+	}
+	parts := strings.Split(header, ",")
+	if len(parts) < 3 {
+		return model.LogEntry{}, false
+	}
+	pri, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return model.LogEntry{}, false
+	}
+	usec, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return model.LogEntry{}, false
+	}
+
+	lines := strings.Split(body, "\n")
+	msg := lines[0]
+	if msg == "" {
+		return model.LogEntry{}, false
+	}
+	if maxMsgSize > 0 && len(msg) > maxMsgSize {
+		msg = msg[:maxMsgSize] + " [truncated]"
+	}
+
+	fields := map[string]string{"SEQNUM": parts[1]}
+	for _, l := range lines[1:] {
+		if k, v, ok := strings.Cut(strings.TrimPrefix(l, " "), "="); ok && k != "" {
+			fields[k] = v
+		}
+	}
+
+	facility := "unknown"
+	if f := pri >> 3; f < len(kmsgFacilities) {
+		facility = kmsgFacilities[f]
+	}
+
+	ts := time.Now()
+	if !bootTime.IsZero() {
+		ts = bootTime.Add(time.Duration(usec) * time.Microsecond)
+	}
+
+	return model.LogEntry{
+		Timestamp: ts,
+		Level:     mapPriorityToLevel(strconv.Itoa(pri & 7)),
+		Message:   msg,
+		Source:    "kernel",
+		Category:  kmsgCategory(facility),
+		Fields:    fields,
+		Labels: map[string]string{
+			"facility": facility,
+		},
+		Meta: &model.LogMeta{
+			Platform: "kmsg",
+			AppName:  "kernel",
+			Path:     kmsgPath,
+		},
+	}, true
+}
+
+// kmsgCategory maps a facility onto the categories used by the other collectors.
+func kmsgCategory(facility string) string {
+	switch facility {
+	case "auth", "authpriv", "security":
+		return "auth"
+	default:
+		return "system"
+	}
+}
+
+// Name returns the name of the collector.
+func (c *KmsgCollector) Name() string {
+	return "kmsg"
+}
+
+// Collect drains buffered entries into batches of at most BatchSize entries.
+func (c *KmsgCollector) Collect(_ context.Context) ([][]model.LogEntry, error) {
+	if c.file == nil {
+		return nil, nil
+	}
+
+	var batches [][]model.LogEntry
+	batch := make([]model.LogEntry, 0, c.batchSize)
+
+drain:
+	for {
+		select {
+		case entry := <-c.lines:
+			batch = append(batch, entry)
+			if len(batch) >= c.batchSize {
+				batches = append(batches, batch)
+				batch = make([]model.LogEntry, 0, c.batchSize)
+			}
+		default:
+			break drain
+		}
+	}
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+	return batches, nil
+}
+
+// Close stops reading and closes /dev/kmsg.
+func (c *KmsgCollector) Close() error {
+	if c.file == nil {
+		return nil
+	}
+	c.once.Do(func() {
+		close(c.stop)
+		_ = c.file.Close()
+		c.wg.Wait()
+		utils.Info("Kernel log collector closed")
+	})
+	return nil
+}
@@ -0,0 +1,60 @@
+//go:build linux
+// +build linux
+
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+package linuxcollector
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseKmsgRecord(t *testing.T) {
+	boot := time.Unix(1700000000, 0)
+	rec := "3,1042,5000000,-;EXT4-fs error (device sda1): ext4_find_entry:1455: comm ls: reading directory lblock 0\n SUBSYSTEM=block\n DEVICE=b8:1\n"
+
+	e, ok := parseKmsgRecord(rec, boot, 0)
+	if !ok {
+		t.Fatal("record not parsed")
+	}
+	if e.Level != "error" || e.Source != "kernel" || e.Labels["facility"] != "kern" || e.Category != "system" {
+		t.Errorf("unexpected entry: %+v", e)
+	}
+	if e.Message != "EXT4-fs error (device sda1): ext4_find_entry:1455: comm ls: reading directory lblock 0" {
+		t.Errorf("message = %q", e.Message)
+	}
+	if !e.Timestamp.Equal(boot.Add(5 * time.Second)) {
+		t.Errorf("timestamp = %v", e.Timestamp)
+	}
+	if e.Fields["SUBSYSTEM"] != "block" || e.Fields["DEVICE"] != "b8:1" || e.Fields["SEQNUM"] != "1042" {
+		t.Errorf("fields = %v", e.Fields)
+	}
+
+	// Priority 38 = facility auth (4), level info (6)
+	if e, ok := parseKmsgRecord("38,1,1,-;session opened", boot, 0); !ok || e.Labels["facility"] != "auth" || e.Level != "info" || e.Category != "auth" {
+		t.Errorf("unexpected auth entry: %+v", e)
+	}
+	if _, ok := parseKmsgRecord("garbage", boot, 0); ok {
+		t.Error("malformed record parsed")
+	}
+}
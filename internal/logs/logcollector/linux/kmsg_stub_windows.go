@@ -0,0 +1,42 @@
+//go:build windows
+// +build windows
+
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis
+
+This file is part of GoSight.
+
+This is a stub for Windows to satisfy the interface used by the kmsg collector.
+*/
+
+package linuxcollector
+
+import (
+	"context"
+
+	"github.com/aaronlmathis/gosight-agent/internal/config"
+	"github.com/aaronlmathis/gosight-shared/model"
+)
+
+// KmsgCollector is a no-op stub for Windows.
+type KmsgCollector struct{}
+
+// NewKmsgCollector returns a disabled stub.
+func NewKmsgCollector(cfg *config.Config) *KmsgCollector {
+	return &KmsgCollector{}
+}
+
+// Name returns the collector name.
+func (c *KmsgCollector) Name() string {
+	return "kmsg"
+}
+
+// Collect returns no logs on Windows.
+func (c *KmsgCollector) Collect(ctx context.Context) ([][]model.LogEntry, error) {
+	return nil, nil
+}
+
+// Close is a no-op.
+func (c *KmsgCollector) Close() error { return nil }
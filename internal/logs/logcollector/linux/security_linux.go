@@ -37,6 +37,7 @@ import (
 	"time"
 
 	"github.com/aaronlmathis/gosight-agent/internal/config"
+	"github.com/aaronlmathis/gosight-agent/internal/logs/logcollector/repeat"
 	"github.com/aaronlmathis/gosight-shared/model"
 	"github.com/aaronlmathis/gosight-shared/utils"
 	"github.com/nxadm/tail" // Import the tail library
@@ -138,12 +139,20 @@ func (c *SecurityLogCollector) runTailing() {
 
 	utils.Debug("Tailing goroutine started for: %s", c.logPath)
 
+	// Collapses repeated lines when enabled for "security"
+	collapser := repeat.New(c.Config.Agent.LogCollection.Repeat, "security")
+	flush := time.NewTicker(time.Second)
+	defer flush.Stop()
+
 	for {
 		select {
 		case <-c.stop: // Check for stop signal first
 			utils.Debug("Stop signal received for tailing: %s", c.logPath)
 			return // Exit the loop and trigger deferred cleanup
 
+		case now := <-flush.C:
+			c.forward(collapser.Flush(now))
+
 		case line, ok := <-c.tailer.Lines:
 			if !ok {
 				// Channel closed, tailer likely stopped or encountered an error
@@ -166,16 +175,22 @@ func (c *SecurityLogCollector) runTailing() {
 				continue
 			}
 
-			// Try to send the parsed entry to the buffer channel.
-			// Use a non-blocking send with select to avoid blocking
-			// the tailer if the buffer is full (e.g., Collect not called often enough).
-			select {
-			case c.lines <- entry:
-				// Successfully sent
-			default:
-				// Buffer is full, drop the log and warn
-				utils.Warn("Log buffer full for %s. Dropping log entry: %s", c.logPath, entry.Message)
-			}
+			c.forward(collapser.Add(entry))
+		}
+	}
+}
+
+// forward sends parsed entries to the buffer channel.
+// Use a non-blocking send with select to avoid blocking
+// the tailer if the buffer is full (e.g., Collect not called often enough).
+func (c *SecurityLogCollector) forward(entries []model.LogEntry) {
+	for _, entry := range entries {
+		select {
+		case c.lines <- entry:
+			// Successfully sent
+		default:
+			// Buffer is full, drop the log and warn
+			utils.Warn("Log buffer full for %s. Dropping log entry: %s", c.logPath, entry.Message)
 		}
 	}
 }
@@ -0,0 +1,310 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// internal/logs/logcollector/listeners/listeners.go
+// Package listenercollector emits a log entry whenever a TCP or UDP listener
+// appears or disappears on the host, by diffing consecutive scans of the
+// listening sockets. It is a lightweight change-detection signal ("a new
+// port opened on this host at 3am").
+package listenercollector
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aaronlmathis/gosight-agent/internal/config"
+	"github.com/aaronlmathis/gosight-shared/model"
+	"github.com/aaronlmathis/gosight-shared/utils"
+	"github.com/shirou/gopsutil/v4/net"
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// defaultScanInterval is used when log_collection.listeners.scan_interval is unset.
+const defaultScanInterval = time.Minute
+
+// Listener is one listening socket.
+type Listener struct {
+	Protocol string // tcp, tcp6, udp, udp6
+	Address  string
+	Port     uint32
+	PID      int32
+	Process  string
+}
+
+// key identifies a listener across scans. The owning process is not part of
+// the key, so a restarted daemon re-binding its port is not reported.
+func (l Listener) key() string {
+	return l.Protocol + " " + l.Address + ":" + strconv.FormatUint(uint64(l.Port), 10)
+}
+
+// ListenerCollector scans the listening sockets every interval and buffers
+// an entry for every listener that opened or closed since the previous scan.
+type ListenerCollector struct {
+	interval  time.Duration
+	batchSize int
+
+	last    map[string]Listener
+	entries chan model.LogEntry
+	stop    chan struct{}
+	wg      sync.WaitGroup
+	once    sync.Once
+}
+
+// NewListenerCollector creates the collector and starts scanning. The first
+// scan only records the baseline; no entries are emitted for listeners that
+// already exist at startup.
+func NewListenerCollector(cfg *config.Config) *ListenerCollector {
+	lc := cfg.Agent.LogCollection
+
+	interval := lc.Listeners.ScanInterval
+	if interval <= 0 {
+		interval = defaultScanInterval
+	}
+	batchSize := lc.BatchSize
+	if batchSize <= 0 {
+		batchSize = 50
+	}
+
+	c := &ListenerCollector{
+		interval:  interval,
+		batchSize: batchSize,
+		entries:   make(chan model.LogEntry, batchSize*10),
+		stop:      make(chan struct{}),
+	}
+
+	if current, err := scan(context.Background()); err != nil {
+		utils.Warn("Initial listener scan failed: %v", err)
+	} else {
+		c.last = current
+	}
+
+	c.wg.Add(1)
+	go c.run()
+
+	return c
+}
+
+// Name returns the name of the collector.
+func (c *ListenerCollector) Name() string {
+	return "listeners"
+}
+
+// run rescans every interval until Close.
+func (c *ListenerCollector) run() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			current, err := scan(context.Background())
+			if err != nil {
+				utils.Warn("Listener scan failed: %v", err)
+				continue
+			}
+			if c.last != nil {
+				opened, closed := diff(c.last, current)
+				now := time.Now()
+				for _, l := range opened {
+					c.emit(newEntry(l, true, now))
+				}
+				for _, l := range closed {
+					c.emit(newEntry(l, false, now))
+				}
+			}
+			c.last = current
+		}
+	}
+}
+
+// emit buffers e, dropping it if the buffer is full.
+func (c *ListenerCollector) emit(e model.LogEntry) {
+	select {
+	case c.entries <- e:
+	default:
+		utils.Warn("Listener event buffer full, dropping: %s", e.Message)
+	}
+}
+
+// scan returns the current listening sockets keyed by Listener.key. TCP
+// sockets in LISTEN state and UDP sockets without a remote peer are listeners.
+func scan(ctx context.Context) (map[string]Listener, error) {
+	conns, err := net.ConnectionsWithContext(ctx, "inet")
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[int32]string)
+	out := make(map[string]Listener)
+	for _, cs := range conns {
+		proto := protocolName(cs.Type, cs.Family)
+		if proto == "" {
+			continue
+		}
+		if cs.Type == sockStream && cs.Status != "LISTEN" {
+			continue
+		}
+		if cs.Type == sockDgram && cs.Raddr.Port != 0 {
+			continue
+		}
+
+		l := Listener{Protocol: proto, Address: cs.Laddr.IP, Port: cs.Laddr.Port, PID: cs.Pid}
+		if cs.Pid > 0 {
+			name, ok := names[cs.Pid]
+			if !ok {
+				if p, err := process.NewProcessWithContext(ctx, cs.Pid); err == nil {
+					name, _ = p.NameWithContext(ctx)
+				}
+				names[cs.Pid] = name
+			}
+			l.Process = name
+		}
+		out[l.key()] = l
+	}
+	return out, nil
+}
+
+// Socket types and address families as reported by gopsutil.
+const (
+	sockStream = 1
+	sockDgram  = 2
+	afInet     = 2
+)
+
+func protocolName(sockType, family uint32) string {
+	var proto string
+	switch sockType {
+	case sockStream:
+		proto = "tcp"
+	case sockDgram:
+		proto = "udp"
+	default:
+		return ""
+	}
+	if family != afInet {
+		proto += "6"
+	}
+	return proto
+}
+
+// diff returns the listeners in current but not in prev (opened) and those
+// in prev but not in current (closed), each sorted by key.
+func diff(prev, current map[string]Listener) (opened, closed []Listener) {
+	for k, l := range current {
+		if _, ok := prev[k]; !ok {
+			opened = append(opened, l)
+		}
+	}
+	for k, l := range prev {
+		if _, ok := current[k]; !ok {
+			closed = append(closed, l)
+		}
+	}
+	sort.Slice(opened, func(i, j int) bool { return opened[i].key() < opened[j].key() })
+	sort.Slice(closed, func(i, j int) bool { return closed[i].key() < closed[j].key() })
+	return opened, closed
+}
+
+// newEntry builds the log entry for a listener change. New listeners are
+// logged at warning level since they are the interesting signal.
+func newEntry(l Listener, opened bool, ts time.Time) model.LogEntry {
+	event, level, verb := "listener_closed", "info", "closed"
+	if opened {
+		event, level, verb = "listener_opened", "warning", "opened"
+	}
+
+	owner := "unknown process"
+	if l.PID > 0 {
+		owner = fmt.Sprintf("pid %d", l.PID)
+		if l.Process != "" {
+			owner = fmt.Sprintf("pid %d %s", l.PID, l.Process)
+		}
+	}
+
+	labels := map[string]string{
+		"event":    event,
+		"protocol": l.Protocol,
+		"address":  l.Address,
+		"port":     strconv.FormatUint(uint64(l.Port), 10),
+	}
+	if l.PID > 0 {
+		labels["pid"] = strconv.Itoa(int(l.PID))
+	}
+	if l.Process != "" {
+		labels["process"] = l.Process
+	}
+
+	return model.LogEntry{
+		Timestamp: ts,
+		Level:     level,
+		Message:   fmt.Sprintf("%s listener %s on %s:%d (%s)", l.Protocol, verb, l.Address, l.Port, owner),
+		Source:    "listeners",
+		Category:  "security",
+		PID:       int(l.PID),
+		Labels:    labels,
+		Meta: &model.LogMeta{
+			Platform: "listeners",
+			AppName:  l.Process,
+		},
+	}
+}
+
+// Collect drains buffered entries into batches of at most BatchSize entries.
+func (c *ListenerCollector) Collect(_ context.Context) ([][]model.LogEntry, error) {
+	var batches [][]model.LogEntry
+	batch := make([]model.LogEntry, 0, c.batchSize)
+
+drain:
+	for {
+		select {
+		case entry := <-c.entries:
+			batch = append(batch, entry)
+			if len(batch) >= c.batchSize {
+				batches = append(batches, batch)
+				batch = make([]model.LogEntry, 0, c.batchSize)
+			}
+		default:
+			break drain
+		}
+	}
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+	return batches, nil
+}
+
+// Close stops scanning.
+func (c *ListenerCollector) Close() error {
+	c.once.Do(func() {
+		close(c.stop)
+		c.wg.Wait()
+		utils.Info("Listener log collector closed")
+	})
+	return nil
+}
@@ -0,0 +1,53 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+package listenercollector
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+	ssh := Listener{Protocol: "tcp", Address: "0.0.0.0", Port: 22, PID: 100, Process: "sshd"}
+	dns := Listener{Protocol: "udp", Address: "127.0.0.53", Port: 53, PID: 200, Process: "systemd-resolved"}
+	shell := Listener{Protocol: "tcp", Address: "0.0.0.0", Port: 4444, PID: 300, Process: "nc"}
+
+	prev := map[string]Listener{ssh.key(): ssh, dns.key(): dns}
+
+	// sshd restarted under a new PID: same listener, not reported
+	restarted := ssh
+	restarted.PID = 101
+	current := map[string]Listener{restarted.key(): restarted, shell.key(): shell}
+
+	opened, closed := diff(prev, current)
+	if len(opened) != 1 || opened[0].Port != 4444 {
+		t.Fatalf("opened = %+v, want only port 4444", opened)
+	}
+	if len(closed) != 1 || closed[0].Port != 53 {
+		t.Fatalf("closed = %+v, want only port 53", closed)
+	}
+
+	e := newEntry(opened[0], true, time.Unix(0, 0))
+	if e.Level != "warning" || e.Labels["event"] != "listener_opened" || e.Labels["process"] != "nc" || e.Labels["port"] != "4444" {
+		t.Fatalf("unexpected entry: %+v", e)
+	}
+}
@@ -27,9 +27,12 @@ import (
 	"io"
 	"runtime"
 	"strings"
+	"sync"
 
 	"github.com/aaronlmathis/gosight-agent/internal/config"
+	filecollector "github.com/aaronlmathis/gosight-agent/internal/logs/logcollector/file"
 	linuxcollector "github.com/aaronlmathis/gosight-agent/internal/logs/logcollector/linux"
+	listenercollector "github.com/aaronlmathis/gosight-agent/internal/logs/logcollector/listeners"
 	windowscollector "github.com/aaronlmathis/gosight-agent/internal/logs/logcollector/windows"
 
 	"github.com/aaronlmathis/gosight-shared/model"
@@ -41,38 +44,116 @@ import (
 // including their initialization, collection of logs, and closing them when no longer needed.
 type LogRegistry struct {
 	LogCollectors map[string]Collector
+
+	// mu guards LogCollectors, which Reload replaces
+	mu sync.Mutex
 }
 
 // NewRegistry initializes and registers enabled log collectors based on the configuration.
 // It creates a new LogRegistry instance and populates it with the specified collectors.
 func NewRegistry(cfg *config.Config) *LogRegistry {
-	reg := &LogRegistry{LogCollectors: make(map[string]Collector)}
+	reg := &LogRegistry{LogCollectors: buildCollectors(cfg, nil)}
+	utils.Info("Loaded %d log collectors", len(reg.LogCollectors))
 
-	for _, name := range cfg.Agent.LogCollection.Sources {
-		switch name {
-		case "journald":
-			if runtime.GOOS != "linux" {
-				utils.Warn("journald collector is only supported on Linux (skipping) \n")
-				continue
-			}
-			reg.LogCollectors["journald"] = linuxcollector.NewJournaldCollector(cfg)
-		case "security":
-			if runtime.GOOS != "linux" {
-				utils.Warn("journald collector is only supported on Linux (skipping) \n")
-				continue
-			}
-			reg.LogCollectors["security"] = linuxcollector.NewSecurityLogCollector(cfg)
-		case "eventviewer":
-			if runtime.GOOS == "windows" {
-				reg.LogCollectors["eventviewer"] = windowscollector.NewEventViewerCollector(cfg)
+	return reg
+}
+
+// Reload applies a new configuration to a running registry. Sources that are
+// no longer listed are closed (releasing journal handles, tailed files and
+// event subscriptions) and removed, newly listed ones are started, and
+// sources that stay listed keep running with their position. Settings a
+// running source read at startup take effect only after a restart.
+func (r *LogRegistry) Reload(cfg *config.Config) {
+	r.mu.Lock()
+	current := r.LogCollectors
+	r.mu.Unlock()
+
+	collectors := buildCollectors(cfg, current)
+
+	r.mu.Lock()
+	r.LogCollectors = collectors
+	r.mu.Unlock()
+
+	for name, collector := range current {
+		if _, ok := collectors[name]; !ok {
+			utils.Info("Log collector %s disabled", name)
+			if err := closeCollector(collector); err != nil {
+				utils.Error("Error closing collector %s: %v", name, err)
 			}
-		default:
-			utils.Warn("Unknown collector: %s (skipping) \n", name)
 		}
 	}
-	utils.Info("Loaded %d log collectors", len(reg.LogCollectors))
+	for name := range collectors {
+		if _, ok := current[name]; !ok {
+			utils.Info("Log collector %s enabled", name)
+		}
+	}
+	utils.Info("Reloaded log collectors: %d active", len(collectors))
+}
 
-	return reg
+// buildCollectors returns the collectors for cfg's sources, reusing those in
+// existing (which may be nil) instead of starting them again.
+func buildCollectors(cfg *config.Config, existing map[string]Collector) map[string]Collector {
+	collectors := make(map[string]Collector)
+
+	for _, name := range cfg.Agent.LogCollection.Sources {
+		if c, ok := existing[name]; ok {
+			collectors[name] = c
+			continue
+		}
+		if c := newCollector(cfg, name); c != nil {
+			collectors[name] = c
+		}
+	}
+	return collectors
+}
+
+// newCollector starts the named log source, or returns nil if it is unknown
+// or unsupported on this platform.
+func newCollector(cfg *config.Config, name string) Collector {
+	switch name {
+	case "journald":
+		if runtime.GOOS != "linux" {
+			utils.Warn("journald collector is only supported on Linux (skipping) \n")
+			return nil
+		}
+		return linuxcollector.NewJournaldCollector(cfg)
+	case "security":
+		if runtime.GOOS != "linux" {
+			utils.Warn("journald collector is only supported on Linux (skipping) \n")
+			return nil
+		}
+		return linuxcollector.NewSecurityLogCollector(cfg)
+	case "kmsg":
+		if runtime.GOOS != "linux" {
+			utils.Warn("kmsg collector is only supported on Linux (skipping) \n")
+			return nil
+		}
+		return linuxcollector.NewKmsgCollector(cfg)
+	case "file":
+		return filecollector.NewFileTailCollector(cfg)
+	case "listeners":
+		return listenercollector.NewListenerCollector(cfg)
+	case "eventviewer":
+		if runtime.GOOS == "windows" {
+			return windowscollector.NewEventViewerCollector(cfg)
+		}
+		return nil
+	default:
+		utils.Warn("Unknown collector: %s (skipping) \n", name)
+		return nil
+	}
+}
+
+// closeCollector closes c if it holds resources, whether its Close returns
+// an error (io.Closer) or not.
+func closeCollector(c Collector) error {
+	switch closer := c.(type) {
+	case io.Closer:
+		return closer.Close()
+	case interface{ Close() }:
+		closer.Close()
+	}
+	return nil
 }
 
 // Collect runs all active collectors and returns all collected metrics as a slice.
@@ -81,17 +162,39 @@ func NewRegistry(cfg *config.Config) *LogRegistry {
 func (r *LogRegistry) Collect(ctx context.Context) ([][]model.LogEntry, error) {
 	var allBatches [][]model.LogEntry
 
-	for name, collector := range r.LogCollectors {
+	bySource, err := r.CollectBySource(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, logBatches := range bySource {
+		allBatches = append(allBatches, logBatches...)
+	}
+
+	return allBatches, nil
+}
+
+// CollectBySource runs all active collectors and returns their batches keyed
+// by collector name (e.g. "security"), for callers that treat sources differently.
+func (r *LogRegistry) CollectBySource(ctx context.Context) (map[string][][]model.LogEntry, error) {
+	r.mu.Lock()
+	collectors := r.LogCollectors
+	r.mu.Unlock()
+
+	bySource := make(map[string][][]model.LogEntry, len(collectors))
+
+	for name, collector := range collectors {
 		logBatches, err := collector.Collect(ctx)
 		if err != nil {
 			utils.Error("Error collecting %s: %v\n", name, err)
 			continue
 		}
-		allBatches = append(allBatches, logBatches...)
+		if len(logBatches) > 0 {
+			bySource[name] = logBatches
+		}
 		//utils.Debug("LogRegistry returned %d batches", len(logBatches))
 	}
 
-	return allBatches, nil
+	return bySource, nil
 }
 
 // Close cleans up the resources used by the LogRegistry.
@@ -101,7 +204,11 @@ func (r *LogRegistry) Close() error {
 	utils.Info("Closing log registry and collectors...")
 	var errs []string // Collect errors non-blockingly
 
-	for name, collector := range r.LogCollectors {
+	r.mu.Lock()
+	collectors := r.LogCollectors
+	r.mu.Unlock()
+
+	for name, collector := range collectors {
 		// Check if the collector implements io.Closer
 		if closer, ok := collector.(io.Closer); ok {
 			utils.Debug("Closing collector: %s", name)
@@ -0,0 +1,149 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/logs/logcollector/repeat/repeat.go
+
+// Package repeat collapses runs of identical consecutive log lines, like
+// syslog's "last message repeated N times", so a service stuck in a tight
+// error loop cannot flood the pipeline.
+package repeat
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/aaronlmathis/gosight-agent/internal/config"
+	"github.com/aaronlmathis/gosight-shared/model"
+)
+
+const (
+	defaultWindow   = 5 * time.Second
+	defaultMaxCount = 1000
+
+	// CountField is the entry field holding the number of collapsed repeats.
+	CountField = "repeat_count"
+)
+
+// Collapser collapses consecutive identical lines from one stream. The first
+// line of a run is passed through immediately; the repeats that follow are
+// counted and emitted as a single copy of the line with a repeat_count field
+// when a different line arrives, the window since the first repeat expires,
+// or MaxCount repeats have been collapsed. A nil Collapser passes every line
+// through unchanged. It is not safe for concurrent use.
+type Collapser struct {
+	window   time.Duration
+	maxCount int
+
+	last        *model.LogEntry // most recent line passed through
+	repeats     int             // identical lines collapsed since then
+	lastRepeat  model.LogEntry  // most recent collapsed line
+	firstRepeat time.Time       // when the first collapsed line was seen
+}
+
+// New returns a Collapser for the named log source, or nil if cfg does not
+// enable repeat collapsing for it.
+func New(cfg config.RepeatCollapseConfig, source string) *Collapser {
+	enabled := false
+	for _, s := range cfg.Sources {
+		if s == source {
+			enabled = true
+			break
+		}
+	}
+	if !enabled {
+		return nil
+	}
+
+	c := &Collapser{window: cfg.Window, maxCount: cfg.MaxCount}
+	if c.window <= 0 {
+		c.window = defaultWindow
+	}
+	if c.maxCount <= 0 {
+		c.maxCount = defaultMaxCount
+	}
+	return c
+}
+
+// Add processes one line and returns the entries to emit, in order.
+func (c *Collapser) Add(e model.LogEntry) []model.LogEntry {
+	if c == nil {
+		return []model.LogEntry{e}
+	}
+
+	if c.last != nil && sameLine(*c.last, e) {
+		if c.repeats == 0 {
+			c.firstRepeat = time.Now()
+		}
+		c.repeats++
+		c.lastRepeat = e
+		if c.repeats >= c.maxCount {
+			return c.summary()
+		}
+		return nil
+	}
+
+	out := c.summary()
+	c.last = &e
+	return append(out, e)
+}
+
+// Flush returns the pending repeat summary once the window since the first
+// collapsed repeat has expired. Call it periodically so a run that simply
+// stops is still reported.
+func (c *Collapser) Flush(now time.Time) []model.LogEntry {
+	if c == nil || c.repeats == 0 || now.Sub(c.firstRepeat) < c.window {
+		return nil
+	}
+	return c.summary()
+}
+
+// summary returns the collapsed repeats as one entry and resets the count.
+func (c *Collapser) summary() []model.LogEntry {
+	if c.repeats == 0 {
+		return nil
+	}
+
+	e := c.lastRepeat
+	fields := make(map[string]string, len(e.Fields)+1)
+	for k, v := range e.Fields {
+		fields[k] = v
+	}
+	fields[CountField] = strconv.Itoa(c.repeats)
+	e.Fields = fields
+
+	c.repeats = 0
+	return []model.LogEntry{e}
+}
+
+// sameLine reports whether two entries are repeats of the same line.
+func sameLine(a, b model.LogEntry) bool {
+	if a.Message != b.Message || a.Level != b.Level || a.Source != b.Source {
+		return false
+	}
+	var unitA, unitB string
+	if a.Meta != nil {
+		unitA = a.Meta.Unit
+	}
+	if b.Meta != nil {
+		unitB = b.Meta.Unit
+	}
+	return unitA == unitB
+}
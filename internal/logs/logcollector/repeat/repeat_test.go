@@ -0,0 +1,79 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+package repeat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aaronlmathis/gosight-agent/internal/config"
+	"github.com/aaronlmathis/gosight-shared/model"
+)
+
+func TestCollapse(t *testing.T) {
+	c := New(config.RepeatCollapseConfig{Sources: []string{"file"}, MaxCount: 3}, "file")
+
+	loop := model.LogEntry{Message: "connection refused", Level: "error"}
+	var out []model.LogEntry
+	for i := 0; i < 5; i++ {
+		out = append(out, c.Add(loop)...)
+	}
+	out = append(out, c.Add(model.LogEntry{Message: "recovered"})...)
+
+	// first line, summary of 3 (max_count), summary of the remaining 1, next line
+	want := []string{"", "3", "1", ""}
+	if len(out) != len(want) {
+		t.Fatalf("expected %d entries, got %d: %+v", len(want), len(out), out)
+	}
+	for i, w := range want {
+		if got := out[i].Fields[CountField]; got != w {
+			t.Errorf("entry %d: repeat_count = %q, want %q", i, got, w)
+		}
+	}
+	if out[3].Message != "recovered" {
+		t.Errorf("distinct line not passed through, got %q", out[3].Message)
+	}
+}
+
+func TestFlushAfterWindow(t *testing.T) {
+	c := New(config.RepeatCollapseConfig{Sources: []string{"journald"}, Window: time.Second}, "journald")
+
+	line := model.LogEntry{Message: "tick"}
+	c.Add(line)
+	c.Add(line)
+
+	if out := c.Flush(time.Now()); len(out) != 0 {
+		t.Fatalf("flushed before window expired: %+v", out)
+	}
+	out := c.Flush(time.Now().Add(2 * time.Second))
+	if len(out) != 1 || out[0].Fields[CountField] != "1" {
+		t.Fatalf("expected one summary with repeat_count 1, got %+v", out)
+	}
+}
+
+func TestDisabledSource(t *testing.T) {
+	var c *Collapser = New(config.RepeatCollapseConfig{Sources: []string{"file"}}, "security")
+	line := model.LogEntry{Message: "same"}
+	if len(c.Add(line))+len(c.Add(line)) != 2 {
+		t.Error("disabled collapser should pass every line through")
+	}
+}
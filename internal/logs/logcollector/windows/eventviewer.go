@@ -47,6 +47,7 @@ import (
 	"unsafe"
 
 	"github.com/aaronlmathis/gosight-agent/internal/config"
+	"github.com/aaronlmathis/gosight-agent/internal/logs/logredact"
 	"github.com/aaronlmathis/gosight-shared/model"
 	"github.com/aaronlmathis/gosight-shared/utils"
 	"golang.org/x/sys/windows"
@@ -129,6 +130,7 @@ type EventViewerCollector struct {
 	once       sync.Once  // Add once for single execution of Close
 	batchSize  int
 	maxSize    int
+	includeRaw bool // keep the rendered XML in Meta.Extra
 }
 
 // getEventLogChannels returns a list of all available Windows Event Log channels
@@ -216,12 +218,17 @@ func matchesPattern(channel, pattern string) bool {
 func NewEventViewerCollector(cfg *config.Config) *EventViewerCollector {
 	utils.Debug("Initializing EventViewer collector...")
 
+	evCfg := cfg.Agent.LogCollection.EventViewer
 	channels, err := getEventLogChannels()
 	if err != nil {
-		utils.Error("Failed to enumerate event log channels: %v", err)
-		return nil
+		// Querying a known channel can still work, so fall back to the
+		// configured names rather than disabling the collector
+		utils.Warn("Failed to enumerate event log channels: %v; trying the configured channels", err)
+		channels = evCfg.Channels
+	} else {
+		utils.Debug("Found %d total available Windows Event Log channels", len(channels))
+		warnMissingChannels(channels, evCfg.Channels)
 	}
-	utils.Debug("Found %d total available Windows Event Log channels", len(channels))
 
 	c := &EventViewerCollector{
 		collectors: make(map[string]*channelCollector),
@@ -229,6 +236,7 @@ func NewEventViewerCollector(cfg *config.Config) *EventViewerCollector {
 		stop:       make(chan struct{}),
 		batchSize:  cfg.Agent.LogCollection.BatchSize,
 		maxSize:    cfg.Agent.LogCollection.MessageMax,
+		includeRaw: cfg.Agent.LogCollection.EventViewer.IncludeRawXML,
 	}
 
 	selectedCount := 0
@@ -264,7 +272,9 @@ func NewEventViewerCollector(cfg *config.Config) *EventViewerCollector {
 			uintptr(EvtQueryChannelPath|EvtQueryForwardDirection),
 		)
 		if h == 0 {
-			utils.Error("EvtQuery failed for channel %s: %v", channel, callErr)
+			// e.g. Security without administrator or Event Log Readers
+			// rights; the other channels are still collected
+			utils.Warn("EvtQuery failed for channel %s, skipping it: %v", channel, callErr)
 			continue
 		}
 
@@ -282,11 +292,25 @@ func NewEventViewerCollector(cfg *config.Config) *EventViewerCollector {
 		utils.Info("Started collecting from Windows Event Log channel: %s (events from %s onwards)", channel, startTime)
 	}
 
-	utils.Info("EventViewer collector initialized with %d channels (selected) and %d channels (skipped). CollectAll=%v",
-		selectedCount, skippedCount, cfg.Agent.LogCollection.EventViewer.CollectAll)
+	utils.Info("EventViewer collector initialized with %d of %d selected channels (%d channels skipped). CollectAll=%v",
+		len(c.collectors), selectedCount, skippedCount, evCfg.CollectAll)
 	return c
 }
 
+// warnMissingChannels logs the configured channels that don't exist on this
+// host, which would otherwise be skipped silently.
+func warnMissingChannels(available, configured []string) {
+	present := make(map[string]bool, len(available))
+	for _, ch := range available {
+		present[strings.ToLower(ch)] = true
+	}
+	for _, ch := range configured {
+		if !present[strings.ToLower(ch)] {
+			utils.Warn("Event log channel %q not found on this host; skipping", ch)
+		}
+	}
+}
+
 // Name returns the name of the collector
 func (e *EventViewerCollector) Name() string {
 	return "eventviewer"
@@ -371,7 +395,7 @@ func (e *EventViewerCollector) runReader(collector *channelCollector) {
 			}
 
 			xml := syscall.UTF16ToString(buffer[:used/2])
-			entry := buildLogEntry(xml, e.maxSize, collector.channelName)
+			entry := buildLogEntry(xml, e.maxSize, collector.channelName, e.includeRaw)
 
 			utils.Debug("Channel %s: Processing event ID %s with level %s", collector.channelName, entry.Meta.EventID, entry.Level)
 
@@ -445,13 +469,9 @@ func (e *EventViewerCollector) Close() error {
 	e.once.Do(func() {
 		utils.Debug("Closing EventViewer collector...")
 
-		// Signal all readers to stop by closing stop channel
-		e.mu.Lock()
-		if e.stop != nil {
-			close(e.stop)
-			e.stop = nil
-		}
-		e.mu.Unlock()
+		// Signal all readers to stop by closing stop channel. It stays set:
+		// readers that haven't seen it closed yet must still find it so.
+		close(e.stop)
 
 		// Wait for goroutines with a timeout
 		done := make(chan struct{})
@@ -488,7 +508,9 @@ func (e *EventViewerCollector) Close() error {
 // mapEventLevel maps Windows Event Log levels to standardized GoSight levels
 func mapEventLevel(level string) string {
 	switch strings.ToLower(level) {
-	case "critical", "error":
+	case "critical":
+		return "critical"
+	case "error":
 		return "error"
 	case "warning":
 		return "warning"
@@ -501,6 +523,29 @@ func mapEventLevel(level string) string {
 	}
 }
 
+// eventLevelName returns the name of a System/Level value. The standard
+// levels 1-5 map to fixed names so they don't vary with the system language;
+// the rendered name is used only for level 0 (LogAlways) and provider-defined
+// levels above 5, which otherwise are reported as information.
+func eventLevelName(level int, rendered string) string {
+	switch level {
+	case 1:
+		return "critical"
+	case 2:
+		return "error"
+	case 3:
+		return "warning"
+	case 4:
+		return "information"
+	case 5:
+		return "verbose"
+	}
+	if rendered != "" {
+		return rendered
+	}
+	return "information"
+}
+
 // eventCategoryMap defines mappings from Windows Event Log channels/providers to GoSight categories
 var eventCategoryMap = []struct {
 	MatchKey string // lowercased channel or provider name
@@ -621,7 +666,7 @@ func parseEventXML(xmlStr string) (level, provider, channel string, fields map[s
 	}
 
 	// Map the level
-	level = strconv.Itoa(evt.System.Level)
+	level = eventLevelName(evt.System.Level, evt.RenderingInfo.Level)
 	provider = evt.System.Provider.Name
 	channel = evt.System.Channel
 
@@ -650,10 +695,16 @@ func parseEventXML(xmlStr string) (level, provider, channel string, fields map[s
 		fields["process_id"] = strconv.Itoa(evt.System.Execution.ProcessID)
 	}
 
-	// Extract event data fields
-	for _, data := range evt.EventData.Data {
-		if data.Name != "" && data.Value != "" {
+	// Extract event data fields; unnamed values (classic providers) are
+	// numbered in order
+	for i, data := range evt.EventData.Data {
+		if data.Value == "" {
+			continue
+		}
+		if data.Name != "" {
 			fields[strings.ToLower(data.Name)] = data.Value
+		} else {
+			fields[fmt.Sprintf("data_%d", i)] = data.Value
 		}
 	}
 
@@ -663,10 +714,16 @@ func parseEventXML(xmlStr string) (level, provider, channel string, fields map[s
 	// Add provider and event ID
 	parts = append(parts, fmt.Sprintf("[%s] Event ID %d", provider, evt.System.EventID))
 
-	// Add event data in a readable format
-	for _, data := range evt.EventData.Data {
-		if data.Name != "" && data.Value != "" {
-			parts = append(parts, fmt.Sprintf("%s: %s", data.Name, data.Value))
+	// Use the rendered message if the event carries one, else the event data
+	if evt.RenderingInfo.Message != "" {
+		parts = append(parts, evt.RenderingInfo.Message)
+	} else {
+		for _, data := range evt.EventData.Data {
+			if data.Name != "" && data.Value != "" {
+				parts = append(parts, fmt.Sprintf("%s: %s", data.Name, data.Value))
+			} else if data.Value != "" {
+				parts = append(parts, data.Value)
+			}
 		}
 	}
 
@@ -685,8 +742,11 @@ func parseEventXML(xmlStr string) (level, provider, channel string, fields map[s
 	return
 }
 
-// buildLogEntry builds a LogEntry from an XML string
-func buildLogEntry(xml string, maxSize int, channelName string) model.LogEntry {
+// buildLogEntry builds a LogEntry from an XML string. The level, provider
+// (as Source), process ID and event ID come from the System section and the
+// EventData values become fields. includeRaw keeps the XML itself in
+// Meta.Extra["raw_xml"].
+func buildLogEntry(xml string, maxSize int, channelName string, includeRaw bool) model.LogEntry {
 	// Parse the event XML to extract key information
 	level, provider, channel, fields, timestamp, message := parseEventXML(xml)
 
@@ -705,13 +765,13 @@ func buildLogEntry(xml string, maxSize int, channelName string) model.LogEntry {
 	// Determine the appropriate category
 	category := classifyEventCategory(channel, provider)
 
-	// Create tags for quick filtering
-	tags := make(map[string]string)
+	// Create labels for quick filtering
+	labels := make(map[string]string)
 	if eventID, ok := fields["event_id"]; ok {
-		tags["event_id"] = eventID
+		labels["event_id"] = eventID
 	}
 	if computer, ok := fields["computer"]; ok {
-		tags["computer"] = computer
+		labels["computer"] = computer
 	}
 
 	// Add Windows-specific metadata
@@ -748,16 +808,26 @@ func buildLogEntry(xml string, maxSize int, channelName string) model.LogEntry {
 			meta.Extra[k] = v
 		}
 	}
+	if includeRaw {
+		meta.Extra[logredact.RawXMLKey] = xml
+	}
+
+	// The provider is the emitting application; unparsable events keep the
+	// collector name
+	source := provider
+	if source == "" {
+		source = "eventviewer"
+	}
 
 	return model.LogEntry{
 		Timestamp: timestamp,
 		Level:     mapEventLevel(level),
 		Message:   message,
-		Source:    "eventviewer",
+		Source:    source,
 		Category:  category,
 		PID:       pid,
 		Fields:    fields,
-		Tags:      tags,
+		Labels:    labels,
 		Meta:      meta,
 	}
 }
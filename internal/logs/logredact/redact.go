@@ -0,0 +1,165 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/logs/logredact/redact.go
+
+// Package logredact masks secrets and personal data in log entries before
+// they leave the log runner, using built-in and configured regex patterns.
+package logredact
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/aaronlmathis/gosight-agent/internal/config"
+	"github.com/aaronlmathis/gosight-shared/model"
+)
+
+const defaultReplacement = "[REDACTED]"
+
+// RawXMLKey is the Meta.Extra key holding an eventviewer entry's raw event XML.
+const RawXMLKey = "raw_xml"
+
+// rule is a compiled redaction pattern.
+type rule struct {
+	name        string
+	re          *regexp.Regexp
+	replacement string
+	valid       func(match string) bool // nil = every match is redacted
+}
+
+// builtinRules are applied, in order, when redact.builtin is set. URL
+// passwords come before emails so "user:pw@host" is seen as credentials.
+var builtinRules = []rule{
+	{name: "bearer_token", re: regexp.MustCompile(`(?i)\b(bearer\s+)[A-Za-z0-9\-._~+/]+=*`), replacement: "${1}" + defaultReplacement},
+	{name: "url_password", re: regexp.MustCompile(`(://[^/\s:@]+:)[^/\s@]+@`), replacement: "${1}" + defaultReplacement + "@"},
+	{name: "password_assignment", re: regexp.MustCompile(`(?i)\b((?:password|passwd|pwd|secret|token|api[_-]?key|access[_-]?key)"?\s*[=:]\s*)("[^"]*"|'[^']*'|[^\s,;&"']+)`), replacement: "${1}" + defaultReplacement},
+	{name: "aws_access_key", re: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`), replacement: defaultReplacement},
+	{name: "email", re: regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}\b`), replacement: defaultReplacement},
+	{name: "credit_card", re: regexp.MustCompile(`\b[3-6](?:[ -]?\d){12,18}\b`), replacement: defaultReplacement, valid: luhn},
+}
+
+// Redactor applies redaction rules to log entries. A nil Redactor leaves
+// entries untouched.
+type Redactor struct {
+	rules     []rule
+	fields    map[string]bool
+	allFields bool
+}
+
+// New compiles the configured patterns. It returns nil, nil when there is
+// nothing to redact.
+func New(cfg config.RedactConfig) (*Redactor, error) {
+	r := &Redactor{fields: make(map[string]bool, len(cfg.Fields))}
+	if cfg.Builtin {
+		r.rules = append(r.rules, builtinRules...)
+	}
+	for _, p := range cfg.Patterns {
+		re, err := regexp.Compile(p.Regex)
+		if err != nil {
+			return nil, fmt.Errorf("redact pattern %q: %w", p.Name, err)
+		}
+		replacement := p.Replacement
+		if replacement == "" {
+			replacement = defaultReplacement
+		}
+		r.rules = append(r.rules, rule{name: p.Name, re: re, replacement: replacement})
+	}
+	if len(r.rules) == 0 {
+		return nil, nil
+	}
+	for _, f := range cfg.Fields {
+		if f == "*" {
+			r.allFields = true
+		}
+		r.fields[f] = true
+	}
+	return r, nil
+}
+
+// Batch redacts every entry of a batch in place.
+func (r *Redactor) Batch(entries []model.LogEntry) {
+	if r == nil {
+		return
+	}
+	for i := range entries {
+		r.Entry(&entries[i])
+	}
+}
+
+// Entry redacts the message, the selected fields and the raw event XML of
+// an entry in place.
+func (r *Redactor) Entry(e *model.LogEntry) {
+	if r == nil {
+		return
+	}
+	e.Message = r.redact(e.Message)
+	for k, v := range e.Fields {
+		if r.allFields || r.fields[k] {
+			e.Fields[k] = r.redact(v)
+		}
+	}
+	if e.Meta != nil {
+		if raw, ok := e.Meta.Extra[RawXMLKey]; ok {
+			e.Meta.Extra[RawXMLKey] = r.redact(raw)
+		}
+	}
+}
+
+func (r *Redactor) redact(s string) string {
+	if s == "" {
+		return s
+	}
+	for _, rl := range r.rules {
+		if rl.valid == nil {
+			s = rl.re.ReplaceAllString(s, rl.replacement)
+			continue
+		}
+		s = rl.re.ReplaceAllStringFunc(s, func(m string) string {
+			if rl.valid(m) {
+				return rl.replacement
+			}
+			return m
+		})
+	}
+	return s
+}
+
+// luhn reports whether the digits in s pass the Luhn checksum used by card
+// numbers, so arbitrary long numbers (IDs, timestamps) are left alone.
+func luhn(s string) bool {
+	sum, n := 0, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n > 0 && sum%10 == 0
+}
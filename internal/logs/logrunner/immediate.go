@@ -0,0 +1,86 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// agent/internal/logs/logrunner/immediate.go
+// Ships high-severity log entries as soon as they are collected instead of
+// waiting for the next log interval.
+
+package logrunner
+
+import (
+	"strings"
+	"time"
+
+	"github.com/aaronlmathis/gosight-shared/model"
+)
+
+// defaultImmediateCheckInterval is used when log_collection.immediate_check_interval is unset.
+const defaultImmediateCheckInterval = time.Second
+
+// severityRank orders log levels; levels it does not know rank below debug
+// (-1) so they never qualify for immediate shipping.
+func severityRank(level string) int {
+	switch strings.ToLower(level) {
+	case "trace":
+		return 0
+	case "debug":
+		return 1
+	case "info", "notice":
+		return 2
+	case "warn", "warning":
+		return 3
+	case "error", "err":
+		return 4
+	case "fatal", "critical", "crit", "alert", "emerg", "panic":
+		return 5
+	default:
+		return -1
+	}
+}
+
+// splitUrgent separates entries at or above minRank from the rest, keeping
+// their order.
+func splitUrgent(batches [][]model.LogEntry, minRank int) (urgent, rest []model.LogEntry) {
+	for _, batch := range batches {
+		for _, e := range batch {
+			if severityRank(e.Level) >= minRank {
+				urgent = append(urgent, e)
+			} else {
+				rest = append(rest, e)
+			}
+		}
+	}
+	return urgent, rest
+}
+
+// rebatch splits entries into batches of at most size entries.
+func rebatch(entries []model.LogEntry, size int) [][]model.LogEntry {
+	if size <= 0 {
+		size = 50
+	}
+	var batches [][]model.LogEntry
+	for len(entries) > 0 {
+		n := min(size, len(entries))
+		batches = append(batches, entries[:n:n])
+		entries = entries[n:]
+	}
+	return batches
+}
@@ -0,0 +1,47 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+package logrunner
+
+import (
+	"testing"
+
+	"github.com/aaronlmathis/gosight-shared/model"
+)
+
+func TestSplitUrgent(t *testing.T) {
+	batches := [][]model.LogEntry{
+		{{Level: "info", Message: "a"}, {Level: "error", Message: "b"}},
+		{{Level: "WARNING", Message: "c"}, {Level: "critical", Message: "d"}, {Level: "unknown", Message: "e"}},
+	}
+
+	urgent, rest := splitUrgent(batches, severityRank("error"))
+	if len(urgent) != 2 || urgent[0].Message != "b" || urgent[1].Message != "d" {
+		t.Errorf("urgent = %+v", urgent)
+	}
+	if len(rest) != 3 || rest[0].Message != "a" || rest[1].Message != "c" || rest[2].Message != "e" {
+		t.Errorf("rest = %+v", rest)
+	}
+
+	if got := rebatch(rest, 2); len(got) != 2 || len(got[0]) != 2 || len(got[1]) != 1 {
+		t.Errorf("rebatch = %+v", got)
+	}
+}
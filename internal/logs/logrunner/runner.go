@@ -27,9 +27,13 @@ import (
 	"time"
 
 	"github.com/aaronlmathis/gosight-agent/internal/config"
+	"github.com/aaronlmathis/gosight-agent/internal/logs/logaudit"
 	"github.com/aaronlmathis/gosight-agent/internal/logs/logcollector"
+	"github.com/aaronlmathis/gosight-agent/internal/logs/logredact"
 	"github.com/aaronlmathis/gosight-agent/internal/logs/logsender"
 	"github.com/aaronlmathis/gosight-agent/internal/meta"
+	"github.com/aaronlmathis/gosight-agent/internal/selfmetrics"
+	agentutils "github.com/aaronlmathis/gosight-agent/internal/utils"
 	"github.com/aaronlmathis/gosight-shared/model"
 	"github.com/aaronlmathis/gosight-shared/utils"
 )
@@ -44,6 +48,31 @@ type LogRunner struct {
 	LogRegistry *logcollector.LogRegistry
 	Meta        *model.Meta
 	runWg       sync.WaitGroup
+
+	// Audit keeps a local copy of the configured sources (nil if disabled)
+	Audit *logaudit.Sink
+
+	// Redactor masks secrets in collected entries (nil if no patterns)
+	Redactor *logredact.Redactor
+
+	// immediateRank is the lowest severity shipped without waiting for the
+	// log interval (-1 = disabled); pending holds lower-severity entries
+	// drained early by the immediate check until the next interval
+	immediateRank int
+	pending       []model.LogEntry
+
+	// emitted holds entries generated by the agent itself (see Emit)
+	emitMu  sync.Mutex
+	emitted []model.LogEntry
+}
+
+// Emit queues an entry generated by the agent itself (e.g. a configuration
+// change event) to be shipped with the next collection cycle. It is safe for
+// concurrent use.
+func (r *LogRunner) Emit(entry model.LogEntry) {
+	r.emitMu.Lock()
+	defer r.emitMu.Unlock()
+	r.emitted = append(r.emitted, entry)
 }
 
 // NewRunner creates a new LogRunner instance.
@@ -51,23 +80,57 @@ type LogRunner struct {
 // It returns a pointer to the LogRunner and an error if any occurs during initialization.
 func NewRunner(ctx context.Context, cfg *config.Config, baseMeta *model.Meta) (*LogRunner, error) {
 
+	redactor, err := logredact.New(cfg.Agent.LogCollection.Redact)
+	if err != nil {
+		return nil, fmt.Errorf("invalid log redaction config: %w", err)
+	}
+
 	logRegistry := logcollector.NewRegistry(cfg)
 
+	audit, err := logaudit.Open(cfg.Agent.LogCollection.Audit)
+	if err != nil {
+		logRegistry.Close()
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+
 	logSender, err := logsender.NewSender(ctx, cfg)
 	if err != nil {
 		// Clean up registry if sender fails?
 		logRegistry.Close() // Add a Close method to LogRegistry
+		audit.Close()
 		return nil, fmt.Errorf("failed to create sender: %v", err)
 	}
 
+	immediateRank := -1
+	if lvl := cfg.Agent.LogCollection.ImmediateLevel; lvl != "" {
+		if immediateRank = severityRank(lvl); immediateRank < 0 {
+			utils.Warn("Unknown log_collection.immediate_level %q; immediate shipping disabled", lvl)
+		}
+	}
+
 	return &LogRunner{
-		Config:      cfg,
-		LogSender:   logSender,
-		LogRegistry: logRegistry,
-		Meta:        baseMeta,
+		Config:        cfg,
+		LogSender:     logSender,
+		LogRegistry:   logRegistry,
+		Meta:          baseMeta,
+		Audit:         audit,
+		Redactor:      redactor,
+		immediateRank: immediateRank,
 	}, nil
 }
 
+// ReloadRedactor rebuilds the redactor from the log_collection.redact
+// settings of cfg. On error the running redactor is kept. It must be called
+// under config.Update, as cycles read the redactor under the read lock.
+func (r *LogRunner) ReloadRedactor(cfg *config.Config) error {
+	redactor, err := logredact.New(cfg.Agent.LogCollection.Redact)
+	if err != nil {
+		return fmt.Errorf("invalid log redaction config: %w", err)
+	}
+	r.Redactor = redactor
+	return nil
+}
+
 // Close cleans up the resources used by the LogRunner.
 // It closes the log sender and the log registry.
 // It should be called when the LogRunner is no longer needed.
@@ -86,6 +149,10 @@ func (r *LogRunner) Close() {
 		}
 	}
 
+	if err := r.Audit.Close(); err != nil {
+		utils.Error("Error closing audit log: %v", err)
+	}
+
 	// Wait for sender pool goroutines to finish (if Close doesn't block)
 	// Or manage worker shutdown signalling more explicitly if needed.
 	// The LogSender's Close method should ideally handle this wait.
@@ -98,18 +165,22 @@ func (r *LogRunner) Run(ctx context.Context) {
 	defer r.Close() // Ensure cleanup on exit
 
 	utils.Debug("Initializing LogRunner...")
+	config.RLock()
 	taskQueue := make(chan *model.LogPayload, r.Config.Agent.LogCollection.BufferSize)
+	selfmetrics.TrackQueue("logs", taskQueue)
 
-	// Start sender worker pool
-	// Make sure StartWorkerPool handles context cancellation gracefully
+	// Start sender worker pool. It outlives ctx so queued batches can still
+	// be flushed on shutdown (see drain)
+	workerCtx, stopWorkers := agentutils.WorkerContext(ctx)
 	r.runWg.Add(1)
 	go func() {
 		defer r.runWg.Done()
-		r.LogSender.StartWorkerPool(ctx, taskQueue, r.Config.Agent.LogCollection.Workers)
+		r.LogSender.StartWorkerPool(workerCtx, taskQueue, r.Config.Agent.LogCollection.Workers)
 		utils.Debug("Log sender worker pool stopped.")
 	}()
 
-	ticker := time.NewTicker(r.Config.Agent.LogCollection.Interval)
+	interval := r.Config.Agent.LogCollection.Interval
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
 	utils.Info("Log Runner started. Collecting logs every %v", r.Config.Agent.LogCollection.Interval)
@@ -117,74 +188,200 @@ func (r *LogRunner) Run(ctx context.Context) {
 	// No need for the startTime throttling anymore unless specifically desired
 	// startTime := time.Now()
 
+	cycles := selfmetrics.NewCycleTracker("logs", r.Config.Agent.LogCollection.Interval, r.Config.Agent.CycleDeadline)
+	cycles.EnableMarkers(r.Config.Agent.CycleMarkers)
+
+	// High-severity entries are looked for every immediate_check_interval
+	var immediate <-chan time.Time
+	if r.immediateRank >= 0 {
+		every := r.Config.Agent.LogCollection.ImmediateCheckInterval
+		if every <= 0 {
+			every = defaultImmediateCheckInterval
+		}
+		t := time.NewTicker(every)
+		defer t.Stop()
+		immediate = t.C
+		utils.Info("Shipping %s and higher log entries immediately (checked every %v)", r.Config.Agent.LogCollection.ImmediateLevel, every)
+	}
+	config.RUnlock()
+
 	for {
 		select {
 		case <-ctx.Done():
 			utils.Warn("Log runner context cancelled, shutting down...")
+			r.drain(ctx, taskQueue, stopWorkers)
 			return // Exit Run, defer Close() will be called
 		case <-ticker.C:
-			// Collect logs from *all* registered collectors via the registry
-			logBatches, err := r.LogRegistry.Collect(ctx) // Assuming Collect iterates through collectors
-			if err != nil {
-				// Log collection errors, but continue running
-				utils.Error("Log collection failed: %v", err)
-				continue
+			config.RLock()
+			// A config reload may have changed the interval
+			if iv := r.Config.Agent.LogCollection.Interval; iv > 0 && iv != interval {
+				utils.Info("Log collection interval changed from %v to %v", interval, iv)
+				interval = iv
+				ticker.Reset(iv)
 			}
-
-			// If no logs collected, continue to next tick
-			if len(logBatches) == 0 {
-				continue
+			start := cycles.Begin()
+			counts, ok := r.collectCycle(ctx, taskQueue)
+			config.RUnlock()
+			cycles.End(start, counts)
+			if !ok {
+				return
 			}
+		case <-immediate:
+			config.RLock()
+			ok := r.immediateCycle(ctx, taskQueue)
+			config.RUnlock()
+			if !ok {
+				return
+			}
+		}
+	}
+}
 
-			// set job tag for victoriametrics.
-			r.Meta.Tags["job"] = "gosight-logs"
-
-			// clone base meta before modifying it
-			meta := meta.CloneMetaWithTags(r.Meta, nil)
+// drain runs a last collection cycle on shutdown, so lines already buffered
+// by the journald/eventviewer readers and held-back entries are not lost, then
+// gives the worker pool up to shutdown_drain_timeout to flush the queue.
+func (r *LogRunner) drain(ctx context.Context, taskQueue chan *model.LogPayload, stopWorkers context.CancelFunc) {
+	config.RLock()
+	timeout := r.Config.Agent.ShutdownDrainTimeout
+	if timeout > 0 {
+		finalCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+		r.collectCycle(finalCtx, taskQueue)
+		cancel()
+	}
+	config.RUnlock()
+	agentutils.DrainQueue("logs", taskQueue, timeout, stopWorkers)
+}
 
-			// Generate Endpoint ID
-			endpointID := utils.GenerateEndpointID(meta)
-			meta.EndpointID = endpointID
-			meta.Kind = "host"
-			meta.Tags["instance"] = meta.Hostname
+// immediateCycle drains all sources, ships entries at or above the immediate
+// level right away in their own batches and keeps the rest for the next
+// regular cycle. It returns false if the context was cancelled while queuing.
+func (r *LogRunner) immediateCycle(ctx context.Context, taskQueue chan<- *model.LogPayload) bool {
+	urgent, rest := splitUrgent(r.collect(ctx), r.immediateRank)
+	r.pending = append(r.pending, rest...)
+	if len(urgent) == 0 {
+		return true
+	}
+	_, _, ok := r.ship(ctx, taskQueue, rebatch(urgent, r.Config.Agent.LogCollection.BatchSize))
+	return ok
+}
 
-			//utils.Debug("Processing %d log batches for sending.", len(logBatches))
+// collectCycle collects logs from all sources once and queues them for
+// sending, together with entries held back by immediateCycle. It returns the
+// cycle's counts for the cycle markers, and false if the context was
+// cancelled while queuing.
+func (r *LogRunner) collectCycle(ctx context.Context, taskQueue chan<- *model.LogPayload) (selfmetrics.CycleCounts, bool) {
+	logBatches := r.collect(ctx)
+	if len(r.pending) > 0 {
+		logBatches = append(rebatch(r.pending, r.Config.Agent.LogCollection.BatchSize), logBatches...)
+		r.pending = nil
+	}
 
-			// Loop through batches collected (potentially from multiple sources)
-			for _, batch := range logBatches {
-				if len(batch) == 0 {
-					continue // Skip empty batches
-				}
+	var counts selfmetrics.CycleCounts
+	for _, batch := range logBatches {
+		counts.Collected += len(batch)
+	}
+	queued, dropped, ok := r.ship(ctx, taskQueue, logBatches)
+	counts.Queued, counts.Dropped = queued, dropped
+	return counts, ok
+}
 
-				// Attach metadata (LogRunner is responsible for the payload structure)
-				payload := &model.LogPayload{
-					AgentID:    meta.AgentID,
-					HostID:     meta.HostID,
-					Hostname:   meta.Hostname,
-					EndpointID: meta.EndpointID,
-					Timestamp:  time.Now(), // Payload timestamp is collection time
-					Logs:       batch,      // The batch collected from a specific source
-					Meta:       meta,       // Agent/Host metadata
-				}
+// collect drains every source once, redacts the entries, writes audited
+// sources to the local audit log, and returns the collected batches.
+func (r *LogRunner) collect(ctx context.Context) [][]model.LogEntry {
+	// Collect logs from *all* registered collectors via the registry
+	bySource, err := r.LogRegistry.CollectBySource(ctx)
+	if err != nil {
+		// Log collection errors, but continue running
+		utils.Error("Log collection failed: %v", err)
+		return nil
+	}
 
-				// No need for the artificial sleep throttling unless rate limiting is required
-				// if time.Since(startTime) < 30*time.Second {
-				//     time.Sleep(100 * time.Millisecond)
-				// }
-				//utils.Debug("Queuing log payload with %d entries from host %s", len(batch), meta.Hostname)
-
-				// Send payload to the worker pool queue
-				select {
-				case taskQueue <- payload:
-					// Successfully queued
-				case <-ctx.Done():
-					utils.Warn("Context cancelled while trying to queue log payload. Shutting down.")
-					return // Exit if context cancelled during queuing attempt
-				default:
-					// Queue is full, drop the batch
-					utils.Warn("Log task queue full! Dropping log batch (%d entries) from host %s", len(batch), meta.Hostname)
+	// Audited sources are persisted locally before (and regardless of) shipping
+	var logBatches [][]model.LogEntry
+	for source, batches := range bySource {
+		for _, batch := range batches {
+			r.Redactor.Batch(batch)
+			if r.Audit.Audits(source) {
+				if err := r.Audit.Write(source, batch); err != nil {
+					utils.Error("Failed to write %d %s entries to audit log: %v", len(batch), source, err)
 				}
 			}
+			logBatches = append(logBatches, batch)
+		}
+	}
+
+	r.emitMu.Lock()
+	if len(r.emitted) > 0 {
+		r.Redactor.Batch(r.emitted)
+		logBatches = append(logBatches, r.emitted)
+		r.emitted = nil
+	}
+	r.emitMu.Unlock()
+
+	return logBatches
+}
+
+// ship wraps each batch in a payload with the host metadata and queues it.
+// It returns how many entries were queued and dropped (queue full), and
+// false if the context was cancelled while queuing.
+func (r *LogRunner) ship(ctx context.Context, taskQueue chan<- *model.LogPayload, logBatches [][]model.LogEntry) (queued, dropped int, ok bool) {
+	// If no logs collected, continue to next tick
+	if len(logBatches) == 0 {
+		return 0, 0, true
+	}
+
+	// Generate Endpoint ID (before the package name is shadowed below)
+	endpointID := meta.GenerateEndpointID(r.Meta)
+
+	// clone base meta before modifying it; the base is shared with the
+	// other runners
+	meta := meta.CloneMetaWithTags(r.Meta, nil)
+	meta.EndpointID = endpointID
+
+	// set job tag for victoriametrics.
+	meta.Tags["job"] = "gosight-logs"
+	meta.Kind = "host"
+	meta.Tags["instance"] = meta.Hostname
+
+	//utils.Debug("Processing %d log batches for sending.", len(logBatches))
+
+	// Loop through batches collected (potentially from multiple sources)
+	for _, batch := range logBatches {
+		if len(batch) == 0 {
+			continue // Skip empty batches
+		}
+
+		// Attach metadata (LogRunner is responsible for the payload structure)
+		payload := &model.LogPayload{
+			AgentID:    meta.AgentID,
+			HostID:     meta.HostID,
+			Hostname:   meta.Hostname,
+			EndpointID: meta.EndpointID,
+			Timestamp:  time.Now(), // Payload timestamp is collection time
+			Logs:       batch,      // The batch collected from a specific source
+			Meta:       meta,       // Agent/Host metadata
+		}
+
+		// No need for the artificial sleep throttling unless rate limiting is required
+		// if time.Since(startTime) < 30*time.Second {
+		//     time.Sleep(100 * time.Millisecond)
+		// }
+		//utils.Debug("Queuing log payload with %d entries from host %s", len(batch), meta.Hostname)
+
+		// Send payload to the worker pool queue
+		select {
+		case taskQueue <- payload:
+			// Successfully queued
+			queued += len(batch)
+		case <-ctx.Done():
+			utils.Warn("Context cancelled while trying to queue log payload. Shutting down.")
+			return queued, dropped, false // Exit if context cancelled during queuing attempt
+		default:
+			// Queue is full, drop the batch
+			utils.Warn("Log task queue full! Dropping log batch (%d entries) from host %s", len(batch), meta.Hostname)
+			dropped += len(batch)
+			selfmetrics.RecordDropped("logs", len(batch))
 		}
 	}
+	return queued, dropped, true
 }
@@ -2,18 +2,24 @@ package logsender
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
 	"github.com/aaronlmathis/gosight-agent/internal/config"
 	grpcconn "github.com/aaronlmathis/gosight-agent/internal/grpc"
 	"github.com/aaronlmathis/gosight-agent/internal/otelconvert"
+	"github.com/aaronlmathis/gosight-agent/internal/selfmetrics"
+	"github.com/aaronlmathis/gosight-agent/internal/sink"
+	"github.com/aaronlmathis/gosight-agent/internal/spool"
+	agentutils "github.com/aaronlmathis/gosight-agent/internal/utils"
 	"github.com/aaronlmathis/gosight-shared/model"
 	"github.com/aaronlmathis/gosight-shared/utils"
 	collogpb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	goproto "google.golang.org/protobuf/proto"
 )
 
 // LogSender holds the gRPC client and connection for OTLP logs.
@@ -23,26 +29,33 @@ type LogSender struct {
 	wg     sync.WaitGroup
 	cfg    *config.Config
 	ctx    context.Context
+
+	// outbox spools exports while the server is unreachable (nil = disabled, see spool.go)
+	outbox *spool.Outbox
+
+	// maxExportBytes is the encoded size above which a batch is split
+	// (0 = grpcconn.MaxMessageSize)
+	maxExportBytes int
 }
 
 // NewSender initializes a new LogSender and starts the connection manager.
 // It returns immediately and launches the background connection manager.
 func NewSender(ctx context.Context, cfg *config.Config) (*LogSender, error) {
 	s := &LogSender{ctx: ctx, cfg: cfg}
+	if sink.Enabled(cfg) {
+		// Dry run: print exports instead of connecting
+		s.client = sink.NewLogsClient(nil)
+		utils.Info("Log exports are printed to stdout; not connecting to the server")
+		return s, nil
+	}
+	s.openSpool()
 	go s.manageConnection()
 	return s, nil
 }
 
 // manageConnection dials & maintains the connection, tears it down on global disconnect,
-// and retries with exponential backoff up to maxBackoff, then fixed-interval.
+// and retries using the backoff shared with the other senders (see grpcconn.WaitForRetry).
 func (s *LogSender) manageConnection() {
-	const (
-		initial    = 1 * time.Second
-		maxBackoff = 15 * time.Minute
-		factor     = 2
-	)
-
-	backoff := initial
 	var lastPause time.Time
 
 	for {
@@ -59,7 +72,6 @@ func (s *LogSender) manageConnection() {
 		if pu.After(lastPause) {
 			utils.Info("Global disconnect: closing log connection")
 			s.client = nil
-			backoff = initial
 			lastPause = pu
 		}
 
@@ -83,22 +95,10 @@ func (s *LogSender) manageConnection() {
 		// Try to establish connection
 		cc, err := grpcconn.GetGRPCConn(s.cfg)
 		if err != nil {
-			utils.Info("Server offline (dial): retrying in %s", backoff)
-
-			// Sleep with context cancellation check
-			select {
-			case <-time.After(backoff):
-			case <-s.ctx.Done():
+			grpcconn.ReportFailure("logs dial")
+			if !grpcconn.WaitForRetry(s.ctx) {
 				return
 			}
-
-			// Calculate next backoff duration
-			if backoff < maxBackoff {
-				backoff = time.Duration(float64(backoff) * float64(factor))
-				if backoff > maxBackoff {
-					backoff = maxBackoff
-				}
-			}
 			continue
 		}
 
@@ -106,8 +106,8 @@ func (s *LogSender) manageConnection() {
 		s.client = collogpb.NewLogsServiceClient(cc)
 		utils.Info("OTLP logs client connected")
 
-		// Reset backoff on successful connection
-		backoff = initial
+		// End the shared backoff on successful connection
+		grpcconn.ReportSuccess()
 
 		// Brief pause to catch any new disconnects, but allow for context cancellation
 		select {
@@ -120,34 +120,153 @@ func (s *LogSender) manageConnection() {
 
 // SendLogs converts the LogPayload to OTLP format and sends it via unary call.
 // If no active client, returns Unavailable so your worker backoff kicks in.
+// A batch whose encoded request exceeds the gRPC message limit is split in
+// halves recursively; entries that are too large on their own are dropped
+// with a warning so one giant line cannot wedge a batch.
+// With agent.spool.dir set, exports that fail because the server is
+// unreachable are spooled to disk, and the spool is drained oldest-first
+// before live data once the server is back.
 func (s *LogSender) SendLogs(payload *model.LogPayload) error {
 	if s.client == nil {
-		return status.Error(codes.Unavailable, "no active OTLP logs client")
+		err := status.Error(codes.Unavailable, "no active OTLP logs client")
+		if s.outbox != nil {
+			if otlpReq := s.buildRequest(payload); otlpReq != nil && s.spoolExport(otlpReq, err) {
+				return nil
+			}
+		}
+		return err
 	}
 
-	// Convert to OTLP format using our conversion function
-	otlpReq := otelconvert.ConvertToOTLPLogs(payload)
+	// Exports spooled during an outage go out before fresh data; while the
+	// backlog cannot be sent, fresh data joins it to keep the order
+	if err := s.drainSpool(); err != nil {
+		if otlpReq := s.buildRequest(payload); otlpReq != nil && s.spoolExport(otlpReq, err) {
+			return nil
+		}
+		return err
+	}
+
+	utils.Info("Sending %d logs to server via OTLP", len(payload.Logs))
+	unsent, err := s.sendSplit(payload, payload.Logs)
+	if err != nil && len(unsent) < len(payload.Logs) {
+		return &PartialSendError{Err: err, Unsent: unsent}
+	}
+	return err
+}
+
+// PartialSendError is returned by SendLogs when a split batch was only partly
+// exported. Unsent holds the entries that were not, so a retry resends just
+// those instead of duplicating the ones the server already has.
+type PartialSendError struct {
+	Err    error
+	Unsent []model.LogEntry
+}
+
+func (e *PartialSendError) Error() string {
+	return fmt.Sprintf("%d log entries not sent: %v", len(e.Unsent), e.Err)
+}
+
+func (e *PartialSendError) Unwrap() error { return e.Err }
+
+// sendSplit exports logs as a copy of payload, halving while the encoded
+// request is over the message limit. It returns the entries that could not
+// be exported along with the first error; entries too large to send on
+// their own are dropped, not returned.
+func (s *LogSender) sendSplit(payload *model.LogPayload, logs []model.LogEntry) ([]model.LogEntry, error) {
+	part := *payload
+	part.Logs = logs
+
+	otlpReq := s.buildRequest(&part)
 	if otlpReq == nil {
 		utils.Warn("Failed to convert logs to OTLP format")
-		return status.Error(codes.InvalidArgument, "failed to convert logs to OTLP")
+		return logs, status.Error(codes.InvalidArgument, "failed to convert logs to OTLP")
+	}
+	size := goproto.Size(otlpReq)
+	if size <= s.maxExportSize() {
+		if err := s.exportLogs(otlpReq, len(logs)); err != nil {
+			return logs, err
+		}
+		return nil, nil
 	}
 
-	// Send via unary call (OTLP standard)
-	utils.Info("Sending %d logs to server via OTLP", len(payload.Logs))
+	if len(logs) <= 1 {
+		msg := ""
+		if len(logs) == 1 {
+			msg = utils.Truncate(logs[0].Message, 80)
+		}
+		utils.Warn("Dropping log entry too large to send (%d bytes): %q", size, msg)
+		return nil, nil
+	}
+
+	mid := len(logs) / 2
+	utils.Debug("Log batch of %d (%d bytes) too large; splitting into %d and %d", len(logs), size, mid, len(logs)-mid)
+
+	unsentFirst, errFirst := s.sendSplit(payload, logs[:mid])
+	unsentSecond, errSecond := s.sendSplit(payload, logs[mid:])
+	// Capped so appending copies instead of writing into logs
+	unsent := append(unsentFirst[:len(unsentFirst):len(unsentFirst)], unsentSecond...)
+	if errFirst != nil {
+		return unsent, errFirst
+	}
+	return unsent, errSecond
+}
+
+// maxExportSize returns the encoded size above which a batch is split.
+func (s *LogSender) maxExportSize() int {
+	if s.maxExportBytes > 0 {
+		return s.maxExportBytes
+	}
+	return grpcconn.MaxMessageSize
+}
+
+// exportLogs performs a single Export call for a request of n entries.
+func (s *LogSender) exportLogs(otlpReq *collogpb.ExportLogsServiceRequest, n int) error {
+	agentutils.DebugPayload(s.cfg, "logs", otlpReq)
+	selfmetrics.RecordExportSize("logs", goproto.Size(otlpReq))
 
 	ctx, cancel := context.WithTimeout(s.ctx, 30*time.Second)
 	defer cancel()
 
-	_, err := s.client.Export(ctx, otlpReq)
+	client := s.client
+	if client == nil {
+		return status.Error(codes.Unavailable, "no active OTLP logs client")
+	}
+	_, err := client.Export(ctx, otlpReq, grpcconn.ExportCallOptions(s.cfg)...)
 	if err != nil {
 		utils.Warn("OTLP logs export failed: %v", err)
+		if s.spoolExport(otlpReq, err) {
+			return nil
+		}
 		return err
 	}
 
-	utils.Debug("Successfully exported %d logs via OTLP", len(payload.Logs))
+	utils.Debug("Successfully exported %d logs via OTLP", n)
 	return nil
 }
 
+// buildRequest converts payload to an OTLP export request with the
+// configured resource attribute mapping and scope version, or nil if the
+// conversion fails.
+func (s *LogSender) buildRequest(payload *model.LogPayload) *collogpb.ExportLogsServiceRequest {
+	otlpReq := otelconvert.ConvertToOTLPLogs(payload)
+	if otlpReq == nil {
+		return nil
+	}
+	config.RLock()
+	defer config.RUnlock()
+	if payload.Meta != nil {
+		otelconvert.MapLogsResourceTags(otlpReq, payload.Meta.Tags, s.cfg.Agent.ResourceAttributeMapping)
+	}
+	otelconvert.SetLogsScopeVersion(otlpReq, s.cfg.Agent.ScopeVersion)
+	return otlpReq
+}
+
+// Wait waits up to timeout for the stopped worker pool to finish its
+// in-flight exports. It returns false if they are still running.
+func (s *LogSender) Wait(timeout time.Duration) bool {
+	return agentutils.WaitWorkers(&s.wg, timeout)
+}
+
 // Close shuts down worker pool and closes the gRPC connection.
 func (s *LogSender) Close() error {
 	utils.Info("Closing LogSender... waiting for workers")
@@ -0,0 +1,139 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+package logsender
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aaronlmathis/gosight-agent/internal/config"
+	"github.com/aaronlmathis/gosight-shared/model"
+	collogpb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	goproto "google.golang.org/protobuf/proto"
+)
+
+// splitClient fails any batch containing the message "fail" with code, only
+// the first recoverAfter times if set; it records the messages it accepted.
+type splitClient struct {
+	code         codes.Code
+	recoverAfter int
+	failed       int
+	accepted     []string
+}
+
+// exportedMessages returns the log bodies in req.
+func exportedMessages(req *collogpb.ExportLogsServiceRequest) []string {
+	var msgs []string
+	for _, rl := range req.ResourceLogs {
+		for _, sl := range rl.ScopeLogs {
+			for _, lr := range sl.LogRecords {
+				msgs = append(msgs, lr.Body.GetStringValue())
+			}
+		}
+	}
+	return msgs
+}
+
+func (c *splitClient) Export(_ context.Context, req *collogpb.ExportLogsServiceRequest, _ ...grpc.CallOption) (*collogpb.ExportLogsServiceResponse, error) {
+	msgs := exportedMessages(req)
+	for _, m := range msgs {
+		if m == "fail" && (c.recoverAfter == 0 || c.failed < c.recoverAfter) {
+			c.failed++
+			return nil, status.Error(c.code, "rejected")
+		}
+	}
+	c.accepted = append(c.accepted, msgs...)
+	return &collogpb.ExportLogsServiceResponse{}, nil
+}
+
+func TestSendLogsReturnsOnlyUnsentHalf(t *testing.T) {
+	client := &splitClient{code: codes.Internal}
+	s := &LogSender{client: client, cfg: &config.Config{}, ctx: context.Background()}
+
+	payload := &model.LogPayload{Meta: &model.Meta{}, Logs: []model.LogEntry{
+		{Message: "a"}, {Message: "b"}, {Message: "fail"}, {Message: "c"},
+	}}
+	// Two entries fit the limit, four do not
+	half := *payload
+	half.Logs = payload.Logs[2:]
+	s.maxExportBytes = goproto.Size(s.buildRequest(&half))
+	err := s.SendLogs(payload)
+
+	var partial *PartialSendError
+	if !errors.As(err, &partial) {
+		t.Fatalf("expected a PartialSendError, got %v", err)
+	}
+	if len(partial.Unsent) != 2 || partial.Unsent[0].Message != "fail" || partial.Unsent[1].Message != "c" {
+		t.Errorf("unexpected unsent entries: %+v", partial.Unsent)
+	}
+	if len(client.accepted) != 2 || client.accepted[0] != "a" || client.accepted[1] != "b" {
+		t.Errorf("unexpected accepted entries: %v", client.accepted)
+	}
+	if status.Code(errors.Unwrap(err)) != codes.Internal {
+		t.Errorf("expected the export error to be wrapped, got %v", err)
+	}
+}
+
+func TestSendLogsDoesNotSplitOnResourceExhausted(t *testing.T) {
+	client := &splitClient{code: codes.ResourceExhausted}
+	s := &LogSender{client: client, cfg: &config.Config{}, ctx: context.Background()}
+
+	payload := &model.LogPayload{Meta: &model.Meta{}, Logs: []model.LogEntry{
+		{Message: "a"}, {Message: "fail"},
+	}}
+	err := s.SendLogs(payload)
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected the throttling error to be returned, got %v", err)
+	}
+	if len(client.accepted) != 0 {
+		t.Errorf("expected a throttled batch not to be split and partly sent, got %v", client.accepted)
+	}
+}
+
+func TestTrySendWithBackoffRetriesUnsent(t *testing.T) {
+	client := &splitClient{code: codes.Unavailable, recoverAfter: 1}
+	s := &LogSender{client: client, cfg: &config.Config{}, ctx: context.Background()}
+
+	payload := &model.LogPayload{Meta: &model.Meta{}, Logs: []model.LogEntry{
+		{Message: "a"}, {Message: "b"}, {Message: "fail"}, {Message: "c"},
+	}}
+	half := *payload
+	half.Logs = payload.Logs[2:]
+	s.maxExportBytes = goproto.Size(s.buildRequest(&half))
+
+	if err := s.trySendWithBackoff(context.Background(), payload); err != nil {
+		t.Fatalf("trySendWithBackoff: %v", err)
+	}
+	want := []string{"a", "b", "fail", "c"}
+	if len(client.accepted) != len(want) {
+		t.Fatalf("expected each entry to be sent once, got %v", client.accepted)
+	}
+	for i := range want {
+		if client.accepted[i] != want[i] {
+			t.Fatalf("expected each entry to be sent once, got %v", client.accepted)
+		}
+	}
+}
@@ -26,10 +26,14 @@ package logsender
 
 import (
 	"context"
+	"errors"
 	"time"
 
+	"github.com/aaronlmathis/gosight-agent/internal/selfmetrics"
 	"github.com/aaronlmathis/gosight-shared/model"
 	"github.com/aaronlmathis/gosight-shared/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // StartWorkerPool launches N workers and processes metric payloads with retries
@@ -50,8 +54,9 @@ func (s *LogSender) StartWorkerPool(ctx context.Context, queue <-chan *model.Log
 				default:
 				}
 
-				//  If not connected, wait and retry
-				if s.client == nil { // Changed from s.stream to s.client
+				//  If not connected, wait and retry (with a spool, keep
+				//  draining the queue to disk instead)
+				if s.client == nil && s.outbox == nil {
 					time.Sleep(500 * time.Millisecond)
 					continue
 				}
@@ -65,9 +70,10 @@ func (s *LogSender) StartWorkerPool(ctx context.Context, queue <-chan *model.Log
 					return
 				}
 
-				//  Send (errors will be logged)
-				if err := s.SendLogs(payload); err != nil {
+				//  Send, retrying transient failures (errors will be logged)
+				if err := s.trySendWithBackoff(ctx, payload); err != nil {
 					utils.Warn("Log worker #%d failed to send payload: %v", id, err)
+					selfmetrics.RecordSendError("logs")
 				}
 			}
 		}(i + 1)
@@ -75,21 +81,43 @@ func (s *LogSender) StartWorkerPool(ctx context.Context, queue <-chan *model.Log
 }
 
 // trySendWithBackoff attempts to send the log payload to the server with exponential backoff.
-// It retries sending the payload up to 5 times with increasing wait times between attempts.
-// If all attempts fail, it returns the last error encountered.
-// The backoff starts at 500ms and doubles each time, up to a maximum of 10 seconds.
-func (s *LogSender) trySendWithBackoff(payload *model.LogPayload) error {
+// Transient failures (Unavailable, DeadlineExceeded, ResourceExhausted) are
+// retried up to 5 attempts in total; after a partial send only the entries
+// that were not exported are retried, so the server never gets duplicates.
+// The backoff starts at 500ms and doubles each time, up to a maximum of 10
+// seconds, and ends early when ctx is done. The last error is returned.
+func (s *LogSender) trySendWithBackoff(ctx context.Context, payload *model.LogPayload) error {
 	var err error
 	backoff := 500 * time.Millisecond
 	maxBackoff := 10 * time.Second
 
-	for retries := 0; retries < 1; retries++ {
+	for attempt := 1; attempt <= 5; attempt++ {
 		err = s.SendLogs(payload)
 		if err == nil {
 			return nil
 		}
-		utils.Warn("Retrying in %v: %v", backoff, err)
-		time.Sleep(backoff)
+		switch status.Code(err) {
+		case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted:
+		default:
+			return err
+		}
+		if attempt == 5 {
+			break
+		}
+
+		// Only retry the part of a split batch that was not exported
+		var partial *PartialSendError
+		if errors.As(err, &partial) {
+			retry := *payload
+			retry.Logs = partial.Unsent
+			payload = &retry
+		}
+		utils.Warn("Retrying %d log entries in %v [attempt %d/5]: %v", len(payload.Logs), backoff, attempt, err)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return err
+		}
 		backoff *= 2
 		if backoff > maxBackoff {
 			backoff = maxBackoff
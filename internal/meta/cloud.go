@@ -0,0 +1,321 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/meta/cloud.go
+// cloud.go detects the cloud provider from the instance metadata services.
+
+package meta
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aaronlmathis/gosight-agent/internal/config"
+	"github.com/aaronlmathis/gosight-shared/model"
+	"github.com/aaronlmathis/gosight-shared/utils"
+)
+
+// cloudDetectTimeout bounds the whole detection, so bare metal hosts (where
+// the metadata addresses don't answer) only pay it once at startup.
+const cloudDetectTimeout = 2 * time.Second
+
+// Instance metadata service endpoints. AWS and Azure share the link-local
+// address but use different paths and headers.
+var (
+	awsMetadataURL   = "http://169.254.169.254"
+	gcpMetadataURL   = "http://metadata.google.internal"
+	azureMetadataURL = "http://169.254.169.254"
+)
+
+// cloudInfo is what a provider's metadata service reports about the instance.
+type cloudInfo struct {
+	Provider      string
+	Region        string
+	Zone          string
+	InstanceID    string
+	InstanceType  string
+	AccountID     string
+	ProjectID     string
+	ResourceGroup string
+	VPCID         string
+	SubnetID      string
+	ImageID       string
+}
+
+// cloudDetector queries one provider's metadata service and returns nil if
+// the host doesn't run on that provider.
+type cloudDetector func(ctx context.Context, client *http.Client) (*cloudInfo, error)
+
+var (
+	cloudOnce   sync.Once
+	cloudCached *cloudInfo
+)
+
+// applyCloudMetadata fills the cloud fields of meta (sent as the cloud.*
+// resource attributes) from the instance metadata service. Detection runs
+// once per process and is cached; hosts outside a cloud get no provider.
+func applyCloudMetadata(cfg *config.Config, meta *model.Meta) {
+	if !cfg.Agent.CloudDetection {
+		return
+	}
+
+	cloudOnce.Do(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), cloudDetectTimeout)
+		defer cancel()
+		// The metadata services are link-local: never send the probes
+		// through HTTP(S)_PROXY
+		client := &http.Client{Timeout: cloudDetectTimeout, Transport: &http.Transport{Proxy: nil}}
+		cloudCached = detectCloud(ctx, client)
+		if cloudCached != nil {
+			utils.Info("Detected cloud provider %s (region %q, instance %q)", cloudCached.Provider, cloudCached.Region, cloudCached.InstanceID)
+		} else {
+			utils.Debug("No cloud provider detected")
+		}
+	})
+
+	info := cloudCached
+	if info == nil {
+		return
+	}
+	meta.CloudProvider = info.Provider
+	meta.Region = info.Region
+	meta.AvailabilityZone = info.Zone
+	meta.InstanceID = info.InstanceID
+	meta.InstanceType = info.InstanceType
+	meta.AccountID = info.AccountID
+	meta.ProjectID = info.ProjectID
+	meta.ResourceGroup = info.ResourceGroup
+	meta.VPCID = info.VPCID
+	meta.SubnetID = info.SubnetID
+	meta.ImageID = info.ImageID
+}
+
+// detectCloud queries every provider concurrently and returns the first
+// match in provider order, or nil.
+func detectCloud(ctx context.Context, client *http.Client) *cloudInfo {
+	detectors := []cloudDetector{detectAWS, detectGCP, detectAzure}
+	results := make([]*cloudInfo, len(detectors))
+
+	var wg sync.WaitGroup
+	for i, detect := range detectors {
+		wg.Add(1)
+		go func(i int, detect cloudDetector) {
+			defer wg.Done()
+			info, err := detect(ctx, client)
+			if err != nil {
+				utils.Debug("Cloud detection: %v", err)
+				return
+			}
+			results[i] = info
+		}(i, detect)
+	}
+	wg.Wait()
+
+	for _, info := range results {
+		if info != nil {
+			return info
+		}
+	}
+	return nil
+}
+
+// metadataGet performs a metadata request and returns the body of a 200
+// response. A non-200 status yields a nil body and no error.
+func metadataGet(ctx context.Context, client *http.Client, method, url string, header map[string]string) ([]byte, http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, resp.Header, nil
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	return body, resp.Header, err
+}
+
+// detectAWS reads the EC2 instance identity document, using an IMDSv2
+// session token when the service issues one and falling back to IMDSv1.
+func detectAWS(ctx context.Context, client *http.Client) (*cloudInfo, error) {
+	token, _, err := metadataGet(ctx, client, http.MethodPut, awsMetadataURL+"/latest/api/token",
+		map[string]string{"X-aws-ec2-metadata-token-ttl-seconds": "60"})
+	if err != nil {
+		return nil, fmt.Errorf("aws: %w", err)
+	}
+	header := map[string]string{}
+	if len(token) > 0 {
+		header["X-aws-ec2-metadata-token"] = string(token)
+	}
+	get := func(p string) string {
+		body, _, err := metadataGet(ctx, client, http.MethodGet, awsMetadataURL+p, header)
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSpace(string(body))
+	}
+
+	body, _, err := metadataGet(ctx, client, http.MethodGet, awsMetadataURL+"/latest/dynamic/instance-identity/document", header)
+	if err != nil || body == nil {
+		return nil, err
+	}
+	var doc struct {
+		AccountID        string `json:"accountId"`
+		AvailabilityZone string `json:"availabilityZone"`
+		ImageID          string `json:"imageId"`
+		InstanceID       string `json:"instanceId"`
+		InstanceType     string `json:"instanceType"`
+		Region           string `json:"region"`
+	}
+	if err := json.Unmarshal(body, &doc); err != nil || doc.InstanceID == "" {
+		return nil, nil
+	}
+
+	info := &cloudInfo{
+		Provider:     "aws",
+		Region:       doc.Region,
+		Zone:         doc.AvailabilityZone,
+		InstanceID:   doc.InstanceID,
+		InstanceType: doc.InstanceType,
+		AccountID:    doc.AccountID,
+		ImageID:      doc.ImageID,
+	}
+	if mac := get("/latest/meta-data/mac"); mac != "" {
+		iface := "/latest/meta-data/network/interfaces/macs/" + mac
+		info.VPCID = get(iface + "/vpc-id")
+		info.SubnetID = get(iface + "/subnet-id")
+	}
+	return info, nil
+}
+
+// detectGCP reads the GCE metadata tree. The Metadata-Flavor response header
+// tells the real server apart from anything else answering the name.
+func detectGCP(ctx context.Context, client *http.Client) (*cloudInfo, error) {
+	body, header, err := metadataGet(ctx, client, http.MethodGet, gcpMetadataURL+"/computeMetadata/v1/?recursive=true",
+		map[string]string{"Metadata-Flavor": "Google"})
+	if err != nil {
+		return nil, fmt.Errorf("gcp: %w", err)
+	}
+	if body == nil || header.Get("Metadata-Flavor") != "Google" {
+		return nil, nil
+	}
+
+	var doc struct {
+		Instance struct {
+			ID                json.Number `json:"id"`
+			MachineType       string      `json:"machineType"`
+			Zone              string      `json:"zone"`
+			Image             string      `json:"image"`
+			NetworkInterfaces []struct {
+				Network    string `json:"network"`
+				Subnetwork string `json:"subnetwork"`
+			} `json:"networkInterfaces"`
+		} `json:"instance"`
+		Project struct {
+			ProjectID        string      `json:"projectId"`
+			NumericProjectID json.Number `json:"numericProjectId"`
+		} `json:"project"`
+	}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, nil
+	}
+
+	// Zones and machine types are resource paths such as
+	// "projects/123/zones/us-central1-a"
+	zone := path.Base(doc.Instance.Zone)
+	region := zone
+	if i := strings.LastIndex(zone, "-"); i > 0 {
+		region = zone[:i]
+	}
+	info := &cloudInfo{
+		Provider:     "gcp",
+		Region:       region,
+		Zone:         zone,
+		InstanceID:   doc.Instance.ID.String(),
+		InstanceType: path.Base(doc.Instance.MachineType),
+		AccountID:    doc.Project.NumericProjectID.String(),
+		ProjectID:    doc.Project.ProjectID,
+		ImageID:      doc.Instance.Image,
+	}
+	if len(doc.Instance.NetworkInterfaces) > 0 {
+		nic := doc.Instance.NetworkInterfaces[0]
+		info.VPCID = path.Base(nic.Network)
+		if nic.Subnetwork != "" {
+			info.SubnetID = path.Base(nic.Subnetwork)
+		}
+	}
+	return info, nil
+}
+
+// detectAzure reads the Azure Instance Metadata Service compute document.
+func detectAzure(ctx context.Context, client *http.Client) (*cloudInfo, error) {
+	body, _, err := metadataGet(ctx, client, http.MethodGet, azureMetadataURL+"/metadata/instance/compute?api-version=2021-02-01",
+		map[string]string{"Metadata": "true"})
+	if err != nil {
+		return nil, fmt.Errorf("azure: %w", err)
+	}
+	if body == nil {
+		return nil, nil
+	}
+
+	var doc struct {
+		Location          string `json:"location"`
+		Zone              string `json:"zone"`
+		VMID              string `json:"vmId"`
+		VMSize            string `json:"vmSize"`
+		SubscriptionID    string `json:"subscriptionId"`
+		ResourceGroupName string `json:"resourceGroupName"`
+		StorageProfile    struct {
+			ImageReference struct {
+				ID string `json:"id"`
+			} `json:"imageReference"`
+		} `json:"storageProfile"`
+	}
+	if err := json.Unmarshal(body, &doc); err != nil || doc.VMID == "" {
+		return nil, nil
+	}
+
+	return &cloudInfo{
+		Provider:      "azure",
+		Region:        doc.Location,
+		Zone:          doc.Zone,
+		InstanceID:    doc.VMID,
+		InstanceType:  doc.VMSize,
+		AccountID:     doc.SubscriptionID,
+		ResourceGroup: doc.ResourceGroupName,
+		ImageID:       doc.StorageProfile.ImageReference.ID,
+	}, nil
+}
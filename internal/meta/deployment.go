@@ -0,0 +1,81 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/meta/deployment.go
+// Resolves the deploy/build identifier stamped on all signals.
+
+package meta
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"strings"
+
+	"github.com/aaronlmathis/gosight-agent/internal/config"
+	"github.com/aaronlmathis/gosight-shared/model"
+	"github.com/aaronlmathis/gosight-shared/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// configHashLen is the number of hex characters kept from the config hash.
+const configHashLen = 12
+
+// applyDeploymentID sets meta.DeploymentID (sent as the deployment.id resource
+// attribute) from agent.deployment_id. Sources are tried in order: the literal
+// value, the environment variable, the first line of the file, and finally a
+// hash of the loaded configuration if config_hash is set.
+func applyDeploymentID(cfg *config.Config, meta *model.Meta) {
+	if id := resolveDeploymentID(cfg); id != "" {
+		meta.DeploymentID = id
+	}
+}
+
+func resolveDeploymentID(cfg *config.Config) string {
+	dc := cfg.Agent.DeploymentID
+
+	if v := strings.TrimSpace(dc.Value); v != "" {
+		return v
+	}
+	if dc.Env != "" {
+		if v := strings.TrimSpace(os.Getenv(dc.Env)); v != "" {
+			return v
+		}
+	}
+	if dc.File != "" {
+		data, err := os.ReadFile(dc.File)
+		if err != nil {
+			utils.Warn("Failed to read deployment id file %s: %v", dc.File, err)
+		} else if line, _, _ := strings.Cut(string(data), "\n"); strings.TrimSpace(line) != "" {
+			return strings.TrimSpace(line)
+		}
+	}
+	if dc.ConfigHash {
+		data, err := yaml.Marshal(cfg)
+		if err != nil {
+			utils.Warn("Failed to hash configuration for deployment id: %v", err)
+			return ""
+		}
+		sum := sha256.Sum256(data)
+		return hex.EncodeToString(sum[:])[:configHashLen]
+	}
+	return ""
+}
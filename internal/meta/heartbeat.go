@@ -0,0 +1,51 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// // gosight/agent/internal/meta/heartbeat.go
+// // Stamps payloads with a cycle sequence number and collection time.
+
+package meta
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/aaronlmathis/gosight-shared/model"
+)
+
+const (
+	// SequenceTag carries the collection cycle number. It increases by one per
+	// cycle for the life of the agent process (see agent_start_time), so gaps
+	// after a reconnect and spool replay show exactly which cycles were lost.
+	SequenceTag = "agent_sequence"
+
+	// CollectedAtTag carries the cycle's collection time in RFC 3339 (nanosecond) format.
+	CollectedAtTag = "agent_collected_at"
+)
+
+// StampHeartbeat records the collection cycle sequence and time on meta.
+func StampHeartbeat(meta *model.Meta, seq uint64, collectedAt time.Time) {
+	if meta.Tags == nil {
+		meta.Tags = make(map[string]string)
+	}
+	meta.Tags[SequenceTag] = strconv.FormatUint(seq, 10)
+	meta.Tags[CollectedAtTag] = collectedAt.UTC().Format(time.RFC3339Nano)
+}
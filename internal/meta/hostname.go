@@ -0,0 +1,92 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/meta/hostname.go
+
+package meta
+
+import (
+	"net"
+	"os"
+	"strings"
+
+	"github.com/aaronlmathis/gosight-agent/internal/config"
+	"github.com/aaronlmathis/gosight-shared/utils"
+)
+
+// ResolveHostname returns the hostname reported in agent metadata.
+// Resolution order:
+//  1. agent.host (HostOverride), if set
+//  2. the fully qualified domain name, if agent.use_fqdn is true and it resolves
+//  3. os.Hostname() (usually the short name)
+//  4. "unknown"
+func ResolveHostname(cfg *config.Config) string {
+	if cfg != nil && cfg.Agent.HostOverride != "" {
+		return cfg.Agent.HostOverride
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		utils.Warn("Failed to get hostname: %v", err)
+		return "unknown"
+	}
+
+	if cfg != nil && cfg.Agent.UseFQDN {
+		if fqdn := lookupFQDN(hostname); fqdn != "" {
+			return fqdn
+		}
+		utils.Warn("Failed to resolve FQDN for %s; using short hostname", hostname)
+	}
+
+	return hostname
+}
+
+// lookupFQDN mimics `hostname -f`: it first asks the resolver for the
+// canonical name, then falls back to a reverse lookup of the host's addresses.
+// It returns "" if no dotted name can be found.
+func lookupFQDN(hostname string) string {
+	if strings.Contains(hostname, ".") {
+		return hostname
+	}
+
+	if cname, err := net.LookupCNAME(hostname); err == nil {
+		if name := strings.TrimSuffix(cname, "."); strings.Contains(name, ".") {
+			return name
+		}
+	}
+
+	addrs, err := net.LookupHost(hostname)
+	if err != nil {
+		return ""
+	}
+	for _, addr := range addrs {
+		names, err := net.LookupAddr(addr)
+		if err != nil {
+			continue
+		}
+		for _, n := range names {
+			if name := strings.TrimSuffix(n, "."); strings.Contains(name, ".") {
+				return name
+			}
+		}
+	}
+	return ""
+}
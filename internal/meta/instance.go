@@ -0,0 +1,77 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// // gosight/agent/internal/meta/instance.go
+// // Distinguishes multiple agent instances running on the same host.
+
+package meta
+
+import (
+	"strings"
+
+	"github.com/aaronlmathis/gosight-agent/internal/config"
+	"github.com/aaronlmathis/gosight-shared/model"
+	"github.com/aaronlmathis/gosight-shared/utils"
+)
+
+// InstanceTag is the meta tag carrying the configured agent.instance_name.
+const InstanceTag = "agent_instance"
+
+// applyInstanceName adds the configured instance name, if any, to tags.
+func applyInstanceName(cfg *config.Config, tags map[string]string) map[string]string {
+	name := strings.TrimSpace(cfg.Agent.InstanceName)
+	if name == "" {
+		return tags
+	}
+	if tags == nil {
+		tags = make(map[string]string)
+	}
+	tags[InstanceTag] = name
+	return tags
+}
+
+// GenerateEndpointID wraps utils.GenerateEndpointID and appends the agent
+// instance name when one is configured, so several agents on one host
+// report distinct endpoints.
+func GenerateEndpointID(meta *model.Meta) string {
+	id := utils.GenerateEndpointID(meta)
+	if meta == nil {
+		return id
+	}
+	if name := meta.Tags[InstanceTag]; name != "" {
+		return id + "-" + sanitizeInstance(name)
+	}
+	return id
+}
+
+// sanitizeInstance lowercases name and replaces anything outside [a-z0-9_-] with '-'.
+func sanitizeInstance(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + ('a' - 'A')
+		default:
+			return '-'
+		}
+	}, name)
+}
@@ -0,0 +1,137 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/meta/maintenance.go
+// Marks outgoing signals as sent during planned maintenance.
+
+package meta
+
+import (
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aaronlmathis/gosight-agent/internal/config"
+	"github.com/aaronlmathis/gosight-shared/model"
+)
+
+// MaintenanceTag is set to "true" on every payload's meta while maintenance
+// mode is on and is sent as the maintenance resource attribute, so the server
+// can suppress alerts without the agent stopping collection.
+const MaintenanceTag = "maintenance"
+
+var maintenance struct {
+	sync.Mutex
+	file            string
+	defaultDuration time.Duration
+
+	// Window set by the maintenance command; a zero until means indefinite.
+	active bool
+	until  time.Time
+}
+
+// ConfigureMaintenance applies agent.maintenance: the file whose presence
+// turns maintenance mode on and the default window of the maintenance command.
+func ConfigureMaintenance(cfg *config.Config) {
+	maintenance.Lock()
+	defer maintenance.Unlock()
+	maintenance.file = cfg.Agent.Maintenance.File
+	maintenance.defaultDuration = cfg.Agent.Maintenance.DefaultDuration
+}
+
+// StartMaintenance turns maintenance mode on for d, or for the configured
+// default window if d is zero. A resulting window of zero is indefinite.
+// It returns the end of the window (zero if indefinite).
+func StartMaintenance(d time.Duration) time.Time {
+	maintenance.Lock()
+	defer maintenance.Unlock()
+
+	if d <= 0 {
+		d = maintenance.defaultDuration
+	}
+	maintenance.active = true
+	maintenance.until = time.Time{}
+	if d > 0 {
+		maintenance.until = time.Now().Add(d)
+	}
+	return maintenance.until
+}
+
+// StopMaintenance ends a window set by StartMaintenance. A maintenance file,
+// if present, keeps maintenance mode on until it is removed.
+func StopMaintenance() {
+	maintenance.Lock()
+	defer maintenance.Unlock()
+	maintenance.active = false
+	maintenance.until = time.Time{}
+}
+
+// InMaintenance reports whether maintenance mode is on at now, either from
+// the maintenance command or from the maintenance file.
+func InMaintenance(now time.Time) bool {
+	maintenance.Lock()
+	active, until, file := maintenance.active, maintenance.until, maintenance.file
+	maintenance.Unlock()
+
+	if active && (until.IsZero() || now.Before(until)) {
+		return true
+	}
+	return file != "" && maintenanceFileActive(file, now)
+}
+
+// maintenanceFileActive reports whether the maintenance file exists and its
+// window has not ended. The first line may hold an RFC 3339 end time or a
+// duration counted from the file's modification time; anything else (or an
+// empty or unreadable file) means indefinite.
+func maintenanceFileActive(path string, now time.Time) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return true
+	}
+	line, _, _ := strings.Cut(string(data), "\n")
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return true
+	}
+	if end, err := time.Parse(time.RFC3339, line); err == nil {
+		return now.Before(end)
+	}
+	if d, err := time.ParseDuration(line); err == nil {
+		return now.Before(info.ModTime().Add(d))
+	}
+	return true
+}
+
+// applyMaintenance stamps MaintenanceTag on meta while maintenance mode is on.
+func applyMaintenance(meta *model.Meta) {
+	if !InMaintenance(time.Now()) {
+		return
+	}
+	if meta.Tags == nil {
+		meta.Tags = make(map[string]string)
+	}
+	meta.Tags[MaintenanceTag] = "true"
+}
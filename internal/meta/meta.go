@@ -24,7 +24,6 @@ along with GoSight. If not, see https://www.gnu.org/licenses/.
 package meta
 
 import (
-	"os"
 	"runtime"
 
 	"github.com/aaronlmathis/gosight-agent/internal/config"
@@ -34,16 +33,12 @@ import (
 )
 
 // BuildMeta constructs the metadata for the agent, including system information and custom tags.
-// It retrieves the hostname, local IP address, and host information using the gopsutil library.
+// It retrieves the hostname (see ResolveHostname), local IP address, and host information using the gopsutil library.
 // The metadata includes the agent ID, version, host ID, hostname, IP address, OS details,
 // and any additional tags provided in the configuration or as arguments.
 
 func BuildMeta(cfg *config.Config, addTags map[string]string, agentID, agentVersion string) *model.Meta {
-	hostname, err := os.Hostname()
-	if err != nil {
-		hostname = "unknown"
-		utils.Warn("Failed to get hostname: %v", err)
-	}
+	hostname := ResolveHostname(cfg)
 
 	ip := utils.GetLocalIP()
 	if ip == "" {
@@ -56,7 +51,7 @@ func BuildMeta(cfg *config.Config, addTags map[string]string, agentID, agentVers
 		hostInfo = &host.InfoStat{}
 	}
 
-	tags := utils.MergeMaps(cfg.CustomTags, addTags)
+	tags := applyInstanceName(cfg, utils.MergeMaps(cfg.CustomTags, addTags))
 
 	meta := &model.Meta{
 		AgentID:              agentID,
@@ -76,12 +71,16 @@ func BuildMeta(cfg *config.Config, addTags map[string]string, agentID, agentVers
 		Architecture:         runtime.GOARCH,
 		Tags:                 tags,
 	}
+	applyCloudMetadata(cfg, meta)
+	applyMetadataFile(cfg, meta)
+	applyDeploymentID(cfg, meta)
 
 	return meta
 }
 
 // CloneMetaWithTags returns a shallow copy of the base Meta
-// but optionally overrides or adds new Tags.
+// but optionally overrides or adds new Tags. While maintenance mode is on the
+// clone also carries the maintenance tag.
 func CloneMetaWithTags(base *model.Meta, extraTags map[string]string) *model.Meta {
 	if base == nil {
 		return nil
@@ -92,6 +91,7 @@ func CloneMetaWithTags(base *model.Meta, extraTags map[string]string) *model.Met
 
 	// Deep copy and merge the Tags map
 	clone.Tags = utils.MergeMaps(base.Tags, extraTags)
+	applyMaintenance(&clone)
 
 	return &clone
 }
@@ -100,11 +100,7 @@ func CloneMetaWithTags(base *model.Meta, extraTags map[string]string) *model.Met
 // It includes additional fields relevant to containerized environments
 // such as container ID, image name, and runtime information.
 func BuildContainerMeta(cfg *config.Config, addTags map[string]string, agentID, agentVersion string) *model.Meta {
-	hostname, err := os.Hostname()
-	if err != nil {
-		hostname = "unknown"
-		utils.Warn("Failed to get hostname: %v", err)
-	}
+	hostname := ResolveHostname(cfg)
 
 	ip := utils.GetLocalIP()
 	if ip == "" {
@@ -118,9 +114,9 @@ func BuildContainerMeta(cfg *config.Config, addTags map[string]string, agentID,
 		hostInfo = &host.InfoStat{}
 	}
 
-	tags := utils.MergeMaps(cfg.CustomTags, addTags)
+	tags := applyInstanceName(cfg, utils.MergeMaps(cfg.CustomTags, addTags))
 
-	return &model.Meta{
+	meta := &model.Meta{
 		AgentID:              agentID,
 		AgentVersion:         agentVersion,
 		HostID:               hostInfo.HostID,
@@ -138,4 +134,9 @@ func BuildContainerMeta(cfg *config.Config, addTags map[string]string, agentID,
 		Architecture:         runtime.GOARCH,
 		Tags:                 tags,
 	}
+	applyCloudMetadata(cfg, meta)
+	applyMetadataFile(cfg, meta)
+	applyDeploymentID(cfg, meta)
+
+	return meta
 }
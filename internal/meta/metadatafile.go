@@ -0,0 +1,98 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// // gosight/agent/internal/meta/metadatafile.go
+// // Loads on-prem topology (region, zone, rack, ...) from a local file.
+
+package meta
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aaronlmathis/gosight-agent/internal/config"
+	"github.com/aaronlmathis/gosight-shared/model"
+	"github.com/aaronlmathis/gosight-shared/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// RackTag is the meta tag carrying the rack read from the metadata file.
+const RackTag = "rack"
+
+// applyMetadataFile reads agent.metadata_file, if set, and maps its keys onto
+// meta: "region" and "zone" (or "availability_zone") fill the same fields
+// cloud detection uses, every other scalar key (e.g. "rack") becomes a tag.
+// Tags already set (custom_tags, instance name) take precedence over file values.
+func applyMetadataFile(cfg *config.Config, meta *model.Meta) {
+	path := cfg.Agent.MetadataFile
+	if path == "" {
+		return
+	}
+
+	values, err := loadMetadataFile(path)
+	if err != nil {
+		utils.Warn("Failed to load metadata file %s: %v", path, err)
+		return
+	}
+
+	if meta.Tags == nil {
+		meta.Tags = make(map[string]string)
+	}
+	for k, v := range values {
+		switch strings.ToLower(k) {
+		case "region":
+			meta.Region = v
+		case "zone", "availability_zone":
+			meta.AvailabilityZone = v
+		default:
+			key := strings.ToLower(k)
+			if _, set := meta.Tags[key]; !set {
+				meta.Tags[key] = v
+			}
+		}
+	}
+}
+
+// loadMetadataFile parses a flat JSON or YAML object (JSON is valid YAML) and
+// returns its scalar values as strings. Nested values are ignored.
+func loadMetadataFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[string]any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parse: %w", err)
+	}
+
+	values := make(map[string]string, len(raw))
+	for k, v := range raw {
+		switch v.(type) {
+		case string, int, int64, uint64, float64, bool:
+			values[k] = fmt.Sprint(v)
+		default:
+			utils.Debug("Metadata file %s: ignoring non-scalar key %q", path, k)
+		}
+	}
+	return values, nil
+}
@@ -34,26 +34,54 @@ import (
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
 
 	agentutils "github.com/aaronlmathis/gosight-agent/internal/utils"
 	"github.com/aaronlmathis/gosight-shared/model"
+	"github.com/aaronlmathis/gosight-shared/utils"
 )
 
 type DockerCollector struct {
-	client *client.Client
+	client      *client.Client
+	labelFields map[string]string
+	apiVersion  string // pinned API version; "" = negotiated
 }
 
 // NewDockerCollector creates a new Docker collector
-// It initializes the Docker client using environment variables
-// and API version negotiation.
-func NewDockerCollector() *DockerCollector {
-	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+// It initializes the Docker client using environment variables and the
+// given API version (e.g. "1.41"), or API version negotiation if empty.
+// labelFields promotes container labels to Meta fields (label key -> field,
+// e.g. com.company.team -> service).
+func NewDockerCollector(labelFields map[string]string, apiVersion string) *DockerCollector {
+	c := &DockerCollector{labelFields: validLabelFields(labelFields), apiVersion: strings.TrimPrefix(apiVersion, "v")}
+	cli, err := newDockerClient(c.apiVersion)
 	if err != nil {
-		return nil
+		utils.Warn("Docker client unavailable: %v", err)
+		return c
 	}
-	return &DockerCollector{client: cli}
+	c.client = cli
+	return c
 }
 
+// newDockerClient creates a client pinned to apiVersion, or negotiating the
+// version with the engine if apiVersion is empty.
+func newDockerClient(apiVersion string) (*client.Client, error) {
+	if apiVersion != "" {
+		return client.NewClientWithOpts(client.FromEnv, client.WithVersion(apiVersion))
+	}
+	return client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+}
+
+// isAPIVersionError reports whether err means the engine does not serve the
+// requested API version: 404 from engines that predate it, or 400 "client
+// version ... is too new/old".
+func isAPIVersionError(err error) bool {
+	return errdefs.IsNotFound(err) || (errdefs.IsInvalidParameter(err) && strings.Contains(err.Error(), "version"))
+}
+
+// Stateful marks the collector as computing rates from the previous call's container stats.
+func (c *DockerCollector) Stateful() {}
+
 // Name returns the name of the collector
 // This is used to identify the collector in logs and metrics.
 func (c *DockerCollector) Name() string {
@@ -69,6 +97,18 @@ func (c *DockerCollector) Collect(ctx context.Context) ([]model.Metric, error) {
 	}
 
 	containers, err := c.client.ContainerList(ctx, types.ContainerListOptions{All: true})
+	if err != nil && c.apiVersion != "" && isAPIVersionError(err) {
+		// The pinned version is not served; negotiate a supported one instead
+		cli, nerr := newDockerClient("")
+		if nerr != nil {
+			return nil, err
+		}
+		cli.NegotiateAPIVersion(ctx)
+		utils.Warn("Docker API version %s not supported by the engine (%v); using %s", c.apiVersion, err, cli.ClientVersion())
+		c.client.Close()
+		c.client, c.apiVersion = cli, ""
+		containers, err = c.client.ContainerList(ctx, types.ContainerListOptions{All: true})
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -96,12 +136,13 @@ func (c *DockerCollector) Collect(ctx context.Context) ([]model.Metric, error) {
 			"runtime":      "docker",
 			"mount_count":  strconv.Itoa(len(ctr.Mounts)),
 		}
-		for k, v := range ctr.Labels {
-			dims["label."+k] = v
-		}
+		addLabelDims(dims, ctr.Labels, c.labelFields)
 		if parts := strings.Split(ctr.Image, ":"); len(parts) == 2 {
 			dims["container_version"] = parts[1]
 		}
+		if ports := formatDockerPorts(ctr.Ports); ports != "" {
+			dims["ports"] = ports
+		}
 
 		inspected, err := c.client.ContainerInspect(ctx, ctr.ID)
 		if err == nil && inspected.State != nil && inspected.State.Health != nil {
@@ -129,12 +170,13 @@ func (c *DockerCollector) Collect(ctx context.Context) ([]model.Metric, error) {
 
 		// Calculate CPU percent and network rates
 		cpuPercent := calculateCPUPercent(ctr.ID, stats.CPUStats.CPUUsage.TotalUsage, stats.CPUStats.SystemUsage, int(stats.CPUStats.OnlineCPUs))
-		rxRate, txRate := calculateNetRate(ctr.ID, now, sumNetRxRawDocker(stats), sumNetTxRawDocker(stats))
+		rxRate, txRate, windowStart := calculateNetRate(ctr.ID, now, agentutils.Monotonic(), sumNetRxRawDocker(stats), sumNetTxRawDocker(stats))
 
+		rateTS := agentutils.RateTimestamp(windowStart, now)
 		metrics = append(metrics,
-			agentutils.Metric("Container", "Docker", "cpu_percent", cpuPercent, "gauge", "percent", dims, now),
-			agentutils.Metric("Container", "Docker", "net_rx_rate_bytes", rxRate, "gauge", "bytes/s", dims, now),
-			agentutils.Metric("Container", "Docker", "net_tx_rate_bytes", txRate, "gauge", "bytes/s", dims, now),
+			agentutils.Metric("Container", "Docker", "cpu_percent", cpuPercent, "gauge", "percent", dims, rateTS),
+			agentutils.Metric("Container", "Docker", "net_rx_rate_bytes", rxRate, "gauge", "bytes/s", dims, rateTS),
+			agentutils.Metric("Container", "Docker", "net_tx_rate_bytes", txRate, "gauge", "bytes/s", dims, rateTS),
 		)
 	}
 
@@ -153,6 +195,8 @@ func ExtractAllDockerMetrics(stats types.StatsJSON, dims map[string]string, ts t
 	cpu := stats.CPUStats
 	metrics = append(metrics,
 		agentutils.Metric("Container", "Docker", "cpu_total_usage", float64(cpu.CPUUsage.TotalUsage), "counter", "nanoseconds", dims, ts),
+		agentutils.Metric("Container", "Docker", "cpu_kernelmode", float64(cpu.CPUUsage.UsageInKernelmode), "counter", "nanoseconds", dims, ts),
+		agentutils.Metric("Container", "Docker", "cpu_usermode", float64(cpu.CPUUsage.UsageInUsermode), "counter", "nanoseconds", dims, ts),
 		agentutils.Metric("Container", "Docker", "cpu_system_usage", float64(cpu.SystemUsage), "counter", "nanoseconds", dims, ts),
 		agentutils.Metric("Container", "Docker", "cpu_online_cpus", float64(cpu.OnlineCPUs), "gauge", "count", dims, ts),
 		agentutils.Metric("Container", "Docker", "cpu_throttle_periods", float64(cpu.ThrottlingData.Periods), "counter", "count", dims, ts),
@@ -172,6 +216,7 @@ func ExtractAllDockerMetrics(stats types.StatsJSON, dims map[string]string, ts t
 		agentutils.Metric("Container", "Docker", "mem_usage_bytes", float64(mem.Usage), "gauge", "bytes", dims, ts),
 		agentutils.Metric("Container", "Docker", "mem_limit_bytes", float64(mem.Limit), "gauge", "bytes", dims, ts),
 		agentutils.Metric("Container", "Docker", "mem_max_usage_bytes", float64(mem.MaxUsage), "gauge", "bytes", dims, ts),
+		agentutils.Metric("Container", "Docker", "mem_working_set_bytes", float64(calculateWorkingSet(mem.Usage, mem.Stats)), "gauge", "bytes", dims, ts),
 	)
 	for k, v := range mem.Stats {
 		metrics = append(metrics,
@@ -216,3 +261,19 @@ func ExtractAllDockerMetrics(stats types.StatsJSON, dims map[string]string, ts t
 
 	return metrics
 }
+
+// formatDockerPorts formats a Docker container's published ports the same
+// way formatPorts does for Podman, e.g. "8080:80/tcp,443/tcp". Docker lists a
+// port published on both IPv4 and IPv6 twice; it is reported once.
+func formatDockerPorts(ports []types.Port) string {
+	seen := make(map[PortMapping]bool, len(ports))
+	mapped := make([]PortMapping, 0, len(ports))
+	for _, p := range ports {
+		m := PortMapping{PrivatePort: int(p.PrivatePort), PublicPort: int(p.PublicPort), Type: p.Type}
+		if !seen[m] {
+			seen[m] = true
+			mapped = append(mapped, m)
+		}
+	}
+	return formatPorts(mapped)
+}
@@ -31,6 +31,7 @@ import (
 	"strings"
 	"time"
 
+	agentutils "github.com/aaronlmathis/gosight-agent/internal/utils"
 	"github.com/docker/docker/api/types"
 )
 
@@ -119,14 +120,15 @@ var prevStats = map[string]struct {
 	NetRx     uint64
 	NetTx     uint64
 	Timestamp time.Time
+	Mono      time.Duration // monotonic reading taken with Timestamp
 }{}
 
 // calculateCPUPercent calculates the CPU percentage for a container
 // based on the total CPU usage and system CPU usage.
 // It uses the previous CPU usage and system CPU usage to calculate
-// the delta and then computes the percentage.
+// the delta and then computes the percentage. The network counters and
+// sample time are left to calculateNetRate, which runs after it.
 func calculateCPUPercent(containerID string, totalUsage, systemUsage uint64, onlineCPUs int) float64 {
-	now := time.Now()
 	prev, ok := prevStats[containerID]
 
 	var percent float64
@@ -138,19 +140,9 @@ func calculateCPUPercent(containerID string, totalUsage, systemUsage uint64, onl
 		}
 	}
 
-	prevStats[containerID] = struct {
-		CPUUsage  uint64
-		SystemCPU uint64
-		NetRx     uint64
-		NetTx     uint64
-		Timestamp time.Time
-	}{
-		CPUUsage:  totalUsage,
-		SystemCPU: systemUsage,
-		NetRx:     0,
-		NetTx:     0,
-		Timestamp: now,
-	}
+	prev.CPUUsage = totalUsage
+	prev.SystemCPU = systemUsage
+	prevStats[containerID] = prev
 
 	return percent
 }
@@ -158,35 +150,29 @@ func calculateCPUPercent(containerID string, totalUsage, systemUsage uint64, onl
 // calculateNetRate calculates the network rate for a container
 // based on the received and transmitted bytes.
 // It uses the previous received and transmitted bytes to calculate
-// the delta and then computes the rate in bytes per second.
-func calculateNetRate(containerID string, now time.Time, rx, tx uint64) (float64, float64) {
-	prev, ok := prevStats[containerID]
-	if !ok || prev.Timestamp.IsZero() {
-		return 0, 0
-	}
-	seconds := now.Sub(prev.Timestamp).Seconds()
-	if seconds <= 0 {
-		return 0, 0
+// the delta and then computes the rate in bytes per second. The interval is
+// measured on the monotonic clock (mono, from agentutils.Monotonic), so a wall
+// clock step between samples does not corrupt it. It also returns the time of
+// the previous sample, i.e. the start of the window the rates (and the CPU
+// percent) cover, which is zero on the first sample.
+func calculateNetRate(containerID string, now time.Time, mono time.Duration, rx, tx uint64) (float64, float64, time.Time) {
+	prev := prevStats[containerID]
+	start := prev.Timestamp
+
+	var rxRate, txRate float64
+	if seconds, ok := agentutils.RateInterval(start, now, prev.Mono, mono); ok {
+		rxRate = float64(rx-prev.NetRx) / seconds
+		txRate = float64(tx-prev.NetTx) / seconds
 	}
-	rxRate := float64(rx-prev.NetRx) / seconds
-	txRate := float64(tx-prev.NetTx) / seconds
 
 	// update previous values
-	prevStats[containerID] = struct {
-		CPUUsage  uint64
-		SystemCPU uint64
-		NetRx     uint64
-		NetTx     uint64
-		Timestamp time.Time
-	}{
-		CPUUsage:  prev.CPUUsage,
-		SystemCPU: prev.SystemCPU,
-		NetRx:     rx,
-		NetTx:     tx,
-		Timestamp: now,
-	}
-
-	return rxRate, txRate
+	prev.NetRx = rx
+	prev.NetTx = tx
+	prev.Timestamp = now
+	prev.Mono = mono
+	prevStats[containerID] = prev
+
+	return rxRate, txRate, start
 }
 
 // sumNetRxRaw sums the received bytes from all network interfaces
@@ -250,3 +236,18 @@ func sumNetTxRawDocker(stats types.StatsJSON) uint64 {
 	}
 	return total
 }
+
+// calculateWorkingSet returns the container memory working set: usage minus
+// inactive page cache. This is the figure cgroup OOM decisions and
+// `kubectl top` use. cgroup v1 reports the cache as "total_inactive_file",
+// cgroup v2 as "inactive_file".
+func calculateWorkingSet(usage uint64, stats map[string]uint64) uint64 {
+	inactive, ok := stats["total_inactive_file"]
+	if !ok {
+		inactive = stats["inactive_file"]
+	}
+	if inactive > usage {
+		return 0
+	}
+	return usage - inactive
+}
@@ -0,0 +1,59 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+package container
+
+import (
+	"testing"
+	"time"
+
+	agentutils "github.com/aaronlmathis/gosight-agent/internal/utils"
+)
+
+func TestNetRateSurvivesBackwardClockStep(t *testing.T) {
+	defer agentutils.SetClockStep("")
+
+	wall := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	mono := time.Hour
+
+	calculateNetRate("step", wall, mono, 1000, 0)
+
+	// 10s pass, but NTP moved the wall clock back 30s meanwhile
+	rx, _, start := calculateNetRate("step", wall.Add(-20*time.Second), mono+10*time.Second, 2000, 0)
+	if !start.Equal(wall) {
+		t.Errorf("expected window start %v, got %v", wall, start)
+	}
+	if rx != 100 {
+		t.Errorf("expected 100 B/s over the monotonic interval, got %v", rx)
+	}
+
+	if err := agentutils.SetClockStep(agentutils.ClockStepReset); err != nil {
+		t.Fatal(err)
+	}
+	rx, _, _ = calculateNetRate("step", wall.Add(-60*time.Second), mono+20*time.Second, 3000, 0)
+	if rx != 0 {
+		t.Errorf("expected rate spanning the step to be dropped in reset mode, got %v", rx)
+	}
+	rx, _, _ = calculateNetRate("step", wall.Add(-50*time.Second), mono+30*time.Second, 4000, 0)
+	if rx != 100 {
+		t.Errorf("expected rates to resume after the step, got %v", rx)
+	}
+}
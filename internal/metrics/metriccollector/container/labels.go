@@ -0,0 +1,73 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+// agent/internal/metrics/metriccollector/container/labels.go
+
+package container
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/aaronlmathis/gosight-shared/utils"
+)
+
+// labelMetaFields are the Meta fields a container label can be promoted to.
+// A promoted label is sent as a dimension named after the field, which the
+// metric runner copies into the container's Meta (see metricrunner.dispatch).
+var labelMetaFields = map[string]bool{
+	"service":       true,
+	"application":   true,
+	"environment":   true,
+	"version":       true,
+	"deployment_id": true,
+}
+
+// validLabelFields returns the label -> field mapping with unknown fields
+// removed, logging a warning for each.
+func validLabelFields(fields map[string]string) map[string]string {
+	valid := make(map[string]string, len(fields))
+	for label, field := range fields {
+		field = strings.ToLower(field)
+		if !labelMetaFields[field] {
+			known := make([]string, 0, len(labelMetaFields))
+			for f := range labelMetaFields {
+				known = append(known, f)
+			}
+			sort.Strings(known)
+			utils.Warn("Ignoring container label mapping %s -> %s: field must be one of %s", label, field, strings.Join(known, ", "))
+			continue
+		}
+		valid[label] = field
+	}
+	return valid
+}
+
+// addLabelDims adds a container's labels to dims: labels mapped in fields
+// become the corresponding Meta field dimension, all others "label.<key>".
+func addLabelDims(dims, labels, fields map[string]string) {
+	for k, v := range labels {
+		if field, ok := fields[k]; ok {
+			dims[field] = v
+			continue
+		}
+		dims["label."+k] = v
+	}
+}
@@ -27,6 +27,7 @@ package container
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
@@ -36,12 +37,21 @@ import (
 
 	agentutils "github.com/aaronlmathis/gosight-agent/internal/utils"
 	"github.com/aaronlmathis/gosight-shared/model"
+	"github.com/aaronlmathis/gosight-shared/utils"
 )
 
 // PodmanCollector collects metrics from Podman containers.
 // It uses the Podman API to fetch container stats and metadata.
 type PodmanCollector struct {
 	SocketPath string
+
+	// APIVersion is the libpod API version used in request paths (e.g.
+	// v4.0.0). Empty uses defaultPodmanListVersion/defaultPodmanInspectVersion.
+	// If the engine does not serve it, the version is probed from /version.
+	APIVersion string
+
+	// LabelFields promotes container labels to Meta fields (label key -> field).
+	LabelFields map[string]string
 }
 
 // PodmanContainer represents a Podman container.
@@ -99,8 +109,9 @@ type PodmanStats struct {
 		OnlineCPUs     int    `json:"online_cpus"`
 	} `json:"cpu_stats"`
 	MemoryStats struct {
-		Usage uint64 `json:"usage_bytes"`
-		Limit uint64 `json:"limit_bytes"`
+		Usage uint64            `json:"usage_bytes"`
+		Limit uint64            `json:"limit_bytes"`
+		Stats map[string]uint64 `json:"stats"`
 	} `json:"memory_stats"`
 	Networks map[string]struct {
 		RxBytes uint64 `json:"rx_bytes"`
@@ -108,6 +119,17 @@ type PodmanStats struct {
 	} `json:"networks"`
 }
 
+// defaultPodmanListVersion and defaultPodmanInspectVersion are the libpod
+// API versions used when none is configured.
+const (
+	defaultPodmanListVersion    = "v4.0.0"
+	defaultPodmanInspectVersion = "v4.5.0"
+)
+
+// errPodmanNotFound is returned by fetchContainers and fetchGeneric when the
+// engine answers 404, e.g. for an API version it does not serve.
+var errPodmanNotFound = errors.New("podman API: not found")
+
 // PortMapping represents a port mapping for a Podman container.
 // It contains the private port, public port, and type of mapping.
 // The PrivatePort field contains the private port number.
@@ -128,10 +150,14 @@ func NewPodmanCollector() *PodmanCollector {
 // NewPodmanCollectorWithSocket creates a new PodmanCollector with a custom socket path.
 // This is useful for testing or if the Podman socket is located in a different path.
 // The socket path should be the full path to the Podman socket file.
-func NewPodmanCollectorWithSocket(path string) *PodmanCollector {
-	return &PodmanCollector{SocketPath: path}
+// labelFields promotes container labels to Meta fields, as for Docker.
+func NewPodmanCollectorWithSocket(path string, labelFields map[string]string) *PodmanCollector {
+	return &PodmanCollector{SocketPath: path, LabelFields: validLabelFields(labelFields)}
 }
 
+// Stateful marks the collector as computing rates from the previous call's container stats.
+func (c *PodmanCollector) Stateful() {}
+
 // Name returns the name of the collector.
 // This is used for logging and debugging purposes.
 // It returns "podman" for the PodmanCollector.
@@ -144,7 +170,18 @@ func (c *PodmanCollector) Name() string {
 // If an error occurs during the collection process, it returns the error.
 // The metrics include CPU usage, memory usage, network statistics, and container state.
 func (c *PodmanCollector) Collect(_ context.Context) ([]model.Metric, error) {
-	containers, err := fetchContainers[PodmanContainer](c.SocketPath, "/v4.0.0/containers/json?all=true")
+	listVersion, inspectVersion := c.versions()
+	containers, err := fetchContainers[PodmanContainer](c.SocketPath, "/"+listVersion+"/containers/json?all=true")
+	if errors.Is(err, errPodmanNotFound) {
+		probed, perr := probePodmanAPIVersion(c.SocketPath)
+		if perr != nil {
+			return nil, fmt.Errorf("podman API %s not served and version probe failed: %w", listVersion, perr)
+		}
+		utils.Warn("Podman API %s not served by the engine; using %s", listVersion, probed)
+		c.APIVersion = probed
+		listVersion, inspectVersion = probed, probed
+		containers, err = fetchContainers[PodmanContainer](c.SocketPath, "/"+listVersion+"/containers/json?all=true")
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -153,11 +190,11 @@ func (c *PodmanCollector) Collect(_ context.Context) ([]model.Metric, error) {
 	var metrics []model.Metric
 
 	for _, ctr := range containers {
-		stats, err := fetchStats(c.SocketPath, ctr.ID)
+		stats, err := fetchStats(c.SocketPath, listVersion, ctr.ID)
 		if err != nil {
 			continue
 		}
-		inspect, err := fetchInspect(c.SocketPath, ctr.ID)
+		inspect, err := fetchInspect(c.SocketPath, inspectVersion, ctr.ID)
 		if err == nil && inspect.State.StartedAt != "" {
 			t, err := time.Parse(time.RFC3339Nano, inspect.State.StartedAt)
 			if err == nil {
@@ -188,9 +225,7 @@ func (c *PodmanCollector) Collect(_ context.Context) ([]model.Metric, error) {
 		if parts := strings.Split(ctr.Image, ":"); len(parts) == 2 {
 			dims["container_version"] = parts[1]
 		}
-		for k, v := range ctr.Labels {
-			dims["label."+k] = v
-		}
+		addLabelDims(dims, ctr.Labels, c.LabelFields)
 		if ports := formatPorts(ctr.Ports); ports != "" {
 			dims["ports"] = ports
 		}
@@ -204,13 +239,13 @@ func (c *PodmanCollector) Collect(_ context.Context) ([]model.Metric, error) {
 
 		// Calculate CPU percent and network rates
 		cpuPercent := calculateCPUPercent(ctr.ID, stats.CPUStats.CPUUsage.TotalUsage, stats.CPUStats.SystemCPUUsage, stats.CPUStats.OnlineCPUs)
-		rxRate, txRate := calculateNetRate(ctr.ID, now, sumNetRxRaw(stats), sumNetTxRaw(stats))
+		rxRate, txRate, windowStart := calculateNetRate(ctr.ID, now, agentutils.Monotonic(), sumNetRxRaw(stats), sumNetTxRaw(stats))
 
-		now := time.Now()
+		rateTS := agentutils.RateTimestamp(windowStart, now)
 		metrics = append(metrics,
-			agentutils.Metric("Container", "Podman", "cpu_percent", cpuPercent, "gauge", "percent", dims, now),
-			agentutils.Metric("Container", "Podman", "net_rx_rate_bytes", rxRate, "gauge", "bytes/s", dims, now),
-			agentutils.Metric("Container", "Podman", "net_tx_rate_bytes", txRate, "gauge", "bytes/s", dims, now),
+			agentutils.Metric("Container", "Podman", "cpu_percent", cpuPercent, "gauge", "percent", dims, rateTS),
+			agentutils.Metric("Container", "Podman", "net_rx_rate_bytes", rxRate, "gauge", "bytes/s", dims, rateTS),
+			agentutils.Metric("Container", "Podman", "net_tx_rate_bytes", txRate, "gauge", "bytes/s", dims, rateTS),
 		)
 	}
 
@@ -237,6 +272,7 @@ func extractAllPodmanMetrics(stats *PodmanStats, dims map[string]string, ts time
 		agentutils.Metric("Container", "Podman", "mem_usage_bytes", float64(stats.MemoryStats.Usage), "gauge", "bytes", dims, ts),
 		agentutils.Metric("Container", "Podman", "mem_limit_bytes", float64(stats.MemoryStats.Limit), "gauge", "bytes", dims, ts),
 		agentutils.Metric("Container", "Podman", "mem_max_usage_bytes", 0, "gauge", "bytes", dims, ts),
+		agentutils.Metric("Container", "Podman", "mem_working_set_bytes", float64(calculateWorkingSet(stats.MemoryStats.Usage, stats.MemoryStats.Stats)), "gauge", "bytes", dims, ts),
 	)
 
 	var rx, tx uint64
@@ -272,6 +308,30 @@ func extractAllPodmanMetrics(stats *PodmanStats, dims map[string]string, ts time
 	return metrics
 }
 
+// versions returns the API versions for the list/stats and inspect requests.
+func (c *PodmanCollector) versions() (list, inspect string) {
+	if c.APIVersion == "" {
+		return defaultPodmanListVersion, defaultPodmanInspectVersion
+	}
+	v := "v" + strings.TrimPrefix(c.APIVersion, "v")
+	return v, v
+}
+
+// probePodmanAPIVersion asks the engine for its version via the unversioned
+// /version endpoint and returns it as a libpod API version (e.g. v4.9.3).
+func probePodmanAPIVersion(socketPath string) (string, error) {
+	info, err := fetchGeneric[struct {
+		Version string `json:"Version"`
+	}](socketPath, "/version")
+	if err != nil {
+		return "", err
+	}
+	if info.Version == "" {
+		return "", errors.New("engine did not report a version")
+	}
+	return "v" + strings.TrimPrefix(info.Version, "v"), nil
+}
+
 // fetchContainers fetches all containers from the Podman API.
 // It returns a slice of PodmanContainer structs containing the container metadata.
 func fetchContainers[T any](socketPath, endpoint string) ([]T, error) {
@@ -282,6 +342,9 @@ func fetchContainers[T any](socketPath, endpoint string) ([]T, error) {
 		return nil, err
 	}
 	defer resp.Body.Close()
+	if err := checkPodmanStatus(resp, endpoint); err != nil {
+		return nil, err
+	}
 
 	var out []T
 	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
@@ -292,15 +355,15 @@ func fetchContainers[T any](socketPath, endpoint string) ([]T, error) {
 
 // fetchStats fetches the stats for a specific container from the Podman API.
 // It returns a PodmanStats struct containing the container stats.
-func fetchStats(socketPath, containerID string) (*PodmanStats, error) {
-	return fetchGeneric[PodmanStats](socketPath, fmt.Sprintf("/v4.0.0/containers/%s/stats?stream=false", containerID))
+func fetchStats(socketPath, version, containerID string) (*PodmanStats, error) {
+	return fetchGeneric[PodmanStats](socketPath, fmt.Sprintf("/%s/containers/%s/stats?stream=false", version, containerID))
 }
 
 // fetchInspect fetches the inspect data for a specific container from the Podman API.
 // It returns a PodmanInspect struct containing the container inspect data.
 // The inspect data includes the container's state, labels, and other metadata.
-func fetchInspect(socketPath, containerID string) (*PodmanInspect, error) {
-	return fetchGeneric[PodmanInspect](socketPath, fmt.Sprintf("/v4.5.0/containers/%s/json", containerID))
+func fetchInspect(socketPath, version, containerID string) (*PodmanInspect, error) {
+	return fetchGeneric[PodmanInspect](socketPath, fmt.Sprintf("/%s/containers/%s/json", version, containerID))
 }
 
 // fetchGeneric fetches generic data from the Podman API.
@@ -314,6 +377,9 @@ func fetchGeneric[T any](socketPath, endpoint string) (*T, error) {
 		return nil, err
 	}
 	defer resp.Body.Close()
+	if err := checkPodmanStatus(resp, endpoint); err != nil {
+		return nil, err
+	}
 
 	var result T
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
@@ -322,6 +388,18 @@ func fetchGeneric[T any](socketPath, endpoint string) (*T, error) {
 	return &result, nil
 }
 
+// checkPodmanStatus returns errPodmanNotFound for a 404 and an error for any
+// other non-2xx response, instead of decoding the error body as data.
+func checkPodmanStatus(resp *http.Response, endpoint string) error {
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("%w: %s", errPodmanNotFound, endpoint)
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return fmt.Errorf("podman API %s: %s", endpoint, resp.Status)
+	}
+	return nil
+}
+
 // unixTransport creates a new HTTP transport that uses a Unix socket.
 // It takes a socket path as an argument and returns a pointer to http.Transport.
 // This is used to communicate with the Podman API over a Unix socket.
@@ -0,0 +1,65 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+package container
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+)
+
+func TestPodmanAPIVersionFallback(t *testing.T) {
+	sock := filepath.Join(t.TempDir(), "podman.sock")
+	ln, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	var listed []string
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/version":
+			w.Write([]byte(`{"Version":"5.2.1","ApiVersion":"1.41"}`))
+		case "/v5.2.1/containers/json":
+			listed = append(listed, r.URL.Path)
+			w.Write([]byte(`[]`))
+		default:
+			listed = append(listed, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	})}
+	go srv.Serve(ln)
+	defer srv.Close()
+
+	c := NewPodmanCollectorWithSocket(sock, nil)
+	c.APIVersion = "9.9.9"
+	if _, err := c.Collect(context.Background()); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if c.APIVersion != "v5.2.1" {
+		t.Errorf("expected probed version v5.2.1, got %q", c.APIVersion)
+	}
+	if len(listed) != 2 || listed[0] != "/v9.9.9/containers/json" {
+		t.Errorf("unexpected requests: %v", listed)
+	}
+}
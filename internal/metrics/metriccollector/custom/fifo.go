@@ -0,0 +1,205 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/collector/custom/fifo.go
+// Package custom provides collectors for user-supplied metrics.
+// fifo.go reads newline-delimited metric records from a named pipe so shell
+// scripts and cron jobs can publish metrics with a simple echo.
+
+package custom
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aaronlmathis/gosight-shared/model"
+	"github.com/aaronlmathis/gosight-shared/utils"
+)
+
+// maxPendingFIFOMetrics bounds how many records are buffered between collections.
+const maxPendingFIFOMetrics = 10000
+
+// FIFOCollector reads records of the form
+//
+//	namespace[.subnamespace].name value [key=value ...]
+//
+// from a named pipe and returns everything received since the last Collect.
+type FIFOCollector struct {
+	path    string
+	once    sync.Once
+	mu      sync.Mutex
+	cancel  context.CancelFunc
+	file    *os.File
+	closed  bool
+	pending []model.Metric
+	dropped int
+}
+
+// NewFIFOCollector creates a collector reading from the named pipe at path.
+// The pipe is created on first use if it does not exist.
+func NewFIFOCollector(path string) *FIFOCollector {
+	return &FIFOCollector{path: path}
+}
+
+// Buffered marks the collector as returning pushed data only once.
+func (c *FIFOCollector) Buffered() {}
+
+// Name returns the name of the collector.
+// This is used to identify the collector in logs and metrics.
+func (c *FIFOCollector) Name() string {
+	return "fifo"
+}
+
+// Collect starts the pipe reader on first call and returns the metrics
+// received since the previous call.
+func (c *FIFOCollector) Collect(ctx context.Context) ([]model.Metric, error) {
+	c.once.Do(func() {
+		runCtx, cancel := context.WithCancel(ctx)
+		c.mu.Lock()
+		c.cancel = cancel
+		c.mu.Unlock()
+		go c.run(runCtx)
+	})
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.dropped > 0 {
+		utils.Warn("FIFO collector dropped %d records (buffer full)", c.dropped)
+		c.dropped = 0
+	}
+	out := c.pending
+	c.pending = nil
+	return out, nil
+}
+
+// Close stops the reader and closes the pipe. The pipe itself is left in
+// place for writers.
+func (c *FIFOCollector) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	if c.cancel != nil {
+		c.cancel()
+	}
+	if c.file != nil {
+		_ = c.file.Close()
+		c.file = nil
+	}
+}
+
+// run reads the pipe until ctx is canceled or the collector is closed.
+func (c *FIFOCollector) run(ctx context.Context) {
+	f, err := openFIFO(c.path)
+	if err != nil {
+		utils.Error("FIFO collector disabled: %v", err)
+		return
+	}
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		_ = f.Close()
+		return
+	}
+	c.file = f
+	c.mu.Unlock()
+	go func() {
+		<-ctx.Done()
+		c.Close()
+	}()
+
+	utils.Info("FIFO collector reading metrics from %s", c.path)
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		m, err := parseMetricLine(line, time.Now())
+		if err != nil {
+			utils.Debug("FIFO collector: skipping line %q: %v", line, err)
+			continue
+		}
+
+		c.mu.Lock()
+		if len(c.pending) < maxPendingFIFOMetrics {
+			c.pending = append(c.pending, m)
+		} else {
+			c.dropped++
+		}
+		c.mu.Unlock()
+	}
+	if err := scanner.Err(); err != nil && ctx.Err() == nil && !errors.Is(err, os.ErrClosed) {
+		utils.Error("FIFO collector read error: %v", err)
+	}
+}
+
+// parseMetricLine parses "namespace[.subnamespace].name value [key=value ...]".
+// A name with more than three dot-separated parts keeps the remainder in Name.
+func parseMetricLine(line string, ts time.Time) (model.Metric, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return model.Metric{}, fmt.Errorf("expected \"name value [tags...]\"")
+	}
+
+	parts := strings.SplitN(fields[0], ".", 3)
+	m := model.Metric{
+		Timestamp: ts,
+		Type:      "gauge",
+	}
+	switch len(parts) {
+	case 2:
+		m.Namespace, m.Name = parts[0], parts[1]
+	case 3:
+		m.Namespace, m.SubNamespace, m.Name = parts[0], parts[1], parts[2]
+	default:
+		return model.Metric{}, fmt.Errorf("metric name %q must include a namespace", fields[0])
+	}
+	if m.Namespace == "" || m.Name == "" {
+		return model.Metric{}, fmt.Errorf("invalid metric name %q", fields[0])
+	}
+
+	v, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil {
+		return model.Metric{}, fmt.Errorf("invalid value %q", fields[1])
+	}
+	m.Value = v
+
+	for _, tag := range fields[2:] {
+		k, val, ok := strings.Cut(tag, "=")
+		if !ok || k == "" {
+			continue
+		}
+		if m.Dimensions == nil {
+			m.Dimensions = make(map[string]string)
+		}
+		m.Dimensions[k] = val
+	}
+	return m, nil
+}
@@ -0,0 +1,54 @@
+//go:build !windows
+
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/collector/custom/fifo_unix.go
+// fifo_unix.go creates and opens the named pipe on Unix-like systems.
+
+package custom
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// openFIFO opens (creating if needed) the named pipe at path. The pipe is opened
+// read-write so the reader never sees EOF when individual writers exit.
+func openFIFO(path string) (*os.File, error) {
+	if path == "" {
+		return nil, fmt.Errorf("no fifo path configured")
+	}
+
+	fi, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		if err := syscall.Mkfifo(path, 0620); err != nil {
+			return nil, fmt.Errorf("failed to create fifo %s: %w", path, err)
+		}
+	} else if err != nil {
+		return nil, err
+	} else if fi.Mode()&os.ModeNamedPipe == 0 {
+		return nil, fmt.Errorf("%s exists and is not a named pipe", path)
+	}
+
+	return os.OpenFile(path, os.O_RDWR, 0)
+}
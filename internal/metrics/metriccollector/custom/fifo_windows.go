@@ -0,0 +1,37 @@
+//go:build windows
+
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/collector/custom/fifo_windows.go
+// fifo_windows.go - named pipes in the Unix sense are not supported on Windows.
+
+package custom
+
+import (
+	"fmt"
+	"os"
+)
+
+// openFIFO always fails on Windows.
+func openFIFO(path string) (*os.File, error) {
+	return nil, fmt.Errorf("fifo collector is not supported on windows")
+}
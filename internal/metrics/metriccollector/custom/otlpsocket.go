@@ -0,0 +1,246 @@
+//go:build !windows
+
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/collector/custom/otlpsocket.go
+// otlpsocket.go serves the OTLP metrics and trace gRPC services on a unix
+// socket so co-located applications can push OTLP to the agent without a
+// TCP stack. Received metrics are enriched with the agent's metadata and
+// forwarded with the next collection cycle; spans go to the span sink (see
+// SetSpanSink), the trace pipeline's queue.
+
+package custom
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/aaronlmathis/gosight-agent/internal/otelconvert"
+	"github.com/aaronlmathis/gosight-shared/model"
+	"github.com/aaronlmathis/gosight-shared/utils"
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	_ "google.golang.org/grpc/encoding/gzip" // accept gzip-compressed exports
+	"google.golang.org/grpc/status"
+)
+
+// maxPendingOTLPMetrics bounds how many data points are buffered between collections.
+const maxPendingOTLPMetrics = 50000
+
+// OTLPSocketCollector receives OTLP metric exports on a unix socket and
+// returns the data points received since the last Collect.
+type OTLPSocketCollector struct {
+	colmetricpb.UnimplementedMetricsServiceServer
+
+	path        string
+	once        sync.Once
+	mu          sync.Mutex
+	cancel      context.CancelFunc
+	srv         *grpc.Server
+	closed      bool
+	pending     []model.Metric
+	dropped     int
+	unsupported int
+}
+
+// NewOTLPSocketCollector creates a collector listening on the unix socket at
+// path. A stale socket left by a previous run is replaced.
+func NewOTLPSocketCollector(path string) *OTLPSocketCollector {
+	return &OTLPSocketCollector{path: path}
+}
+
+// Buffered marks the collector as returning pushed data only once.
+func (c *OTLPSocketCollector) Buffered() {}
+
+// Name returns the name of the collector.
+// This is used to identify the collector in logs and metrics.
+func (c *OTLPSocketCollector) Name() string {
+	return "otlp_socket"
+}
+
+// Collect starts the receiver on first call and returns the metrics received
+// since the previous call.
+func (c *OTLPSocketCollector) Collect(ctx context.Context) ([]model.Metric, error) {
+	c.once.Do(func() {
+		runCtx, cancel := context.WithCancel(ctx)
+		c.mu.Lock()
+		c.cancel = cancel
+		c.mu.Unlock()
+		go c.run(runCtx)
+	})
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.dropped > 0 {
+		utils.Warn("OTLP socket receiver dropped %d data points (buffer full)", c.dropped)
+		c.dropped = 0
+	}
+	if c.unsupported > 0 {
+		utils.Debug("OTLP socket receiver skipped %d metrics of unsupported types", c.unsupported)
+		c.unsupported = 0
+	}
+	out := c.pending
+	c.pending = nil
+	return out, nil
+}
+
+// Close stops the receiver, removes its socket and discards buffered data
+// points so producers are no longer acknowledged.
+func (c *OTLPSocketCollector) Close() {
+	c.mu.Lock()
+	c.closed = true
+	if c.cancel != nil {
+		c.cancel()
+	}
+	srv := c.srv
+	c.srv = nil
+	c.pending = nil
+	c.mu.Unlock()
+
+	if srv != nil {
+		srv.Stop()
+		_ = os.Remove(c.path)
+	}
+}
+
+// run serves the OTLP metrics service until ctx is canceled or the collector
+// is closed.
+func (c *OTLPSocketCollector) run(ctx context.Context) {
+	l, err := listenUnix(c.path)
+	if err != nil {
+		utils.Error("OTLP socket receiver disabled: %v", err)
+		return
+	}
+
+	srv := grpc.NewServer(grpc.MaxRecvMsgSize(32 * 1024 * 1024))
+	colmetricpb.RegisterMetricsServiceServer(srv, c)
+	coltracepb.RegisterTraceServiceServer(srv, otlpTraceService{})
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		_ = l.Close()
+		_ = os.Remove(c.path)
+		return
+	}
+	c.srv = srv
+	c.mu.Unlock()
+	go func() {
+		<-ctx.Done()
+		c.Close()
+	}()
+
+	utils.Info("OTLP socket receiver listening on %s", c.path)
+	if err := srv.Serve(l); err != nil && ctx.Err() == nil {
+		utils.Error("OTLP socket receiver stopped: %v", err)
+	}
+}
+
+// Export implements the OTLP MetricsService. Data points that do not fit in
+// the buffer are reported back as rejected via partial success.
+func (c *OTLPSocketCollector) Export(_ context.Context, req *colmetricpb.ExportMetricsServiceRequest) (*colmetricpb.ExportMetricsServiceResponse, error) {
+	metrics, unsupported := otelconvert.ConvertFromOTLPMetrics(req, time.Now())
+
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil, status.Error(codes.Unavailable, "otlp socket receiver closed")
+	}
+	room := maxPendingOTLPMetrics - len(c.pending)
+	if room < 0 {
+		room = 0
+	}
+	rejected := 0
+	if len(metrics) > room {
+		rejected = len(metrics) - room
+		metrics = metrics[:room]
+	}
+	c.pending = append(c.pending, metrics...)
+	c.dropped += rejected
+	c.unsupported += unsupported
+	c.mu.Unlock()
+
+	resp := &colmetricpb.ExportMetricsServiceResponse{}
+	if rejected > 0 {
+		resp.PartialSuccess = &colmetricpb.ExportMetricsPartialSuccess{
+			RejectedDataPoints: int64(rejected),
+			ErrorMessage:       "agent buffer full",
+		}
+	}
+	return resp, nil
+}
+
+// otlpTraceService serves the OTLP TraceService on the collector's socket.
+type otlpTraceService struct {
+	coltracepb.UnimplementedTraceServiceServer
+}
+
+// Export implements the OTLP TraceService by passing the spans to the span
+// sink. Spans the sink cannot queue are reported back as rejected.
+func (otlpTraceService) Export(_ context.Context, req *coltracepb.ExportTraceServiceRequest) (*coltracepb.ExportTraceServiceResponse, error) {
+	sink := currentSpanSink()
+	if sink == nil {
+		return nil, status.Error(codes.Unavailable, "trace export is not enabled in the agent")
+	}
+
+	spans := otelconvert.ConvertFromOTLPTraces(req)
+	resp := &coltracepb.ExportTraceServiceResponse{}
+	if !sink(spans) {
+		resp.PartialSuccess = &coltracepb.ExportTracePartialSuccess{
+			RejectedSpans: int64(len(spans)),
+			ErrorMessage:  "agent trace queue full",
+		}
+	}
+	return resp, nil
+}
+
+// listenUnix listens on the unix socket at path, removing a stale socket
+// first. Other file types at path are left alone. The socket is made
+// group-writable so local producers in the agent's group can connect.
+func listenUnix(path string) (net.Listener, error) {
+	if path == "" {
+		return nil, fmt.Errorf("no otlp socket path configured")
+	}
+	if fi, err := os.Lstat(path); err == nil {
+		if fi.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s exists and is not a socket", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("failed to remove stale socket %s: %w", path, err)
+		}
+	}
+
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0660); err != nil {
+		utils.Warn("Failed to set permissions on %s: %v", path, err)
+	}
+	return l, nil
+}
@@ -0,0 +1,70 @@
+//go:build !windows
+
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+
+package custom
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aaronlmathis/gosight-shared/model"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestOTLPTraceServiceExport(t *testing.T) {
+	defer SetSpanSink(nil)
+	req := &coltracepb.ExportTraceServiceRequest{ResourceSpans: []*tracepb.ResourceSpans{{
+		ScopeSpans: []*tracepb.ScopeSpans{{Spans: []*tracepb.Span{
+			{TraceId: make([]byte, 16), SpanId: make([]byte, 8), Name: "a"},
+			{TraceId: make([]byte, 16), SpanId: make([]byte, 8), Name: "b"},
+		}}},
+	}}}
+	svc := otlpTraceService{}
+
+	if _, err := svc.Export(context.Background(), req); status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected Unavailable without a span sink, got %v", err)
+	}
+
+	var got []model.TraceSpan
+	SetSpanSink(func(spans []model.TraceSpan) bool {
+		got = append(got, spans...)
+		return true
+	})
+	resp, err := svc.Export(context.Background(), req)
+	if err != nil || resp.PartialSuccess != nil {
+		t.Fatalf("Export: %v %v", resp, err)
+	}
+	if len(got) != 2 || got[0].Name != "a" {
+		t.Errorf("expected both spans to reach the sink, got %+v", got)
+	}
+
+	SetSpanSink(func([]model.TraceSpan) bool { return false })
+	resp, err = svc.Export(context.Background(), req)
+	if err != nil || resp.PartialSuccess.GetRejectedSpans() != 2 {
+		t.Errorf("expected the spans to be reported as rejected, got %v %v", resp, err)
+	}
+}
@@ -0,0 +1,61 @@
+//go:build windows
+
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/collector/custom/otlpsocket_windows.go
+// otlpsocket_windows.go - the OTLP unix socket receiver is not supported on Windows.
+
+package custom
+
+import (
+	"context"
+	"sync"
+
+	"github.com/aaronlmathis/gosight-shared/model"
+	"github.com/aaronlmathis/gosight-shared/utils"
+)
+
+// OTLPSocketCollector is a no-op on Windows.
+type OTLPSocketCollector struct {
+	once sync.Once
+}
+
+// NewOTLPSocketCollector returns a collector that reports nothing on Windows.
+func NewOTLPSocketCollector(path string) *OTLPSocketCollector {
+	return &OTLPSocketCollector{}
+}
+
+// Buffered marks the collector as returning pushed data only once.
+func (c *OTLPSocketCollector) Buffered() {}
+
+// Name returns the name of the collector.
+func (c *OTLPSocketCollector) Name() string {
+	return "otlp_socket"
+}
+
+// Collect logs once that the source is unsupported and returns no metrics.
+func (c *OTLPSocketCollector) Collect(_ context.Context) ([]model.Metric, error) {
+	c.once.Do(func() {
+		utils.Warn("otlp_socket collector is not supported on windows")
+	})
+	return nil, nil
+}
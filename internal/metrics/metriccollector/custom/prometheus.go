@@ -0,0 +1,251 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/collector/custom/prometheus.go
+// prometheus.go scrapes Prometheus exporters (node_exporter, cadvisor, ...)
+// so their metrics are shipped without duplicating collectors.
+
+package custom
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aaronlmathis/gosight-agent/internal/config"
+	agentutils "github.com/aaronlmathis/gosight-agent/internal/utils"
+	"github.com/aaronlmathis/gosight-shared/model"
+	"github.com/aaronlmathis/gosight-shared/utils"
+)
+
+const (
+	// defaultScrapeTimeout applies to targets without a timeout.
+	defaultScrapeTimeout = 10 * time.Second
+	// maxScrapeBytes bounds the exposition read from one target.
+	maxScrapeBytes = 64 << 20
+)
+
+// PrometheusCollector scrapes the configured targets. Every sample becomes a
+// metric with its labels as dimensions plus job and instance; each target also
+// reports Prometheus up (1 if the scrape succeeded) and
+// scrape_duration_seconds.
+type PrometheusCollector struct {
+	targets []*scrapeTarget
+}
+
+// scrapeTarget is a target with its HTTP client and scrape state.
+type scrapeTarget struct {
+	cfg      config.ScrapeTarget
+	client   *http.Client
+	job      string
+	instance string
+
+	last time.Time
+	down bool
+}
+
+// NewPrometheusCollector creates a collector for targets, keeping at most
+// maxTargets. Targets whose TLS files can't be loaded are skipped with a
+// warning.
+func NewPrometheusCollector(targets []config.ScrapeTarget, maxTargets int) *PrometheusCollector {
+	c := &PrometheusCollector{}
+	for _, t := range agentutils.ApplyLimit("scrape targets", "max_scrape_targets", targets, maxTargets) {
+		st, err := newScrapeTarget(t)
+		if err != nil {
+			utils.Warn("Skipping Prometheus target %s: %v", t.URL, err)
+			continue
+		}
+		c.targets = append(c.targets, st)
+	}
+	return c
+}
+
+func newScrapeTarget(t config.ScrapeTarget) (*scrapeTarget, error) {
+	u, err := url.Parse(t.URL)
+	if err != nil {
+		return nil, err
+	}
+	if t.Timeout <= 0 {
+		t.Timeout = defaultScrapeTimeout
+	}
+
+	tlsCfg := &tls.Config{InsecureSkipVerify: t.InsecureSkipVerify, MinVersion: tls.VersionTLS12}
+	if t.CAFile != "" {
+		pem, err := os.ReadFile(t.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("failed to parse CA file %s", t.CAFile)
+		}
+		tlsCfg.RootCAs = pool
+	}
+	if t.CertFile != "" && t.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client cert/key: %w", err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+
+	job := t.Job
+	if job == "" {
+		job = u.Host
+	}
+	return &scrapeTarget{
+		cfg: t,
+		client: &http.Client{
+			Timeout:   t.Timeout,
+			Transport: &http.Transport{TLSClientConfig: tlsCfg, Proxy: http.ProxyFromEnvironment},
+		},
+		job:      job,
+		instance: u.Host,
+	}, nil
+}
+
+// Close releases the idle keep-alive connections of every target's transport.
+// The registry calls it when the collector is disabled or replaced on reload.
+func (c *PrometheusCollector) Close() {
+	for _, t := range c.targets {
+		t.client.CloseIdleConnections()
+	}
+}
+
+// Stateful marks the collector as scraping each target at most once per target interval.
+func (c *PrometheusCollector) Stateful() {}
+
+// Name returns the name of the collector.
+// This is used to identify the collector in logs and metrics.
+func (c *PrometheusCollector) Name() string {
+	return "prometheus"
+}
+
+// Collect scrapes every target whose interval has elapsed, concurrently.
+// A target that can't be scraped only reports up=0.
+func (c *PrometheusCollector) Collect(ctx context.Context) ([]model.Metric, error) {
+	now := time.Now()
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		metrics []model.Metric
+	)
+	for _, t := range c.targets {
+		if !t.last.IsZero() && now.Sub(t.last) < t.cfg.Interval {
+			continue
+		}
+		t.last = now
+
+		wg.Add(1)
+		go func(t *scrapeTarget) {
+			defer wg.Done()
+			scraped := t.scrape(ctx, now)
+			mu.Lock()
+			metrics = append(metrics, scraped...)
+			mu.Unlock()
+		}(t)
+	}
+	wg.Wait()
+
+	return metrics, nil
+}
+
+// scrape fetches and converts the target's exposition, followed by its up
+// and scrape_duration_seconds metrics.
+func (t *scrapeTarget) scrape(ctx context.Context, now time.Time) []model.Metric {
+	start := time.Now()
+	samples, types, err := t.fetch(ctx)
+	duration := time.Since(start).Seconds()
+
+	targetDims := map[string]string{"job": t.job, "instance": t.instance}
+	up := 1
+	if err != nil {
+		up = 0
+		if !t.down {
+			utils.Warn("Prometheus target %s is down: %v", t.cfg.URL, err)
+		}
+	} else if t.down {
+		utils.Info("Prometheus target %s is back up", t.cfg.URL)
+	}
+	t.down = err != nil
+
+	metrics := make([]model.Metric, 0, len(samples)+2)
+	for _, s := range samples {
+		dims := s.labels
+		if _, ok := dims["job"]; !ok {
+			dims["job"] = t.job
+		}
+		if _, ok := dims["instance"]; !ok {
+			dims["instance"] = t.instance
+		}
+		typ, family := promSampleType(s.name, types)
+		ns, sub, name := splitPromName(s.name, family)
+		metrics = append(metrics, agentutils.Metric(ns, sub, name, s.value, typ, "", dims, now))
+	}
+	metrics = append(metrics,
+		agentutils.Metric("Prometheus", "", "up", up, "gauge", "bool", targetDims, now),
+		agentutils.Metric("Prometheus", "", "scrape_duration_seconds", duration, "gauge", "seconds", copyDims(targetDims), now),
+	)
+	return metrics
+}
+
+// fetch GETs the target and parses the response.
+func (t *scrapeTarget) fetch(ctx context.Context) ([]promSample, map[string]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.cfg.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, t.cfg.URL, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Accept", "text/plain;version=0.0.4;q=1,*/*;q=0.1")
+	req.Header.Set("X-Prometheus-Scrape-Timeout-Seconds", strconv.FormatFloat(t.cfg.Timeout.Seconds(), 'f', -1, 64))
+	if t.cfg.Username != "" || t.cfg.Password != "" {
+		req.SetBasicAuth(t.cfg.Username, t.cfg.Password)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return parsePromText(io.LimitReader(resp.Body, maxScrapeBytes))
+}
+
+func copyDims(dims map[string]string) map[string]string {
+	out := make(map[string]string, len(dims))
+	for k, v := range dims {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,141 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+package custom
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aaronlmathis/gosight-agent/internal/config"
+	"github.com/aaronlmathis/gosight-shared/model"
+)
+
+// scrapeOnce runs one collection against a single target and returns its
+// samples by name and the target's up value.
+func scrapeOnce(t *testing.T, target config.ScrapeTarget) (map[string]model.Metric, float64) {
+	t.Helper()
+	c := NewPrometheusCollector([]config.ScrapeTarget{target}, 0)
+	if len(c.targets) != 1 {
+		t.Fatalf("target %s not created", target.URL)
+	}
+	metrics, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	byName := make(map[string]model.Metric)
+	up := -1.0
+	for _, m := range metrics {
+		if m.Namespace == "Prometheus" && m.Name == "up" {
+			up = m.Value
+			continue
+		}
+		byName[m.Namespace+"."+m.SubNamespace+"."+m.Name] = m
+	}
+	return byName, up
+}
+
+func TestPrometheusScrape(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "# TYPE node_load1 gauge\nnode_load1 0.5\n# TYPE http_requests counter\nhttp_requests_total{code=\"200\"} 42\n")
+	}))
+	defer srv.Close()
+
+	metrics, up := scrapeOnce(t, config.ScrapeTarget{URL: srv.URL, Job: "node"})
+	if up != 1 {
+		t.Errorf("up = %v, want 1", up)
+	}
+	if load, ok := metrics["node..load1"]; !ok || load.Value != 0.5 || load.Type != "gauge" || load.Dimensions["job"] != "node" {
+		t.Errorf("unexpected node_load1: %+v", load)
+	}
+	if reqs := metrics["http..requests_total"]; reqs.Value != 42 || reqs.Type != "counter" || reqs.Dimensions["code"] != "200" {
+		t.Errorf("unexpected http_requests_total: %+v", reqs)
+	}
+}
+
+func TestPrometheusTargetDown(t *testing.T) {
+	t.Run("timeout", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+		}))
+		defer srv.Close()
+
+		metrics, up := scrapeOnce(t, config.ScrapeTarget{URL: srv.URL, Timeout: 50 * time.Millisecond})
+		if up != 0 {
+			t.Errorf("up = %v, want 0", up)
+		}
+		if len(metrics) != 1 { // scrape_duration_seconds only
+			t.Errorf("expected no samples, got %v", metrics)
+		}
+	})
+
+	t.Run("status", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "node_load1 1", http.StatusServiceUnavailable)
+		}))
+		defer srv.Close()
+
+		metrics, up := scrapeOnce(t, config.ScrapeTarget{URL: srv.URL})
+		if up != 0 {
+			t.Errorf("up = %v, want 0", up)
+		}
+		if len(metrics) != 1 {
+			t.Errorf("expected no samples, got %v", metrics)
+		}
+	})
+}
+
+// TestPrometheusCloseReleasesConnections checks that Close drops the
+// keep-alive connections left open by scrapes.
+func TestPrometheusCloseReleasesConnections(t *testing.T) {
+	closed := make(chan struct{}, 1)
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "up_test 1\n")
+	}))
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateClosed {
+			closed <- struct{}{}
+		}
+	}
+	srv.Start()
+	defer srv.Close()
+
+	c := NewPrometheusCollector([]config.ScrapeTarget{{URL: srv.URL}}, 0)
+	if _, err := c.Collect(context.Background()); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	c.Close()
+
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the idle scrape connection to be closed")
+	}
+}
@@ -0,0 +1,220 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/collector/custom/promparse.go
+// promparse.go parses the Prometheus text exposition format (version 0.0.4,
+// and the OpenMetrics text that exporters may send instead) into samples.
+
+package custom
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// promSample is one exposition line: a series and its value.
+type promSample struct {
+	name   string
+	labels map[string]string
+	value  float64
+}
+
+// parsePromText reads an exposition and returns its samples and the metric
+// family types declared by # TYPE lines. Comments other than TYPE, and
+// samples with a NaN or infinite value, are skipped.
+func parsePromText(r io.Reader) ([]promSample, map[string]string, error) {
+	var samples []promSample
+	types := make(map[string]string)
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for lineNo := 1; sc.Scan(); lineNo++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			// # TYPE <name> <type>
+			if f := strings.Fields(line); len(f) == 4 && f[1] == "TYPE" {
+				types[f[2]] = strings.ToLower(f[3])
+			}
+			continue
+		}
+
+		s, err := parsePromSample(line)
+		if err != nil {
+			return nil, nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		if math.IsNaN(s.value) || math.IsInf(s.value, 0) {
+			continue
+		}
+		samples = append(samples, s)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, nil, err
+	}
+	return samples, types, nil
+}
+
+// parsePromSample parses `name{label="value",...} value [timestamp]`. The
+// timestamp, if any, is ignored: samples are stamped with the scrape time.
+func parsePromSample(line string) (promSample, error) {
+	s := promSample{labels: map[string]string{}}
+
+	i := 0
+	for i < len(line) && isPromNameChar(line[i], i == 0) {
+		i++
+	}
+	if i == 0 {
+		return s, fmt.Errorf("invalid metric name in %q", line)
+	}
+	s.name = line[:i]
+	rest := line[i:]
+
+	if strings.HasPrefix(rest, "{") {
+		n, err := parsePromLabels(rest[1:], s.labels)
+		if err != nil {
+			return s, fmt.Errorf("%s: %w", s.name, err)
+		}
+		rest = rest[1+n:]
+	}
+
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return s, fmt.Errorf("%s: missing value", s.name)
+	}
+	v, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return s, fmt.Errorf("%s: invalid value %q", s.name, fields[0])
+	}
+	s.value = v
+	return s, nil
+}
+
+// parsePromLabels parses label pairs up to and including the closing brace
+// into labels and returns the number of bytes consumed.
+func parsePromLabels(in string, labels map[string]string) (int, error) {
+	i := 0
+	for {
+		for i < len(in) && (in[i] == ' ' || in[i] == ',') {
+			i++
+		}
+		if i >= len(in) {
+			return 0, fmt.Errorf("unterminated label set")
+		}
+		if in[i] == '}' {
+			return i + 1, nil
+		}
+
+		start := i
+		for i < len(in) && isPromNameChar(in[i], i == start) && in[i] != ':' {
+			i++
+		}
+		name := in[start:i]
+		if name == "" || i+1 >= len(in) || in[i] != '=' || in[i+1] != '"' {
+			return 0, fmt.Errorf("invalid label at %q", in[start:])
+		}
+		i += 2
+
+		var value strings.Builder
+		for ; ; i++ {
+			if i >= len(in) {
+				return 0, fmt.Errorf("unterminated value for label %q", name)
+			}
+			c := in[i]
+			if c == '"' {
+				i++
+				break
+			}
+			if c == '\\' && i+1 < len(in) {
+				i++
+				switch in[i] {
+				case 'n':
+					c = '\n'
+				default: // \\ and \"
+					c = in[i]
+				}
+			}
+			value.WriteByte(c)
+		}
+		labels[name] = value.String()
+	}
+}
+
+func isPromNameChar(c byte, first bool) bool {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '_', c == ':':
+		return true
+	case c >= '0' && c <= '9':
+		return !first
+	}
+	return false
+}
+
+// promSampleType returns the model metric type of a sample given the
+// declared family types, and the family the sample belongs to. Histogram
+// buckets, sums and counts and summary sums and counts are cumulative
+// counters; summary quantiles, gauges and untyped samples are gauges.
+func promSampleType(name string, types map[string]string) (typ, family string) {
+	if t, ok := types[name]; ok {
+		if t == "counter" {
+			return "counter", name
+		}
+		return "gauge", name
+	}
+	for _, suffix := range []string{"_bucket", "_sum", "_count", "_total"} {
+		base, ok := strings.CutSuffix(name, suffix)
+		if !ok {
+			continue
+		}
+		switch types[base] {
+		case "histogram", "summary", "counter":
+			return "counter", base
+		}
+	}
+	return "gauge", name
+}
+
+// splitPromName maps a Prometheus sample onto namespace, subnamespace and
+// name by the first two underscore-separated segments of its family, e.g.
+// node_cpu_seconds_total -> node / cpu / seconds_total. The rest of the
+// sample name (e.g. _bucket) stays on the name, so all series of a family
+// share a namespace. Families with a single segment use namespace
+// "Prometheus".
+func splitPromName(name, family string) (ns, sub, short string) {
+	suffix := strings.TrimPrefix(name, family)
+	parts := strings.SplitN(family, "_", 3)
+	switch len(parts) {
+	case 3:
+		if parts[0] != "" && parts[1] != "" && parts[2] != "" {
+			return parts[0], parts[1], parts[2] + suffix
+		}
+	case 2:
+		if parts[0] != "" && parts[1] != "" {
+			return parts[0], "", parts[1] + suffix
+		}
+	}
+	return "Prometheus", "", name
+}
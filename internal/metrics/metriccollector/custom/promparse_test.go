@@ -0,0 +1,125 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+package custom
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestParsePromSample(t *testing.T) {
+	s, err := parsePromSample(`http_requests_total{method="post",path="/a\"b\\c\nd"} 1027 1395066363000`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.name != "http_requests_total" || s.value != 1027 {
+		t.Errorf("got %s = %v, want http_requests_total = 1027", s.name, s.value)
+	}
+	if s.labels["method"] != "post" || s.labels["path"] != "/a\"b\\c\nd" {
+		t.Errorf("unexpected labels: %q", s.labels)
+	}
+
+	if s, err := parsePromSample(`up 1`); err != nil || s.value != 1 || len(s.labels) != 0 {
+		t.Errorf("sample without labels: %+v, %v", s, err)
+	}
+	if s, err := parsePromSample(`temp{sensor="a",} NaN`); err != nil || !math.IsNaN(s.value) {
+		t.Errorf("NaN sample: %+v, %v", s, err)
+	}
+
+	for _, line := range []string{
+		`m{a="b" 1`, // unterminated label set
+		`m{a="b} 1`, // unterminated value
+		`m{a=b} 1`,  // unquoted value
+		`m{a="b"}`,  // missing value
+		`m{} x`,     // invalid value
+		`0m 1`,      // invalid name
+	} {
+		if _, err := parsePromSample(line); err == nil {
+			t.Errorf("expected an error for %q", line)
+		}
+	}
+}
+
+func TestParsePromTextSkipsNaN(t *testing.T) {
+	in := "# HELP temp Temperature\n# TYPE temp gauge\ntemp{sensor=\"a\"} NaN\ntemp{sensor=\"b\"} 21.5\ntemp{sensor=\"c\"} +Inf\n"
+	samples, types, err := parsePromText(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 1 || samples[0].labels["sensor"] != "b" {
+		t.Errorf("expected only the finite sample, got %+v", samples)
+	}
+	if types["temp"] != "gauge" {
+		t.Errorf("unexpected types: %v", types)
+	}
+
+	if _, _, err := parsePromText(strings.NewReader("ok 1\nbroken{a=\"b\" 2\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("expected a line 2 error, got %v", err)
+	}
+}
+
+func TestPromSampleType(t *testing.T) {
+	types := map[string]string{
+		"req_duration_seconds": "histogram",
+		"rpc_latency":          "summary",
+		"http_requests":        "counter",
+		"http_errors_total":    "counter",
+		"temperature":          "gauge",
+	}
+	for _, tc := range []struct {
+		name, typ, family string
+	}{
+		{"req_duration_seconds_bucket", "counter", "req_duration_seconds"},
+		{"req_duration_seconds_sum", "counter", "req_duration_seconds"},
+		{"req_duration_seconds_count", "counter", "req_duration_seconds"},
+		{"rpc_latency", "gauge", "rpc_latency"}, // quantiles
+		{"rpc_latency_sum", "counter", "rpc_latency"},
+		{"rpc_latency_count", "counter", "rpc_latency"},
+		{"http_requests_total", "counter", "http_requests"},
+		{"http_errors_total", "counter", "http_errors_total"},
+		{"temperature", "gauge", "temperature"},
+		{"untyped_total", "gauge", "untyped_total"},
+	} {
+		typ, family := promSampleType(tc.name, types)
+		if typ != tc.typ || family != tc.family {
+			t.Errorf("promSampleType(%s) = %s, %s; want %s, %s", tc.name, typ, family, tc.typ, tc.family)
+		}
+	}
+}
+
+func TestSplitPromName(t *testing.T) {
+	for _, tc := range []struct {
+		name, family, ns, sub, short string
+	}{
+		{"node_cpu_seconds_total", "node_cpu_seconds_total", "node", "cpu", "seconds_total"},
+		{"req_duration_seconds_bucket", "req_duration_seconds", "req", "duration", "seconds_bucket"},
+		{"go_goroutines", "go_goroutines", "go", "", "goroutines"},
+		{"up", "up", "Prometheus", "", "up"},
+		{"_odd_name", "_odd_name", "Prometheus", "", "_odd_name"},
+	} {
+		ns, sub, short := splitPromName(tc.name, tc.family)
+		if ns != tc.ns || sub != tc.sub || short != tc.short {
+			t.Errorf("splitPromName(%s) = %s/%s/%s, want %s/%s/%s", tc.name, ns, sub, short, tc.ns, tc.sub, tc.short)
+		}
+	}
+}
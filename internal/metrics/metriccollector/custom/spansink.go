@@ -0,0 +1,53 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/collector/custom/spansink.go
+// spansink.go hands spans pushed to the otlp_socket source to the trace
+// pipeline, which the metric collectors otherwise know nothing about.
+
+package custom
+
+import (
+	"sync"
+
+	"github.com/aaronlmathis/gosight-shared/model"
+)
+
+var (
+	spanSinkMu sync.RWMutex
+	spanSink   func([]model.TraceSpan) bool
+)
+
+// SetSpanSink sets the function that receives spans exported to the OTLP
+// socket. It reports whether the spans were accepted. Until a sink is set,
+// trace exports to the socket are refused.
+func SetSpanSink(sink func([]model.TraceSpan) bool) {
+	spanSinkMu.Lock()
+	defer spanSinkMu.Unlock()
+	spanSink = sink
+}
+
+// currentSpanSink returns the span sink, or nil if none is set.
+func currentSpanSink() func([]model.TraceSpan) bool {
+	spanSinkMu.RLock()
+	defer spanSinkMu.RUnlock()
+	return spanSink
+}
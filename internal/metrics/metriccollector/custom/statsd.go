@@ -0,0 +1,351 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/collector/custom/statsd.go
+// statsd.go receives StatsD (and DogStatsD-tagged) metrics over UDP so
+// application code can push counters, gauges and timers to the local agent.
+// Values are aggregated between collections, so the collector's interval is
+// the flush interval.
+
+package custom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aaronlmathis/gosight-agent/internal/otelconvert"
+	"github.com/aaronlmathis/gosight-shared/model"
+	"github.com/aaronlmathis/gosight-shared/utils"
+)
+
+const (
+	// DefaultStatsDAddress is used when no bind address is configured.
+	DefaultStatsDAddress = "127.0.0.1:8125"
+
+	// maxStatsDSeries bounds the distinct series aggregated at once.
+	maxStatsDSeries = 10000
+	// statsdIdleFlushes is how many collections a series may go without
+	// updates before it is forgotten (a counter then restarts from zero).
+	statsdIdleFlushes = 10
+	// statsdWarnInterval rate-limits the malformed/dropped warnings.
+	statsdWarnInterval = time.Minute
+)
+
+// statsdSeries aggregates one metric name, type and tag set.
+type statsdSeries struct {
+	kind    string // c, g, ms, h or s
+	ns      string
+	sub     string
+	name    string
+	dims    map[string]string
+	updated bool
+	idle    int
+
+	value float64 // counter total since first seen, or gauge value
+
+	// timers/histograms and sets, reset every collection
+	count, sum, min, max float64
+	set                  map[string]struct{}
+}
+
+// StatsDCollector listens for StatsD lines on a UDP socket and returns the
+// series updated since the last Collect: counters as cumulative totals,
+// gauges as their current value, ms/h timers as min/max/count/sum
+// statistics and sets as the number of distinct values seen.
+type StatsDCollector struct {
+	addr   string
+	once   sync.Once
+	cancel context.CancelFunc
+
+	mu        sync.Mutex
+	series    map[string]*statsdSeries
+	malformed int
+	dropped   int
+	lastWarn  time.Time
+}
+
+// NewStatsDCollector creates a collector listening on addr (host:port),
+// DefaultStatsDAddress if empty.
+func NewStatsDCollector(addr string) *StatsDCollector {
+	if addr == "" {
+		addr = DefaultStatsDAddress
+	}
+	return &StatsDCollector{addr: addr, series: make(map[string]*statsdSeries)}
+}
+
+// Buffered marks the collector as returning pushed data only once.
+func (c *StatsDCollector) Buffered() {}
+
+// Name returns the name of the collector.
+// This is used to identify the collector in logs and metrics.
+func (c *StatsDCollector) Name() string {
+	return "statsd"
+}
+
+// Collect starts the listener on first call and flushes the series updated
+// since the previous call.
+func (c *StatsDCollector) Collect(ctx context.Context) ([]model.Metric, error) {
+	c.once.Do(func() {
+		runCtx, cancel := context.WithCancel(ctx)
+		c.mu.Lock()
+		c.cancel = cancel
+		c.mu.Unlock()
+		go c.run(runCtx)
+	})
+
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if (c.malformed > 0 || c.dropped > 0) && now.Sub(c.lastWarn) >= statsdWarnInterval {
+		utils.Warn("StatsD listener skipped %d malformed lines and dropped %d values (more than %d series)", c.malformed, c.dropped, maxStatsDSeries)
+		c.malformed, c.dropped = 0, 0
+		c.lastWarn = now
+	}
+
+	var out []model.Metric
+	for key, s := range c.series {
+		if !s.updated {
+			if s.idle++; s.idle >= statsdIdleFlushes {
+				delete(c.series, key)
+			}
+			continue
+		}
+		out = append(out, s.flush(now))
+		s.updated, s.idle = false, 0
+	}
+	return out, nil
+}
+
+// Close stops the listener.
+func (c *StatsDCollector) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cancel != nil {
+		c.cancel()
+	}
+}
+
+// run reads packets until ctx is canceled.
+func (c *StatsDCollector) run(ctx context.Context) {
+	conn, err := net.ListenPacket("udp", c.addr)
+	if err != nil {
+		utils.Error("StatsD listener disabled: %v", err)
+		return
+	}
+	go func() {
+		<-ctx.Done()
+		_ = conn.Close()
+	}()
+
+	utils.Info("StatsD listener receiving on %s", c.addr)
+
+	buf := make([]byte, 65535)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			if ctx.Err() == nil && !errors.Is(err, net.ErrClosed) {
+				utils.Error("StatsD listener stopped: %v", err)
+			}
+			return
+		}
+		c.handlePacket(string(buf[:n]))
+	}
+}
+
+// handlePacket aggregates every line of a packet.
+func (c *StatsDCollector) handlePacket(packet string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, line := range strings.Split(packet, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if err := c.handleLine(line); err != nil {
+			utils.Debug("StatsD: skipping line %q: %v", line, err)
+			c.malformed++
+		}
+	}
+}
+
+// handleLine parses "name:value[:value...]|type[|@rate][|#tag:value,...]"
+// and adds it to its series. The caller holds c.mu.
+func (c *StatsDCollector) handleLine(line string) error {
+	name, rest, ok := strings.Cut(line, ":")
+	if !ok || name == "" {
+		return fmt.Errorf("expected name:value|type")
+	}
+	fields := strings.Split(rest, "|")
+	if len(fields) < 2 {
+		return fmt.Errorf("missing type")
+	}
+	kind := fields[1]
+	switch kind {
+	case "c", "g", "ms", "h", "s":
+	default:
+		return fmt.Errorf("unsupported type %q", kind)
+	}
+
+	rate := 1.0
+	var tags []string
+	for _, f := range fields[2:] {
+		switch {
+		case strings.HasPrefix(f, "@"):
+			r, err := strconv.ParseFloat(f[1:], 64)
+			if err != nil || r <= 0 || r > 1 {
+				return fmt.Errorf("invalid sample rate %q", f)
+			}
+			rate = r
+		case strings.HasPrefix(f, "#"):
+			tags = strings.Split(f[1:], ",")
+		}
+		// Other DogStatsD extensions (c:container, T:timestamp) are ignored
+	}
+
+	// Parse every value first so a malformed line changes nothing
+	raws := strings.Split(fields[0], ":")
+	values := make([]float64, len(raws))
+	if kind != "s" {
+		for i, raw := range raws {
+			v, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				return fmt.Errorf("invalid value %q", raw)
+			}
+			values[i] = v
+		}
+	}
+
+	s, ok := c.lookup(kind, name, tags)
+	if !ok {
+		c.dropped++
+		return nil
+	}
+
+	for i, raw := range raws {
+		if kind == "s" {
+			if s.set == nil {
+				s.set = make(map[string]struct{})
+			}
+			s.set[raw] = struct{}{}
+			continue
+		}
+		s.add(raw, values[i], rate)
+	}
+	s.updated = true
+	return nil
+}
+
+// lookup returns the series for kind, name and tags, creating it unless the
+// series limit is reached. The caller holds c.mu.
+func (c *StatsDCollector) lookup(kind, name string, tags []string) (*statsdSeries, bool) {
+	sort.Strings(tags)
+	key := kind + "|" + name + "|" + strings.Join(tags, ",")
+	if s, ok := c.series[key]; ok {
+		return s, true
+	}
+	if len(c.series) >= maxStatsDSeries {
+		return nil, false
+	}
+
+	ns, sub, short := otelconvert.SplitMetricName(name)
+	s := &statsdSeries{kind: kind, ns: ns, sub: sub, name: short}
+	for _, tag := range tags {
+		if tag == "" {
+			continue
+		}
+		if s.dims == nil {
+			s.dims = make(map[string]string)
+		}
+		k, v, _ := strings.Cut(tag, ":")
+		s.dims[k] = v
+	}
+	c.series[key] = s
+	return s, true
+}
+
+// add applies one value. Counters are scaled by the sample rate; gauge values
+// with an explicit sign adjust the current value instead of replacing it.
+func (s *statsdSeries) add(raw string, v, rate float64) {
+	switch s.kind {
+	case "c":
+		s.value += v / rate
+	case "g":
+		if strings.HasPrefix(raw, "+") || strings.HasPrefix(raw, "-") {
+			s.value += v
+		} else {
+			s.value = v
+		}
+	case "ms", "h":
+		if s.count == 0 || v < s.min {
+			s.min = v
+		}
+		if s.count == 0 || v > s.max {
+			s.max = v
+		}
+		s.count += 1 / rate
+		s.sum += v / rate
+	}
+}
+
+// flush returns the series' metric for this collection and resets the
+// per-interval timer and set state.
+func (s *statsdSeries) flush(now time.Time) model.Metric {
+	m := model.Metric{
+		Namespace:    s.ns,
+		SubNamespace: s.sub,
+		Name:         s.name,
+		Timestamp:    now,
+		Type:         "gauge",
+		Dimensions:   copyDims(s.dims),
+	}
+	switch s.kind {
+	case "c":
+		m.Type = "counter"
+		m.Value = s.value
+	case "g":
+		m.Value = s.value
+	case "ms", "h":
+		if s.kind == "ms" {
+			m.Unit = "ms"
+		}
+		m.Value = s.sum / s.count
+		m.StatisticValues = &model.StatisticValues{
+			Minimum:     s.min,
+			Maximum:     s.max,
+			SampleCount: int(s.count + 0.5),
+			Sum:         s.sum,
+		}
+		s.count, s.sum, s.min, s.max = 0, 0, 0, 0
+	case "s":
+		m.Value = float64(len(s.set))
+		s.set = nil
+	}
+	return m
+}
@@ -0,0 +1,129 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+package custom
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aaronlmathis/gosight-shared/model"
+)
+
+// newTestStatsD returns a collector whose listener is never started, so
+// tests feed it packets directly.
+func newTestStatsD() *StatsDCollector {
+	c := NewStatsDCollector("")
+	c.once.Do(func() {})
+	return c
+}
+
+// flushStatsD collects once and returns the metrics by ns.sub.name.
+func flushStatsD(t *testing.T, c *StatsDCollector) map[string]model.Metric {
+	t.Helper()
+	metrics, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := make(map[string]model.Metric, len(metrics))
+	for _, m := range metrics {
+		out[m.Namespace+"."+m.SubNamespace+"."+m.Name] = m
+	}
+	return out
+}
+
+func TestStatsDSampleRatesAndGauges(t *testing.T) {
+	c := newTestStatsD()
+	c.handlePacket("app.web.requests:1|c|@0.1\napp.web.requests:2|c\n" +
+		"app.web.latency:10|ms|@0.5\napp.web.latency:30|ms\n" +
+		"app.web.queue:10|g\napp.web.queue:+5|g\napp.web.queue:-3|g\n")
+
+	got := flushStatsD(t, c)
+	if m := got["app.web.requests"]; m.Type != "counter" || m.Value != 12 {
+		t.Errorf("counter: got %v (%s), want 12", m.Value, m.Type)
+	}
+	lat := got["app.web.latency"]
+	if st := lat.StatisticValues; st == nil || st.SampleCount != 3 || st.Sum != 50 || st.Minimum != 10 || st.Maximum != 30 {
+		t.Errorf("timer: unexpected statistics %+v", lat.StatisticValues)
+	}
+	if q := got["app.web.queue"]; q.Value != 12 {
+		t.Errorf("gauge: got %v, want 12", q.Value)
+	}
+
+	// Counters are cumulative; gauges keep their value until updated again
+	c.handlePacket("app.web.requests:3|c\napp.web.queue:-2|g")
+	got = flushStatsD(t, c)
+	if got["app.web.requests"].Value != 15 || got["app.web.queue"].Value != 10 {
+		t.Errorf("second flush: requests=%v queue=%v, want 15 and 10", got["app.web.requests"].Value, got["app.web.queue"].Value)
+	}
+	if _, ok := got["app.web.latency"]; ok {
+		t.Error("timer without new samples should not be flushed")
+	}
+}
+
+func TestStatsDMultiValueAndTags(t *testing.T) {
+	c := newTestStatsD()
+	c.handlePacket("app.db.query:4:6:8|h|#env:prod,region:eu\napp.db.query:100|h|#region:eu,env:prod\n" +
+		"app.users:alice|s\napp.users:bob|s\napp.users:alice|s\n")
+
+	got := flushStatsD(t, c)
+	q := got["app.db.query"]
+	if q.Dimensions["env"] != "prod" || q.Dimensions["region"] != "eu" {
+		t.Errorf("unexpected tags: %v", q.Dimensions)
+	}
+	// Tag order doesn't split the series
+	if st := q.StatisticValues; st == nil || st.SampleCount != 4 || st.Sum != 118 {
+		t.Errorf("histogram: unexpected statistics %+v", q.StatisticValues)
+	}
+	if u := got["app..users"]; u.Value != 2 {
+		t.Errorf("set: got %v distinct values, want 2", u.Value)
+	}
+}
+
+func TestStatsDMalformedAndLimit(t *testing.T) {
+	c := newTestStatsD()
+	c.handlePacket("app.ok:1|c\nnovalue\napp.bad:1|x\napp.rate:1|c|@2\napp.partial:1:oops|c\n:1|c")
+	if c.malformed != 5 {
+		t.Errorf("malformed = %d, want 5", c.malformed)
+	}
+	got := flushStatsD(t, c)
+	if len(got) != 1 || got["app..ok"].Value != 1 {
+		t.Errorf("expected only app.ok, got %v", got)
+	}
+
+	// A line with an invalid value must not apply its valid ones
+	c.handlePacket("app.ok:5:bad|c\napp.ok:1|c")
+	if got := flushStatsD(t, c); got["app..ok"].Value != 2 {
+		t.Errorf("partially applied line: counter at %v, want 2", got["app..ok"].Value)
+	}
+
+	c = newTestStatsD()
+	var packet strings.Builder
+	for i := 0; i <= maxStatsDSeries; i++ {
+		fmt.Fprintf(&packet, "app.series.s%d:1|c\n", i)
+	}
+	c.handlePacket(packet.String())
+	if len(c.series) != maxStatsDSeries || c.dropped != 1 {
+		t.Errorf("series=%d dropped=%d, want %d and 1", len(c.series), c.dropped, maxStatsDSeries)
+	}
+}
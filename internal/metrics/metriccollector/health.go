@@ -0,0 +1,144 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/collector/health.go
+// health.go - backs off collectors that keep failing and reports their state.
+
+package metriccollector
+
+import (
+	"time"
+
+	"github.com/aaronlmathis/gosight-agent/internal/config"
+	agentutils "github.com/aaronlmathis/gosight-agent/internal/utils"
+	"github.com/aaronlmathis/gosight-shared/model"
+	"github.com/aaronlmathis/gosight-shared/utils"
+)
+
+const defaultProbeInterval = time.Minute
+
+// collectorHealth tracks the consecutive failures of one collector.
+type collectorHealth struct {
+	failures    int
+	nextProbe   time.Time // zero unless the collector is backed off
+	lastSuccess time.Time // zero until the collector first succeeds
+}
+
+// backedOff reports whether h has reached the failure threshold.
+func (h *collectorHealth) backedOff(threshold int) bool {
+	return threshold > 0 && h.failures >= threshold
+}
+
+// healthTracker moves collectors that fail FailureThreshold times in a row to
+// a slow probe cadence until they succeed again. It is used only from
+// MetricRegistry.Collect and is not safe for concurrent use.
+type healthTracker struct {
+	threshold int
+	probe     time.Duration
+	state     map[string]*collectorHealth
+}
+
+func newHealthTracker(cfg config.CollectorBackoffConfig) *healthTracker {
+	probe := cfg.ProbeInterval
+	if probe <= 0 {
+		probe = defaultProbeInterval
+	}
+	return &healthTracker{
+		threshold: cfg.FailureThreshold,
+		probe:     probe,
+		state:     make(map[string]*collectorHealth),
+	}
+}
+
+func (t *healthTracker) get(name string) *collectorHealth {
+	h := t.state[name]
+	if h == nil {
+		h = &collectorHealth{}
+		t.state[name] = h
+	}
+	return h
+}
+
+// due reports whether the collector should run at now. Collectors that are not
+// backed off always run; backed-off ones only once their probe time is reached.
+func (t *healthTracker) due(name string, now time.Time) bool {
+	h := t.get(name)
+	return !h.backedOff(t.threshold) || !now.Before(h.nextProbe)
+}
+
+// inBackoff reports whether the collector is currently on the probe cadence.
+func (t *healthTracker) inBackoff(name string) bool {
+	return t.get(name).backedOff(t.threshold)
+}
+
+// record updates the collector's state with the result of a run at now.
+func (t *healthTracker) record(name string, err error, now time.Time) {
+	h := t.get(name)
+	if err == nil {
+		if h.backedOff(t.threshold) {
+			utils.Info("Collector %s recovered after %d consecutive failures; restoring normal interval", name, h.failures)
+		}
+		h.failures = 0
+		h.nextProbe = time.Time{}
+		h.lastSuccess = now
+		return
+	}
+
+	h.failures++
+	if t.threshold <= 0 || h.failures < t.threshold {
+		return
+	}
+	if h.failures == t.threshold {
+		utils.Warn("Collector %s failed %d times in a row; retrying every %v until it succeeds", name, h.failures, t.probe)
+	}
+	h.nextProbe = now.Add(t.probe)
+}
+
+// metrics returns, per collector seen so far (dimension "collector"), Agent
+// collector_last_success_timestamp (Unix seconds of the last run without
+// error, once there has been one) and, when backoff is enabled,
+// collector_healthy, collector_backoff and collector_consecutive_failures.
+func (t *healthTracker) metrics(now time.Time) []model.Metric {
+	out := make([]model.Metric, 0, len(t.state)*4)
+	for name, h := range t.state {
+		dims := map[string]string{"collector": name}
+		if !h.lastSuccess.IsZero() {
+			out = append(out, agentutils.Metric("Agent", "", "collector_last_success_timestamp",
+				h.lastSuccess.Unix(), "gauge", "seconds", dims, now))
+		}
+		if t.threshold <= 0 {
+			continue
+		}
+		healthy, backoff := 0, 0
+		if h.failures == 0 {
+			healthy = 1
+		}
+		if h.backedOff(t.threshold) {
+			backoff = 1
+		}
+		out = append(out,
+			agentutils.Metric("Agent", "", "collector_healthy", healthy, "gauge", "bool", dims, now),
+			agentutils.Metric("Agent", "", "collector_backoff", backoff, "gauge", "bool", dims, now),
+			agentutils.Metric("Agent", "", "collector_consecutive_failures", h.failures, "gauge", "count", dims, now),
+		)
+	}
+	return out
+}
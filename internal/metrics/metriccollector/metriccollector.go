@@ -35,3 +35,33 @@ type MetricCollector interface {
 	Name() string
 	Collect(ctx context.Context) ([]model.Metric, error)
 }
+
+// BufferedCollector is implemented by collectors whose Collect returns the
+// data pushed to them since the previous call (fifo, statsd, otlp_socket).
+// Only the regular collection cycle may collect from them; any other caller
+// would take that data away from the next send.
+type BufferedCollector interface {
+	MetricCollector
+	Buffered()
+}
+
+// IsBuffered reports whether c is a BufferedCollector.
+func IsBuffered(c MetricCollector) bool {
+	_, ok := c.(BufferedCollector)
+	return ok
+}
+
+// StatefulCollector is implemented by collectors whose result depends on the
+// previous call, e.g. rates from the previous container stats or process
+// restarts. Only the regular collection cycle may collect from them; an
+// extra call would shorten the next cycle's window or consume its changes.
+type StatefulCollector interface {
+	MetricCollector
+	Stateful()
+}
+
+// IsStateful reports whether c is a StatefulCollector.
+func IsStateful(c MetricCollector) bool {
+	_, ok := c.(StatefulCollector)
+	return ok
+}
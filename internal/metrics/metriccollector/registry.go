@@ -26,64 +26,520 @@ package metriccollector
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/aaronlmathis/gosight-agent/internal/config"
 	"github.com/aaronlmathis/gosight-agent/internal/metrics/metriccollector/container"
+	"github.com/aaronlmathis/gosight-agent/internal/metrics/metriccollector/custom"
+	"github.com/aaronlmathis/gosight-agent/internal/metrics/metriccollector/self"
 	"github.com/aaronlmathis/gosight-agent/internal/metrics/metriccollector/system"
+	"github.com/aaronlmathis/gosight-agent/internal/selfmetrics"
+	agentutils "github.com/aaronlmathis/gosight-agent/internal/utils"
 	"github.com/aaronlmathis/gosight-shared/model"
 	"github.com/aaronlmathis/gosight-shared/utils"
 )
 
+// selfCollectors are always registered and report on the agent itself rather
+// than on a configured source.
+var selfCollectors = map[string]bool{"agent": true, "agent_exports": true, "agent_cycles": true, "agent_pipeline": true}
+
 // Registry holds active collectors keyed by name
 type MetricRegistry struct {
 	Collectors map[string]MetricCollector
+
+	// mu guards Collectors and schedule, which Reload replaces, and the
+	// results of the most recent Collect.
+	mu sync.Mutex
+
+	// collectMu serializes calls into the collectors, which keep unlocked
+	// state (e.g. previous container stats) between calls
+	collectMu sync.Mutex
+
+	// lastErrors holds the error each collector returned on the most recent Collect
+	lastErrors map[string]error
+
+	// produced records the metrics each collector emitted on the most recent
+	// Collect. Collect returns copies, so the runner's relabeling and default
+	// dimensions never touch these.
+	produced map[string][]model.Metric
+
+	// health backs off collectors that fail repeatedly
+	health *healthTracker
+
+	// schedule runs collectors with their own interval on a subset of ticks
+	schedule *collectorSchedule
 }
 
 // NewRegistry initializes and registers enabled collectors based on the configuration.
 // It creates a new MetricRegistry instance and populates it with the specified collectors.
 // The collectors are created based on the configuration settings and are stored in a map.
-// The function returns a pointer to the MetricRegistry instance.
+// The function returns a pointer to the MetricRegistry instance, or an error if
+// two collectors would be registered under the same name (e.g. a source listed
+// twice), rather than letting one silently replace the other.
 // It also logs the number of loaded collectors for debugging purposes.
-func NewRegistry(cfg *config.Config) *MetricRegistry {
-	reg := &MetricRegistry{Collectors: make(map[string]MetricCollector)}
+func NewRegistry(cfg *config.Config) (*MetricRegistry, error) {
+	reg := &MetricRegistry{
+		health: newHealthTracker(cfg.Agent.MetricCollection.CollectorBackoff),
+	}
+
+	schedule, errs := configureCollection(cfg)
+	reg.schedule = schedule
+
+	collectors, buildErrs := buildCollectors(cfg, nil)
+	reg.Collectors = collectors
+	errs = append(errs, buildErrs...)
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid metric collector configuration: %s", strings.Join(errs, "; "))
+	}
+	warnUnknownIntervals(cfg, reg.Collectors)
+	utils.Info("Loaded %d metric collectors", len(reg.Collectors))
+
+	return reg, nil
+}
+
+// Reload applies a new configuration to a running registry. Collectors that
+// are no longer enabled are closed and removed, newly enabled ones are
+// constructed, and collectors that stay enabled keep running with their state
+// (previous counters for rates, backoff). Settings that only a collector's
+// constructor reads (e.g. smart.devices) take effect for a collector that
+// stays enabled only after a restart. On error the registry is unchanged.
+func (r *MetricRegistry) Reload(cfg *config.Config) error {
+	schedule, errs := configureCollection(cfg)
+
+	current := r.Active()
+	collectors, buildErrs := buildCollectors(cfg, current)
+	errs = append(errs, buildErrs...)
+	if len(errs) > 0 {
+		closeCollectors(collectors, current)
+		return fmt.Errorf("invalid metric collector configuration: %s", strings.Join(errs, "; "))
+	}
+	warnUnknownIntervals(cfg, collectors)
+
+	r.mu.Lock()
+	r.Collectors = collectors
+	r.schedule = schedule
+	r.mu.Unlock()
+
+	closeCollectors(current, collectors)
+	for name := range current {
+		if _, ok := collectors[name]; !ok {
+			utils.Info("Metric collector %s disabled", name)
+		}
+	}
+	for name := range collectors {
+		if _, ok := current[name]; !ok {
+			utils.Info("Metric collector %s enabled", name)
+		}
+	}
+	utils.Info("Reloaded metric collectors: %d active", len(collectors))
+	return nil
+}
+
+// configureCollection builds the collection schedule and applies the
+// process-wide rate settings for cfg, returning any configuration errors.
+func configureCollection(cfg *config.Config) (*collectorSchedule, []string) {
+	var errs []string
+
+	schedule, err := newCollectorSchedule(cfg.Agent.MetricCollection.Interval, cfg.Agent.MetricCollection.CollectorIntervals)
+	if err != nil {
+		errs = append(errs, err.Error())
+	}
+	if err := agentutils.SetRateTimestamp(cfg.Agent.MetricCollection.RateTimestamp); err != nil {
+		errs = append(errs, err.Error())
+	}
+	if err := agentutils.SetClockStep(cfg.Agent.MetricCollection.ClockStep); err != nil {
+		errs = append(errs, err.Error())
+	}
+	return schedule, errs
+}
+
+// warnUnknownIntervals warns about collector_intervals entries that name no
+// enabled collector.
+func warnUnknownIntervals(cfg *config.Config, collectors map[string]MetricCollector) {
+	for name := range cfg.Agent.MetricCollection.CollectorIntervals {
+		if _, ok := collectors[name]; !ok {
+			utils.Warn("collector_intervals: no collector named %q is enabled; ignoring its interval", name)
+		}
+	}
+}
+
+// collectorBuilder assembles the collectors enabled by a configuration.
+// Collectors already running under the same name are reused rather than
+// constructed again, so a reload neither resets their state nor re-opens
+// their sockets and files.
+type collectorBuilder struct {
+	existing   map[string]MetricCollector
+	collectors map[string]MetricCollector
+	errs       []string
+}
+
+// add registers the collector called name, constructing it with mk unless a
+// running one is reused, or records an error if the name is already taken.
+func (b *collectorBuilder) add(name string, mk func() MetricCollector) {
+	if _, exists := b.collectors[name]; exists {
+		b.errs = append(b.errs, fmt.Sprintf("duplicate collector %q (listed more than once or conflicts with a built-in collector)", name))
+		return
+	}
+	if c, ok := b.existing[name]; ok {
+		b.collectors[name] = c
+		return
+	}
+	b.collectors[name] = mk()
+}
+
+// buildCollectors returns the collectors cfg enables, reusing those in
+// existing (which may be nil), and any configuration errors.
+func buildCollectors(cfg *config.Config, existing map[string]MetricCollector) (map[string]MetricCollector, []string) {
+	b := &collectorBuilder{
+		existing:   existing,
+		collectors: make(map[string]MetricCollector),
+	}
 
 	for _, name := range cfg.Agent.MetricCollection.Sources {
 		switch name {
 		case "cpu":
-			reg.Collectors["cpu"] = system.NewCPUCollector(cfg.Agent.MetricCollection.Interval)
+			b.add("cpu", func() MetricCollector {
+				return system.NewCPUCollector(cfg.Agent.MetricCollection.Interval, cfg.Agent.MetricCollection.CPUPerCoreMaxCores, cfg.Agent.MetricCollection.CPUDistinctTotalName)
+			})
 		case "mem":
-			reg.Collectors["mem"] = system.NewMemCollector()
+			b.add("mem", func() MetricCollector { return system.NewMemCollector() })
 		case "disk":
-			reg.Collectors["disk"] = system.NewDiskCollector()
+			b.add("disk", func() MetricCollector { return system.NewDiskCollector(cfg.Agent.MetricCollection.Disk) })
 		case "host":
-			reg.Collectors["host"] = system.NewHostCollector()
+			b.add("host", func() MetricCollector { return system.NewHostCollector() })
 		case "net":
-			reg.Collectors["net"] = system.NewNetworkCollector()
+			b.add("net", func() MetricCollector { return system.NewNetworkCollector() })
+		case "conn":
+			b.add("conn", func() MetricCollector { return system.NewConnCollector() })
+		case "zfs":
+			b.add("zfs", func() MetricCollector { return system.NewZFSCollector() })
+		case "btrfs":
+			b.add("btrfs", func() MetricCollector { return system.NewBtrfsCollector() })
+		case "gpu":
+			b.add("gpu", func() MetricCollector { return system.NewGPUCollector() })
+		case "mdstat":
+			b.add("mdstat", func() MetricCollector { return system.NewMDStatCollector() })
+		case "meminfo":
+			b.add("meminfo", func() MetricCollector { return system.NewMemInfoCollector() })
+		case "swaps":
+			b.add("swaps", func() MetricCollector { return system.NewSwapsCollector() })
+		case "smart":
+			b.add("smart", func() MetricCollector { return system.NewSMARTCollector(cfg.Agent.MetricCollection.SMART.Devices) })
+		case "fifo":
+			b.add("fifo", func() MetricCollector { return custom.NewFIFOCollector(cfg.Agent.MetricCollection.FifoPath) })
+		case "otlp_socket":
+			b.add("otlp_socket", func() MetricCollector {
+				return custom.NewOTLPSocketCollector(cfg.Agent.MetricCollection.OTLPSocketPath)
+			})
+		case "sensors":
+			b.add("sensors", func() MetricCollector { return system.NewSensorsCollector() })
+		case "systemd":
+			b.add("systemd", func() MetricCollector { return system.NewSystemdCollector(cfg.Agent.MetricCollection.Systemd.Units) })
+		case "statsd":
+			b.add("statsd", func() MetricCollector { return custom.NewStatsDCollector(cfg.Agent.MetricCollection.StatsD.Address) })
+		case "prometheus":
+			b.add("prometheus", func() MetricCollector {
+				return custom.NewPrometheusCollector(cfg.Agent.MetricCollection.Prometheus.Targets, cfg.Agent.Limits.ScrapeTargets())
+			})
+		case "winservices":
+			ws := cfg.Agent.MetricCollection.WindowsServices
+			b.add("winservices", func() MetricCollector { return system.NewWindowsServiceCollector(ws.Services, ws.AllAutoStart) })
+		case "ntp":
+			b.add("ntp", func() MetricCollector { return system.NewNTPCollector(cfg.Agent.MetricCollection.NTPDaemon) })
 		case "podman":
-			reg.Collectors["podman"] = container.NewPodmanCollectorWithSocket(cfg.Podman.Socket)
+			if !cfg.Podman.Enabled {
+				utils.Info("Skipping podman collector: podman.enabled is false")
+				continue
+			}
+			b.add("podman", func() MetricCollector {
+				c := container.NewPodmanCollectorWithSocket(cfg.Podman.Socket, cfg.Agent.MetricCollection.ContainerLabelFields)
+				c.APIVersion = cfg.Podman.APIVersion
+				return c
+			})
 		case "docker":
-			reg.Collectors["docker"] = container.NewDockerCollector()
+			if !cfg.Docker.Enabled {
+				utils.Info("Skipping docker collector: docker.enabled is false")
+				continue
+			}
+			b.add("docker", func() MetricCollector {
+				return container.NewDockerCollector(cfg.Agent.MetricCollection.ContainerLabelFields, cfg.Docker.APIVersion)
+			})
 		default:
 			utils.Warn(" Unknown collector: %s (skipping) \n", name)
 		}
 	}
-	utils.Info("Loaded %d metric collectors", len(reg.Collectors))
 
-	return reg
+	// Watched processes are tracked for restarts whenever a watch list is configured
+	if len(cfg.Agent.ProcessCollection.Watch) > 0 {
+		b.add("procwatch", func() MetricCollector { return system.NewProcessWatchCollector(cfg.Agent.ProcessCollection.Watch) })
+	}
+	// Per-user usage is aggregated by the process runner and reported here
+	if cfg.Agent.ProcessCollection.PerUser {
+		b.add("users", func() MetricCollector { return system.NewUserUsageCollector() })
+	}
+	// The agent always reports the health of its own server connection, export
+	// sizes, cycle durations and pipeline (queues, drops, send errors, runtime)
+	b.add("agent", func() MetricCollector { return self.NewConnectionCollector() })
+	b.add("agent_exports", func() MetricCollector { return self.NewExportSizeCollector() })
+	b.add("agent_cycles", func() MetricCollector { return self.NewCycleCollector() })
+	b.add("agent_pipeline", func() MetricCollector { return self.NewPipelineCollector() })
+
+	return b.collectors, b.errs
+}
+
+// closeCollectors closes the collectors in set that are not in keep and hold
+// resources (implement Close).
+func closeCollectors(set, keep map[string]MetricCollector) {
+	for name, c := range set {
+		if _, kept := keep[name]; kept {
+			continue
+		}
+		switch closer := c.(type) {
+		case io.Closer:
+			if err := closer.Close(); err != nil {
+				utils.Warn("Error closing metric collector %s: %v", name, err)
+			}
+		case interface{ Close() }:
+			closer.Close()
+		}
+	}
+}
+
+// Active returns a copy of the currently registered collectors by name.
+func (r *MetricRegistry) Active() map[string]MetricCollector {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	active := make(map[string]MetricCollector, len(r.Collectors))
+	for name, c := range r.Collectors {
+		active[name] = c
+	}
+	return active
+}
+
+// TickInterval is how often the runner should call Collect: the shortest of
+// the global interval and the collector_intervals overrides.
+func (r *MetricRegistry) TickInterval() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.schedule.tick
 }
 
-// Collect runs all active collectors and returns all collected metrics
+// LongestInterval is the longest collection interval of any collector: the
+// global interval or a longer collector_intervals override.
+func (r *MetricRegistry) LongestInterval() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.schedule.longest()
+}
+
+// Due reports whether any collector is due to run at now. Ticks on which no
+// collector is due (only possible with collector_intervals) can be skipped.
+func (r *MetricRegistry) Due(now time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for name := range r.Collectors {
+		if r.schedule.due(name, now) {
+			return true
+		}
+	}
+	return false
+}
+
+// Collect runs the active collectors that are due (see collector_intervals)
+// and returns their metrics. Collectors that have failed
+// collector_backoff.failure_threshold times in a row are only probed every
+// probe_interval until they succeed; their last error is kept for
+// Diagnostics in between. Collectors that are not due keep their previous
+// result for Diagnostics and Producers.
 func (r *MetricRegistry) Collect(ctx context.Context) ([]model.Metric, error) {
 	var all []model.Metric
+	now := time.Now()
+
+	r.mu.Lock()
+	collectors := r.Collectors
+	schedule := r.schedule
+	prevErrs := r.lastErrors
+	prevProduced := r.produced
+	r.mu.Unlock()
 
-	for name, collector := range r.Collectors {
+	errs := make(map[string]error)
+	produced := make(map[string][]model.Metric, len(collectors))
+
+	r.collectMu.Lock()
+	defer r.collectMu.Unlock()
+
+	for name, collector := range collectors {
+		if !schedule.due(name, now) {
+			errs[name] = prevErrs[name]
+			produced[name] = prevProduced[name]
+			continue
+		}
+		schedule.ran(name, now)
+		if !r.health.due(name, now) {
+			errs[name] = prevErrs[name]
+			continue
+		}
+		probing := r.health.inBackoff(name)
+		started := time.Now()
 		metrics, err := collector.Collect(ctx)
+		selfmetrics.RecordCollectorDuration(name, time.Since(started))
+		r.health.record(name, err, now)
 		if err != nil {
-			utils.Error(" Error collecting %s: %v\n", name, err)
+			if probing {
+				utils.Debug("Probe of collector %s failed: %v", name, err)
+			} else {
+				utils.Error(" Error collecting %s: %v\n", name, err)
+			}
+			errs[name] = err
 			continue
 		}
-		all = append(all, metrics...)
+		all = append(all, copyMetrics(metrics)...)
+		produced[name] = metrics
 	}
+	all = append(all, r.health.metrics(now)...)
+
+	r.mu.Lock()
+	r.lastErrors = errs
+	r.produced = produced
+	r.mu.Unlock()
 
 	return all, nil
 }
+
+// CollectFrom runs only the named collectors and returns their metrics.
+// Unknown names and buffered or stateful collectors (see BufferedCollector
+// and StatefulCollector), which only the regular Collect may call, are
+// ignored. It waits for a running Collect
+// to finish and does not affect Diagnostics.
+func (r *MetricRegistry) CollectFrom(ctx context.Context, names []string) []model.Metric {
+	var all []model.Metric
+	active := r.Active()
+
+	r.collectMu.Lock()
+	defer r.collectMu.Unlock()
+
+	for _, name := range names {
+		collector, ok := active[name]
+		if !ok || IsBuffered(collector) || IsStateful(collector) {
+			continue
+		}
+		metrics, err := collector.Collect(ctx)
+		if err != nil {
+			utils.Debug("Error collecting %s: %v", name, err)
+			continue
+		}
+		all = append(all, metrics...)
+	}
+	return all
+}
+
+// LastCollected returns, for each active collector, the metrics it emitted
+// and the error it returned on the most recent Collect. A collector that has
+// not run yet appears in neither map. Unlike Collect it does not call into
+// the collectors, so it has no effect on their state.
+func (r *MetricRegistry) LastCollected() (map[string][]model.Metric, map[string]error) {
+	active := r.Active()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	metrics := make(map[string][]model.Metric)
+	errs := make(map[string]error)
+	for name := range active {
+		if err := r.lastErrors[name]; err != nil {
+			errs[name] = err
+		}
+		if m, ok := r.produced[name]; ok {
+			metrics[name] = copyMetrics(m)
+		}
+	}
+	return metrics, errs
+}
+
+// SourceMetrics returns how many metrics the configured collectors emitted
+// on their most recent run, leaving out the agent's own self-metrics, which
+// are reported on every cycle regardless.
+func (r *MetricRegistry) SourceMetrics() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := 0
+	for name, metrics := range r.produced {
+		if !selfCollectors[name] {
+			n += len(metrics)
+		}
+	}
+	return n
+}
+
+// Producers returns the sorted names of collectors that emitted at least one
+// metric satisfying match on the most recent Collect.
+func (r *MetricRegistry) Producers(match func(model.Metric) bool) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var names []string
+	for name, metrics := range r.produced {
+		for _, m := range metrics {
+			if match(m) {
+				names = append(names, name)
+				break
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Diagnostics describes the registered collectors and the result of each on the
+// most recent Collect, e.g. "cpu: ok, docker: error: permission denied".
+// It is used to explain why a collection cycle produced no metrics.
+func (r *MetricRegistry) Diagnostics() string {
+	active := r.Active()
+	if len(active) == 0 {
+		return "no collectors registered (check agent.metric_collection.sources)"
+	}
+
+	names := make([]string, 0, len(active))
+	for name := range active {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		if err := r.lastErrors[name]; err != nil {
+			parts = append(parts, fmt.Sprintf("%s: error: %v", name, err))
+		} else {
+			parts = append(parts, name+": ok (no metrics)")
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// copyMetrics copies metrics along with their dimension maps.
+func copyMetrics(metrics []model.Metric) []model.Metric {
+	out := make([]model.Metric, len(metrics))
+	for i, m := range metrics {
+		if m.Dimensions != nil {
+			dims := make(map[string]string, len(m.Dimensions))
+			for k, v := range m.Dimensions {
+				dims[k] = v
+			}
+			m.Dimensions = dims
+		}
+		out[i] = m
+	}
+	return out
+}
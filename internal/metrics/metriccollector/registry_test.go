@@ -0,0 +1,195 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+package metriccollector
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aaronlmathis/gosight-agent/internal/config"
+	"github.com/aaronlmathis/gosight-shared/model"
+)
+
+func TestRegistryReload(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Agent.MetricCollection.Interval = 10 * time.Second
+	cfg.Agent.MetricCollection.Sources = []string{"mem", "swaps"}
+
+	reg, err := NewRegistry(cfg)
+	if err != nil {
+		t.Fatalf("NewRegistry: %v", err)
+	}
+	mem := reg.Active()["mem"]
+
+	cfg.Agent.MetricCollection.Sources = []string{"mem", "host"}
+	cfg.Agent.MetricCollection.CollectorIntervals = map[string]time.Duration{"host": 5 * time.Second}
+	if err := reg.Reload(cfg); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	active := reg.Active()
+	if active["mem"] != mem {
+		t.Error("expected the mem collector to be kept across the reload")
+	}
+	if _, ok := active["swaps"]; ok {
+		t.Error("expected swaps to be removed")
+	}
+	if _, ok := active["host"]; !ok {
+		t.Error("expected host to be added")
+	}
+	if reg.TickInterval() != 5*time.Second {
+		t.Errorf("expected the reloaded schedule's tick of 5s, got %v", reg.TickInterval())
+	}
+
+	cfg.Agent.MetricCollection.Sources = []string{"mem", "mem"}
+	if err := reg.Reload(cfg); err == nil {
+		t.Fatal("expected an error for a duplicate source")
+	}
+	if _, ok := reg.Active()["host"]; !ok {
+		t.Error("expected a failed reload to leave the registry unchanged")
+	}
+}
+
+// statefulCollector keeps unlocked state between calls, like the container
+// collectors' previous stats.
+type statefulCollector struct {
+	name  string
+	calls map[int]int
+}
+
+func (c *statefulCollector) Name() string { return c.name }
+
+func (c *statefulCollector) Collect(_ context.Context) ([]model.Metric, error) {
+	c.calls[len(c.calls)]++
+	return []model.Metric{{Namespace: "Test", Name: c.name, Value: 1}}, nil
+}
+
+type bufferedTestCollector struct{ statefulCollector }
+
+func (c *bufferedTestCollector) Buffered() {}
+
+type statefulTestCollector struct{ statefulCollector }
+
+func (c *statefulTestCollector) Stateful() {}
+
+func TestCollectFromSerializedAndSkipsBuffered(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Agent.MetricCollection.Interval = 10 * time.Second
+
+	reg, err := NewRegistry(cfg)
+	if err != nil {
+		t.Fatalf("NewRegistry: %v", err)
+	}
+	buffered := &bufferedTestCollector{statefulCollector{name: "statsd", calls: map[int]int{}}}
+	reg.Collectors = map[string]MetricCollector{
+		"docker": &statefulCollector{name: "docker", calls: map[int]int{}},
+		"statsd": buffered,
+	}
+
+	// Run under -race: concurrent calls into a collector must not overlap
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			reg.Collect(context.Background())
+		}()
+		go func() {
+			defer wg.Done()
+			reg.CollectFrom(context.Background(), []string{"docker", "statsd"})
+		}()
+	}
+	wg.Wait()
+
+	if got := len(buffered.calls); got != 10 {
+		t.Errorf("expected the buffered collector to be called only by Collect (10 times), got %d", got)
+	}
+}
+
+func TestCollectFromSkipsStateful(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Agent.MetricCollection.Interval = 10 * time.Second
+
+	reg, err := NewRegistry(cfg)
+	if err != nil {
+		t.Fatalf("NewRegistry: %v", err)
+	}
+	reg.Collectors = map[string]MetricCollector{
+		"mem":    &statefulCollector{name: "mem", calls: map[int]int{}},
+		"docker": &statefulTestCollector{statefulCollector{name: "docker", calls: map[int]int{}}},
+	}
+
+	metrics := reg.CollectFrom(context.Background(), []string{"mem", "docker"})
+	if len(metrics) != 1 || metrics[0].Name != "mem" {
+		t.Errorf("expected only the stateless collector to run, got %+v", metrics)
+	}
+}
+
+func TestReloadStopsOTLPSocket(t *testing.T) {
+	// Unix socket paths are length limited, so stay out of t.TempDir
+	dir, err := os.MkdirTemp("", "gsotlp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "otlp.sock")
+
+	cfg := &config.Config{}
+	cfg.Agent.MetricCollection.Interval = 10 * time.Second
+	cfg.Agent.MetricCollection.Sources = []string{"mem", "otlp_socket"}
+	cfg.Agent.MetricCollection.OTLPSocketPath = path
+
+	reg, err := NewRegistry(cfg)
+	if err != nil {
+		t.Fatalf("NewRegistry: %v", err)
+	}
+	if _, err := reg.Collect(context.Background()); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if conn, err := net.Dial("unix", path); err == nil {
+			conn.Close()
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("OTLP socket receiver did not start listening")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	cfg.Agent.MetricCollection.Sources = []string{"mem"}
+	if err := reg.Reload(cfg); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if _, err := os.Lstat(path); !os.IsNotExist(err) {
+		t.Errorf("expected the socket to be removed, got %v", err)
+	}
+	if conn, err := net.Dial("unix", path); err == nil {
+		conn.Close()
+		t.Error("expected the listener to be closed after the collector was disabled")
+	}
+}
@@ -0,0 +1,108 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/collector/schedule.go
+// schedule.go - runs collectors with their own interval on the runner's ticks.
+
+package metriccollector
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// collectorSchedule decides which collectors are due on a tick when some of
+// them have their own interval (metric_collection.collector_intervals). The
+// runner ticks at the shortest interval; a collector runs on the first tick
+// at or after its previous run plus its interval, so intervals are rounded up
+// to whole ticks. It is used only from MetricRegistry.Collect and is not safe
+// for concurrent use.
+type collectorSchedule struct {
+	tick      time.Duration
+	interval  time.Duration // default for collectors without an override
+	overrides map[string]time.Duration
+	next      map[string]time.Time
+}
+
+// newCollectorSchedule validates overrides and builds a schedule around the
+// global interval.
+func newCollectorSchedule(interval time.Duration, overrides map[string]time.Duration) (*collectorSchedule, error) {
+	s := &collectorSchedule{
+		tick:      interval,
+		interval:  interval,
+		overrides: make(map[string]time.Duration, len(overrides)),
+		next:      make(map[string]time.Time),
+	}
+
+	var bad []string
+	for name, d := range overrides {
+		if d <= 0 {
+			bad = append(bad, fmt.Sprintf("%s: %v", name, d))
+			continue
+		}
+		s.overrides[name] = d
+		if s.tick <= 0 || d < s.tick {
+			s.tick = d
+		}
+	}
+	if len(bad) > 0 {
+		sort.Strings(bad)
+		return nil, fmt.Errorf("collector_intervals must be positive (%s)", strings.Join(bad, ", "))
+	}
+	return s, nil
+}
+
+// intervalOf returns the collection interval of the named collector.
+func (s *collectorSchedule) intervalOf(name string) time.Duration {
+	if d, ok := s.overrides[name]; ok {
+		return d
+	}
+	return s.interval
+}
+
+// longest returns the longest collection interval of any collector.
+func (s *collectorSchedule) longest() time.Duration {
+	longest := s.interval
+	for _, d := range s.overrides {
+		if d > longest {
+			longest = d
+		}
+	}
+	return longest
+}
+
+// due reports whether the collector should run on the tick at now. Collectors
+// running at the tick interval are due on every tick; others once now is
+// within half a tick of their next run, absorbing ticker jitter.
+func (s *collectorSchedule) due(name string, now time.Time) bool {
+	if s.intervalOf(name) <= s.tick {
+		return true
+	}
+	next, ok := s.next[name]
+	return !ok || !now.Before(next.Add(-s.tick/2))
+}
+
+// ran records that the collector ran at now.
+func (s *collectorSchedule) ran(name string, now time.Time) {
+	s.next[name] = now.Add(s.intervalOf(name))
+}
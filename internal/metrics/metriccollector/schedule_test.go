@@ -0,0 +1,59 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+package metriccollector
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCollectorScheduleOverrides(t *testing.T) {
+	s, err := newCollectorSchedule(10*time.Second, map[string]time.Duration{"cpu": 2 * time.Second, "disk": 30 * time.Second})
+	if err != nil {
+		t.Fatalf("newCollectorSchedule: %v", err)
+	}
+	if s.tick != 2*time.Second {
+		t.Fatalf("expected tick of the shortest interval, got %v", s.tick)
+	}
+
+	runs := map[string]int{}
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 30; i++ {
+		// Ticks arrive with a little jitter
+		now := start.Add(time.Duration(i)*2*time.Second + time.Duration(i%3)*10*time.Millisecond)
+		for _, name := range []string{"cpu", "mem", "disk"} {
+			if s.due(name, now) {
+				s.ran(name, now)
+				runs[name]++
+			}
+		}
+	}
+
+	// 60 seconds of ticks
+	if runs["cpu"] != 30 || runs["mem"] != 6 || runs["disk"] != 2 {
+		t.Errorf("unexpected runs per collector: %v", runs)
+	}
+
+	if _, err := newCollectorSchedule(10*time.Second, map[string]time.Duration{"disk": 0}); err == nil {
+		t.Error("expected error for non-positive interval")
+	}
+}
@@ -0,0 +1,76 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/collector/self/connection.go
+// Package self provides collectors that report on the agent itself.
+// connection.go reports how stable the agent's link to the server is.
+
+package self
+
+import (
+	"context"
+	"time"
+
+	grpcconn "github.com/aaronlmathis/gosight-agent/internal/grpc"
+	agentutils "github.com/aaronlmathis/gosight-agent/internal/utils"
+	"github.com/aaronlmathis/gosight-shared/model"
+)
+
+type ConnectionCollector struct{}
+
+// NewConnectionCollector creates a new ConnectionCollector instance.
+func NewConnectionCollector() *ConnectionCollector {
+	return &ConnectionCollector{}
+}
+
+// Name returns the name of the collector.
+// This is used to identify the collector in logs and metrics.
+func (c *ConnectionCollector) Name() string {
+	return "agent"
+}
+
+// Collect emits Agent reconnect_attempts_total (failed connection attempts by
+// any sender since start), connected (1 if the shared connection is ready),
+// connection_state (the gRPC connectivity state as a number, with its name in
+// the state dimension; -1 and NONE without a connection) and
+// connection_redials_total (connections torn down after staying in
+// TRANSIENT_FAILURE beyond agent.reconnect_after).
+// Values gathered while offline are delivered with the first cycle after
+// reconnecting, so a flapping link shows up as a rising counter with few gaps.
+func (c *ConnectionCollector) Collect(_ context.Context) ([]model.Metric, error) {
+	now := time.Now()
+	connected := 0
+	if grpcconn.Connected() {
+		connected = 1
+	}
+
+	stateValue, stateName := -1, "NONE"
+	if state, ok := grpcconn.ConnState(); ok {
+		stateValue, stateName = int(state), state.String()
+	}
+
+	return []model.Metric{
+		agentutils.Metric("Agent", "", "reconnect_attempts_total", grpcconn.ReconnectAttempts(), "counter", "count", nil, now),
+		agentutils.Metric("Agent", "", "connected", connected, "gauge", "bool", nil, now),
+		agentutils.Metric("Agent", "", "connection_state", stateValue, "gauge", "state", map[string]string{"state": stateName}, now),
+		agentutils.Metric("Agent", "", "connection_redials_total", grpcconn.HealthRedials(), "counter", "count", nil, now),
+	}, nil
+}
@@ -0,0 +1,77 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/collector/self/cycles.go
+// cycles.go reports how long the agent's collection cycles take.
+
+package self
+
+import (
+	"context"
+	"time"
+
+	"github.com/aaronlmathis/gosight-agent/internal/selfmetrics"
+	agentutils "github.com/aaronlmathis/gosight-agent/internal/utils"
+	"github.com/aaronlmathis/gosight-shared/model"
+)
+
+type CycleCollector struct{}
+
+// NewCycleCollector creates a new CycleCollector instance.
+func NewCycleCollector() *CycleCollector {
+	return &CycleCollector{}
+}
+
+// Stateful marks the collector as draining the cycle duration windows on every call.
+func (c *CycleCollector) Stateful() {}
+
+// Name returns the name of the collector.
+// This is used to identify the collector in logs and metrics.
+func (c *CycleCollector) Name() string {
+	return "agent_cycles"
+}
+
+// Collect emits, per runner (dimension "runner": metrics, logs, processes),
+// Agent collect_cycle_duration_ms (duration of the latest collection cycle,
+// from collecting to queuing the payloads; the export itself runs in the
+// sender workers and is not included) and cycle_overruns_total (cycles that
+// exceeded the deadline since start), plus collect_cycle_duration_window_ms
+// carrying the min/max/sum/count of all cycles since the previous collection
+// as StatisticValues, with their mean as value.
+func (c *CycleCollector) Collect(_ context.Context) ([]model.Metric, error) {
+	now := time.Now()
+
+	var metrics []model.Metric
+	for runner, st := range selfmetrics.CycleDurations() {
+		dims := map[string]string{"runner": runner}
+		ms := float64(st.LastDuration) / float64(time.Millisecond)
+		metrics = append(metrics,
+			agentutils.Metric("Agent", "", "collect_cycle_duration_ms", ms, "gauge", "ms", dims, now),
+			agentutils.Metric("Agent", "", "cycle_overruns_total", st.Overruns, "counter", "count", dims, now),
+		)
+	}
+	for runner, stats := range selfmetrics.TakeCycleWindows() {
+		dims := map[string]string{"runner": runner}
+		metrics = append(metrics,
+			agentutils.SummaryMetric("Agent", "", "collect_cycle_duration_window_ms", stats, "ms", dims, now))
+	}
+	return metrics, nil
+}
@@ -0,0 +1,69 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/collector/self/exports.go
+// exports.go reports the size of the OTLP/stream requests the agent sends.
+
+package self
+
+import (
+	"context"
+	"time"
+
+	"github.com/aaronlmathis/gosight-agent/internal/selfmetrics"
+	agentutils "github.com/aaronlmathis/gosight-agent/internal/utils"
+	"github.com/aaronlmathis/gosight-shared/model"
+)
+
+type ExportSizeCollector struct{}
+
+// NewExportSizeCollector creates a new ExportSizeCollector instance.
+func NewExportSizeCollector() *ExportSizeCollector {
+	return &ExportSizeCollector{}
+}
+
+// Stateful marks the collector as draining the export size stats on every call.
+func (c *ExportSizeCollector) Stateful() {}
+
+// Name returns the name of the collector.
+// This is used to identify the collector in logs and metrics.
+func (c *ExportSizeCollector) Name() string {
+	return "agent_exports"
+}
+
+// Collect emits, per signal (dimension "signal": metrics, logs, processes),
+// Agent export_payload_bytes (mean serialized request size), export_payload_bytes_max
+// and export_requests for the requests sent since the previous collection.
+// Signals that sent nothing in the period are omitted.
+func (c *ExportSizeCollector) Collect(_ context.Context) ([]model.Metric, error) {
+	now := time.Now()
+
+	var metrics []model.Metric
+	for signal, st := range selfmetrics.TakeExportSizes() {
+		dims := map[string]string{"signal": signal}
+		metrics = append(metrics,
+			agentutils.Metric("Agent", "", "export_payload_bytes", st.AvgBytes(), "gauge", "bytes", dims, now),
+			agentutils.Metric("Agent", "", "export_payload_bytes_max", st.MaxBytes, "gauge", "bytes", dims, now),
+			agentutils.Metric("Agent", "", "export_requests", st.Count, "gauge", "count", dims, now),
+		)
+	}
+	return metrics, nil
+}
@@ -0,0 +1,103 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/collector/self/pipeline.go
+// pipeline.go reports the health of the agent's own collect -> queue -> send pipeline.
+
+package self
+
+import (
+	"context"
+	"runtime"
+	"time"
+
+	"github.com/aaronlmathis/gosight-agent/internal/selfmetrics"
+	agentutils "github.com/aaronlmathis/gosight-agent/internal/utils"
+	"github.com/aaronlmathis/gosight-shared/model"
+)
+
+type PipelineCollector struct{}
+
+// NewPipelineCollector creates a new PipelineCollector instance.
+func NewPipelineCollector() *PipelineCollector {
+	return &PipelineCollector{}
+}
+
+// Name returns the name of the collector.
+// This is used to identify the collector in logs and metrics.
+func (c *PipelineCollector) Name() string {
+	return "agent_pipeline"
+}
+
+// Collect emits, under namespace Agent:
+//   - metrics_collected_total: metrics collected since start.
+//   - collection_duration_seconds: latest run time per collector (dimension "collector").
+//   - task_queue_length / task_queue_capacity per runner (dimension "runner").
+//   - task_queue_dropped_total: items dropped on a full queue per runner.
+//   - send_errors_total: failed sends per signal (dimension "signal").
+//   - spool_records, spool_evicted_total, spool_expired_total,
+//     spool_quarantined_total and spool_replayed_total per signal when the
+//     disk spool is enabled.
+//   - goroutines, heap_alloc_bytes, heap_sys_bytes and gc_runs_total from the Go runtime.
+func (c *PipelineCollector) Collect(_ context.Context) ([]model.Metric, error) {
+	now := time.Now()
+	st := selfmetrics.Pipeline()
+
+	metrics := []model.Metric{
+		agentutils.Metric("Agent", "", "metrics_collected_total", st.MetricsCollected, "counter", "count", nil, now),
+	}
+	for collector, d := range st.CollectorDurations {
+		dims := map[string]string{"collector": collector}
+		metrics = append(metrics, agentutils.Metric("Agent", "", "collection_duration_seconds", d.Seconds(), "gauge", "seconds", dims, now))
+	}
+	for runner, q := range st.Queues {
+		dims := map[string]string{"runner": runner}
+		metrics = append(metrics,
+			agentutils.Metric("Agent", "", "task_queue_length", q.Length, "gauge", "count", dims, now),
+			agentutils.Metric("Agent", "", "task_queue_capacity", q.Capacity, "gauge", "count", dims, now),
+			agentutils.Metric("Agent", "", "task_queue_dropped_total", st.QueueDropped[runner], "counter", "count", dims, now),
+		)
+	}
+	for signal, n := range st.SendErrors {
+		dims := map[string]string{"signal": signal}
+		metrics = append(metrics, agentutils.Metric("Agent", "", "send_errors_total", n, "counter", "count", dims, now))
+	}
+	for signal, sp := range st.Spools {
+		dims := map[string]string{"signal": signal}
+		metrics = append(metrics,
+			agentutils.Metric("Agent", "", "spool_records", sp.Pending, "gauge", "count", dims, now),
+			agentutils.Metric("Agent", "", "spool_evicted_total", sp.Evicted, "counter", "count", dims, now),
+			agentutils.Metric("Agent", "", "spool_expired_total", sp.Expired, "counter", "count", dims, now),
+			agentutils.Metric("Agent", "", "spool_quarantined_total", sp.Quarantined, "counter", "count", dims, now),
+			agentutils.Metric("Agent", "", "spool_replayed_total", sp.Replayed, "counter", "count", dims, now),
+		)
+	}
+
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	metrics = append(metrics,
+		agentutils.Metric("Agent", "", "goroutines", runtime.NumGoroutine(), "gauge", "count", nil, now),
+		agentutils.Metric("Agent", "", "heap_alloc_bytes", ms.HeapAlloc, "gauge", "bytes", nil, now),
+		agentutils.Metric("Agent", "", "heap_sys_bytes", ms.HeapSys, "gauge", "bytes", nil, now),
+		agentutils.Metric("Agent", "", "gc_runs_total", ms.NumGC, "counter", "count", nil, now),
+	)
+	return metrics, nil
+}
@@ -0,0 +1,164 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/collector/system/btrfs.go
+// Package system provides collectors for system hardware (CPU/RAM/DISK/ETC)
+// btrfs.go collects Btrfs filesystem health and usage metrics.
+// It reads /sys/fs/btrfs and stays silent if no Btrfs filesystems are mounted.
+
+package system
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	agentutils "github.com/aaronlmathis/gosight-agent/internal/utils"
+	"github.com/aaronlmathis/gosight-shared/model"
+)
+
+// btrfsSysPath is the sysfs root for mounted Btrfs filesystems.
+const btrfsSysPath = "/sys/fs/btrfs"
+
+type BtrfsCollector struct{}
+
+// NewBtrfsCollector creates a new BtrfsCollector instance.
+func NewBtrfsCollector() *BtrfsCollector {
+	return &BtrfsCollector{}
+}
+
+// Name returns the name of the collector.
+// This is used to identify the collector in logs and metrics.
+func (c *BtrfsCollector) Name() string {
+	return "btrfs"
+}
+
+// Collect gathers device size, chunk allocation, data/metadata usage, device
+// error counts, and a health gauge for every mounted Btrfs filesystem.
+// A filesystem is reported DEGRADED if any device is missing or has errors.
+func (c *BtrfsCollector) Collect(_ context.Context) ([]model.Metric, error) {
+	entries, err := os.ReadDir(btrfsSysPath)
+	if err != nil {
+		return nil, nil
+	}
+
+	var metrics []model.Metric
+	now := time.Now()
+
+	for _, e := range entries {
+		fsDir := filepath.Join(btrfsSysPath, e.Name())
+		// Skip non-filesystem entries such as "features"
+		if _, err := os.Stat(filepath.Join(fsDir, "allocation")); err != nil {
+			continue
+		}
+
+		label := readSysString(filepath.Join(fsDir, "label"))
+		dims := map[string]string{"fsid": e.Name(), "label": label}
+
+		// Total device capacity (sizes are in 512-byte sectors)
+		var size uint64
+		if devs, err := os.ReadDir(filepath.Join(fsDir, "devices")); err == nil {
+			for _, d := range devs {
+				size += readSysUint(filepath.Join(fsDir, "devices", d.Name(), "size")) * 512
+			}
+		}
+
+		var allocated uint64
+		for _, kind := range []string{"data", "metadata", "system"} {
+			allocDir := filepath.Join(fsDir, "allocation", kind)
+			allocated += readSysUint(filepath.Join(allocDir, "disk_total"))
+			if kind == "system" {
+				continue
+			}
+			metrics = append(metrics,
+				agentutils.Metric("System", "Btrfs", kind+"_total", readSysUint(filepath.Join(allocDir, "total_bytes")), "gauge", "bytes", dims, now),
+				agentutils.Metric("System", "Btrfs", kind+"_used", readSysUint(filepath.Join(allocDir, "bytes_used")), "gauge", "bytes", dims, now),
+			)
+		}
+
+		// Device health from devinfo (missing flag and error_stats counters)
+		var missing, errorCount uint64
+		if devs, err := os.ReadDir(filepath.Join(fsDir, "devinfo")); err == nil {
+			for _, d := range devs {
+				devDir := filepath.Join(fsDir, "devinfo", d.Name())
+				missing += readSysUint(filepath.Join(devDir, "missing"))
+				errorCount += sumErrorStats(filepath.Join(devDir, "error_stats"))
+			}
+		}
+
+		health := "ONLINE"
+		if missing > 0 || errorCount > 0 {
+			health = "DEGRADED"
+		}
+		healthDims := map[string]string{"fsid": e.Name(), "label": label, "health": strings.ToLower(health)}
+
+		metrics = append(metrics,
+			agentutils.Metric("System", "Btrfs", "size", size, "gauge", "bytes", dims, now),
+			agentutils.Metric("System", "Btrfs", "allocated", allocated, "gauge", "bytes", dims, now),
+			agentutils.Metric("System", "Btrfs", "missing_devices", missing, "gauge", "count", dims, now),
+			agentutils.Metric("System", "Btrfs", "device_errors", errorCount, "counter", "count", dims, now),
+			agentutils.Metric("System", "Btrfs", "health", healthValue(health), "gauge", "state", healthDims, now),
+		)
+	}
+
+	return metrics, nil
+}
+
+// sumErrorStats sums the counters in a devinfo error_stats file
+// ("write_errs 0\nread_errs 0\n...").
+func sumErrorStats(path string) uint64 {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	var total uint64
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		if v, err := strconv.ParseUint(fields[1], 10, 64); err == nil {
+			total += v
+		}
+	}
+	return total
+}
+
+// readSysString reads a sysfs attribute and trims surrounding whitespace.
+func readSysString(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// readSysUint reads a numeric sysfs attribute, returning 0 on any error.
+func readSysUint(path string) uint64 {
+	v, err := strconv.ParseUint(readSysString(path), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return v
+}
@@ -0,0 +1,119 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/collector/system/conn.go
+// Package system provides collectors for system hardware (CPU/RAM/DISK/ETC)
+// conn.go counts TCP and UDP sockets by state via gopsutil.
+
+package system
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	agentutils "github.com/aaronlmathis/gosight-agent/internal/utils"
+	"github.com/aaronlmathis/gosight-shared/model"
+	"github.com/aaronlmathis/gosight-shared/utils"
+	"github.com/shirou/gopsutil/v4/net"
+)
+
+// connProtocols are the gopsutil connection kinds counted, each covering IPv4 and IPv6.
+var connProtocols = []string{"tcp", "udp"}
+
+type ConnCollector struct {
+	warned map[string]bool // protocols whose enumeration failure was already logged
+}
+
+// NewConnCollector creates a new ConnCollector instance.
+// Enumerating every socket is expensive on busy hosts, so it is usually given
+// a longer interval through metric_collection.collector_intervals.
+func NewConnCollector() *ConnCollector {
+	return &ConnCollector{warned: make(map[string]bool)}
+}
+
+// Name returns the name of the collector.
+// This is used to identify the collector in logs and metrics.
+func (c *ConnCollector) Name() string {
+	return "conn"
+}
+
+// Collect emits System.Network conn_count per protocol (tcp/udp) and state
+// (ESTABLISHED, TIME_WAIT, LISTEN, ...; NONE for stateless UDP sockets) and
+// conn_total per protocol. If a protocol cannot be enumerated (e.g. /proc is
+// not readable) a warning is logged once and the other protocol is still
+// reported; an error is only returned when nothing could be counted.
+func (c *ConnCollector) Collect(ctx context.Context) ([]model.Metric, error) {
+	now := time.Now()
+
+	var (
+		metrics []model.Metric
+		lastErr error
+		ok      int
+	)
+	for _, proto := range connProtocols {
+		conns, err := net.ConnectionsWithoutUidsWithContext(ctx, proto)
+		if err != nil {
+			lastErr = err
+			if !c.warned[proto] {
+				utils.Warn("Unable to enumerate %s connections (reporting partial data): %v", proto, err)
+				c.warned[proto] = true
+			}
+			if len(conns) == 0 {
+				continue
+			}
+		} else {
+			c.warned[proto] = false
+		}
+		ok++
+
+		counts := countConnStates(conns)
+		states := make([]string, 0, len(counts))
+		for state := range counts {
+			states = append(states, state)
+		}
+		sort.Strings(states)
+		for _, state := range states {
+			dims := map[string]string{"protocol": proto, "state": state}
+			metrics = append(metrics, agentutils.Metric("System", "Network", "conn_count", counts[state], "gauge", "count", dims, now))
+		}
+		dims := map[string]string{"protocol": proto}
+		metrics = append(metrics, agentutils.Metric("System", "Network", "conn_total", len(conns), "gauge", "count", dims, now))
+	}
+
+	if ok == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+	return metrics, nil
+}
+
+// countConnStates counts connections by state; an empty state (UDP) is counted as NONE.
+func countConnStates(conns []net.ConnectionStat) map[string]int {
+	counts := make(map[string]int)
+	for _, conn := range conns {
+		state := conn.Status
+		if state == "" {
+			state = "NONE"
+		}
+		counts[state]++
+	}
+	return counts
+}
@@ -31,6 +31,7 @@ import (
 	"strconv"
 	"time"
 
+	agentutils "github.com/aaronlmathis/gosight-agent/internal/utils"
 	"github.com/aaronlmathis/gosight-shared/model"
 	"github.com/shirou/gopsutil/v4/cpu"
 	"github.com/shirou/gopsutil/v4/load"
@@ -40,17 +41,48 @@ import (
 // It implements the Collector interface and is used to gather CPU usage,
 // times, and information about the CPU cores.
 type CPUCollector struct {
-	interval time.Duration
+	interval        time.Duration
+	perCoreMaxCores int  // 0 = always emit per-core metrics
+	distinctTotal   bool // name the total usage_percent_total
 }
 
 // NewCPUCollector creates a new CPUCollector instance.
 // It initializes the collector with a specified interval for collecting metrics.
 // If the interval is less than or equal to zero, it defaults to 2 seconds.
-func NewCPUCollector(interval time.Duration) *CPUCollector {
+// If perCoreMaxCores is positive and the machine has more logical cores than
+// that, per-core metrics are skipped and only aggregates are emitted.
+// If distinctTotal is set, total usage is emitted as usage_percent_total
+// instead of sharing usage_percent with the per-core series.
+func NewCPUCollector(interval time.Duration, perCoreMaxCores int, distinctTotal bool) *CPUCollector {
 	if interval <= 0 {
 		interval = 2 * time.Second
 	}
-	return &CPUCollector{interval: interval}
+	return &CPUCollector{interval: interval, perCoreMaxCores: perCoreMaxCores, distinctTotal: distinctTotal}
+}
+
+// totalUsageName returns the metric name used for total CPU usage.
+func (c *CPUCollector) totalUsageName() string {
+	if c.distinctTotal {
+		return "usage_percent_total"
+	}
+	return "usage_percent"
+}
+
+// percentPerCore returns per-core usage, or nothing when per-core metrics are disabled.
+func (c *CPUCollector) percentPerCore(ctx context.Context, enabled bool) ([]float64, error) {
+	if !enabled {
+		return nil, nil
+	}
+	return cpu.PercentWithContext(ctx, c.interval, true)
+}
+
+// perCoreEnabled reports whether per-core metrics should be emitted.
+func (c *CPUCollector) perCoreEnabled(ctx context.Context) bool {
+	if c.perCoreMaxCores <= 0 {
+		return true
+	}
+	count, err := cpu.CountsWithContext(ctx, true)
+	return err != nil || count <= c.perCoreMaxCores
 }
 
 // Name returns the name of the collector.
@@ -70,15 +102,17 @@ func (c *CPUCollector) Name() string {
 func (c *CPUCollector) Collect(ctx context.Context) ([]model.Metric, error) {
 	var metrics []model.Metric
 	now := time.Now()
+	perCore := c.perCoreEnabled(ctx)
 
-	// Per-core usage
-	if percentPerCore, err := cpu.PercentWithContext(ctx, c.interval, true); err == nil {
+	// Per-core usage (skipped above cpu_per_core_max_cores)
+	if percentPerCore, err := c.percentPerCore(ctx, perCore); err == nil {
+		ts := agentutils.RateTimestamp(now, time.Now())
 		for i, val := range percentPerCore {
 			metrics = append(metrics, model.Metric{
 				Namespace:    "System",
 				SubNamespace: "CPU",
 				Name:         "usage_percent",
-				Timestamp:    now,
+				Timestamp:    ts,
 				Value:        val,
 				Type:         "gauge",
 				Unit:         "percent",
@@ -90,13 +124,14 @@ func (c *CPUCollector) Collect(ctx context.Context) ([]model.Metric, error) {
 		}
 	}
 
-	// Total CPU usage
+	// Total CPU usage, measured over the following interval
+	totalStart := time.Now()
 	if percentTotal, err := cpu.PercentWithContext(ctx, c.interval, false); err == nil && len(percentTotal) > 0 {
 		metrics = append(metrics, model.Metric{
 			Namespace:    "System",
 			SubNamespace: "CPU",
-			Name:         "usage_percent",
-			Timestamp:    now,
+			Name:         c.totalUsageName(),
+			Timestamp:    agentutils.RateTimestamp(totalStart, time.Now()),
 			Value:        percentTotal[0],
 			Type:         "gauge",
 			Unit:         "percent",
@@ -136,8 +171,11 @@ func (c *CPUCollector) Collect(ctx context.Context) ([]model.Metric, error) {
 		}
 	}
 
-	// CPU Info: Clock speed per core
+	// CPU Info: Clock speed per core (only the first core above cpu_per_core_max_cores)
 	if info, err := cpu.InfoWithContext(ctx); err == nil && len(info) > 0 {
+		if !perCore {
+			info = info[:1]
+		}
 		for i, cpu := range info {
 			metrics = append(metrics, model.Metric{
 				Namespace:    "System",
@@ -28,27 +28,63 @@ package system
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"path"
 	"runtime"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/aaronlmathis/gosight-agent/internal/config"
 	agentutils "github.com/aaronlmathis/gosight-agent/internal/utils"
 	"github.com/aaronlmathis/gosight-shared/model"
+	"github.com/aaronlmathis/gosight-shared/utils"
 	"github.com/shirou/gopsutil/v4/disk"
 )
 
-type DiskCollector struct{}
+// Partitions skipped when disk.exclude_fstypes / disk.exclude_mountpoints are not set
+var (
+	defaultExcludeFSTypes     = []string{"tmpfs", "devtmpfs", "overlay", "squashfs"}
+	defaultExcludeMountpoints = []string{"/sys", "/proc", "/run"}
+)
+
+const defaultDiskUsageTimeout = 5 * time.Second
+
+type DiskCollector struct {
+	excludeFSTypes     []string
+	excludeMountpoints []string
+	usageTimeout       time.Duration
+
+	mu   sync.Mutex
+	hung map[string]bool // mountpoints whose usage call has not returned yet
+}
 
 // NewDiskCollector creates a new DiskCollector instance.
 // It uses the gopsutil library to gather disk metrics.
-// The collector is platform-neutral and will skip virtual, pseudo, and temp filesystems on Linux/macOS.
-// On Windows, it will skip reserved, empty, mapped, and unmounted drives.
+// Partitions whose filesystem type or mountpoint matches the configured exclude globs are skipped
+// (by default tmpfs, devtmpfs, overlay and squashfs, and anything mounted under /sys, /proc or /run).
+// On Windows, it will also skip reserved, empty, mapped, and unmounted drives.
 // The collector gathers metrics such as total, used, free space, used percentage, inodes total, used, free, and used percentage.
 // It also collects disk I/O metrics such as read/write counts, bytes, time, and merged counts.
 // The metrics are returned as a slice of model.Metric.
-func NewDiskCollector() *DiskCollector {
-	return &DiskCollector{}
+func NewDiskCollector(cfg config.DiskConfig) *DiskCollector {
+	c := &DiskCollector{
+		excludeFSTypes:     cfg.ExcludeFSTypes,
+		excludeMountpoints: cfg.ExcludeMountpoints,
+		usageTimeout:       cfg.UsageTimeout,
+		hung:               make(map[string]bool),
+	}
+	if c.excludeFSTypes == nil {
+		c.excludeFSTypes = defaultExcludeFSTypes
+	}
+	if c.excludeMountpoints == nil {
+		c.excludeMountpoints = defaultExcludeMountpoints
+	}
+	if c.usageTimeout <= 0 {
+		c.usageTimeout = defaultDiskUsageTimeout
+	}
+	return c
 }
 
 // Name returns the name of the collector.
@@ -59,36 +95,25 @@ func (c *DiskCollector) Name() string {
 
 // Collect gathers disk metrics using the gopsutil library.
 // It retrieves information about disk partitions, usage, and I/O statistics.
-// The metrics are filtered based on the platform (Linux/macOS/Windows) to exclude virtual, pseudo, and temp filesystems.
+// Excluded partitions are filtered out before their usage is read. A partition whose usage
+// doesn't return within the usage timeout (e.g. a hung NFS mount) is reported as usage_error
+// instead of stalling the collector.
 // The function returns a slice of model.Metric containing the collected metrics.
-func (c *DiskCollector) Collect(_ context.Context) ([]model.Metric, error) {
+func (c *DiskCollector) Collect(ctx context.Context) ([]model.Metric, error) {
 	var metrics []model.Metric
 	now := time.Now()
 
-	partitions, err := disk.Partitions(false)
+	partitions, err := disk.PartitionsWithContext(ctx, false)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get disk partitions: %w", err)
 	}
 
 	for _, p := range partitions {
-		// Platform-neutral filtering
-		if runtime.GOOS != "windows" {
-			// Linux/macOS: skip virtual, pseudo, temp filesystems
-			if strings.HasPrefix(p.Mountpoint, "/sys") ||
-				strings.HasPrefix(p.Mountpoint, "/proc") ||
-				strings.HasPrefix(p.Mountpoint, "/run") ||
-				p.Fstype == "tmpfs" || p.Fstype == "devtmpfs" || p.Fstype == "overlay" {
-				continue
-			}
-		} else {
-			// Windows: skip reserved/empty/mapped/unmounted drives
-			if p.Fstype == "" || p.Mountpoint == "" {
-				continue
-			}
+		// Windows: skip reserved/empty/mapped/unmounted drives
+		if runtime.GOOS == "windows" && (p.Fstype == "" || p.Mountpoint == "") {
+			continue
 		}
-
-		usage, err := disk.Usage(p.Mountpoint)
-		if err != nil || usage == nil {
+		if matchAnyGlob(c.excludeFSTypes, p.Fstype) || c.excludedMountpoint(p.Mountpoint) {
 			continue
 		}
 
@@ -98,6 +123,15 @@ func (c *DiskCollector) Collect(_ context.Context) ([]model.Metric, error) {
 			"fstype":     p.Fstype,
 		}
 
+		usage, err := c.usage(ctx, p.Mountpoint)
+		if errors.Is(err, context.DeadlineExceeded) {
+			metrics = append(metrics, agentutils.Metric("System", "Disk", "usage_error", 1, "gauge", "count", dims, now))
+			continue
+		}
+		if err != nil || usage == nil {
+			continue
+		}
+
 		metrics = append(metrics,
 			agentutils.Metric("System", "Disk", "total", usage.Total, "gauge", "bytes", dims, now),
 			agentutils.Metric("System", "Disk", "used", usage.Used, "gauge", "bytes", dims, now),
@@ -135,3 +169,66 @@ func (c *DiskCollector) Collect(_ context.Context) ([]model.Metric, error) {
 
 	return metrics, nil
 }
+
+// usage reads a partition's usage, giving up after the usage timeout. statfs
+// on a dead network mount blocks regardless of the context, so the call runs
+// in its own goroutine; while it stays blocked the mountpoint is not queried
+// again and keeps reporting a timeout.
+func (c *DiskCollector) usage(ctx context.Context, mountpoint string) (*disk.UsageStat, error) {
+	c.mu.Lock()
+	if c.hung[mountpoint] {
+		c.mu.Unlock()
+		return nil, context.DeadlineExceeded
+	}
+	c.hung[mountpoint] = true
+	c.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, c.usageTimeout)
+	defer cancel()
+
+	type result struct {
+		usage *disk.UsageStat
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		u, err := disk.UsageWithContext(ctx, mountpoint)
+		c.mu.Lock()
+		delete(c.hung, mountpoint)
+		c.mu.Unlock()
+		done <- result{u, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.usage, r.err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			utils.Warn("Disk usage of %s did not return within %v; reporting usage_error until it does", mountpoint, c.usageTimeout)
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// excludedMountpoint reports whether mountpoint, or a directory it is mounted
+// below, matches an exclude glob, so "/run" also covers "/run/user/1000".
+func (c *DiskCollector) excludedMountpoint(mountpoint string) bool {
+	if matchAnyGlob(c.excludeMountpoints, mountpoint) {
+		return true
+	}
+	for dir := path.Dir(mountpoint); dir != "/" && dir != "."; dir = path.Dir(dir) {
+		if matchAnyGlob(c.excludeMountpoints, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchAnyGlob(patterns []string, s string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, s); ok {
+			return true
+		}
+	}
+	return false
+}
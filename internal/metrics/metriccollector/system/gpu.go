@@ -0,0 +1,121 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/collector/system/gpu.go
+// gpu.go collects NVIDIA GPU utilization, memory, temperature and power.
+// It shells out to nvidia-smi and stays silent if it is absent.
+
+package system
+
+import (
+	"context"
+	"encoding/csv"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	agentutils "github.com/aaronlmathis/gosight-agent/internal/utils"
+	"github.com/aaronlmathis/gosight-shared/model"
+	"github.com/aaronlmathis/gosight-shared/utils"
+)
+
+// gpuCommandTimeout bounds every nvidia-smi invocation.
+const gpuCommandTimeout = 10 * time.Second
+
+// gpuQueryFields are the nvidia-smi --query-gpu fields, in output order.
+var gpuQueryFields = []string{
+	"index", "uuid", "name",
+	"utilization.gpu", "memory.used", "memory.total", "temperature.gpu", "power.draw",
+}
+
+type GPUCollector struct{}
+
+// NewGPUCollector creates a new GPUCollector instance.
+func NewGPUCollector() *GPUCollector {
+	return &GPUCollector{}
+}
+
+// Name returns the name of the collector.
+// This is used to identify the collector in logs and metrics.
+func (c *GPUCollector) Name() string {
+	return "gpu"
+}
+
+// Collect reports, per NVIDIA GPU (dimensions gpu (index), uuid and name),
+// utilization_percent, mem_used_bytes, mem_total_bytes, temperature_c and
+// power_watts. Values the device does not support are omitted.
+// If nvidia-smi is not installed or finds no GPU it returns no metrics and no error.
+func (c *GPUCollector) Collect(ctx context.Context) ([]model.Metric, error) {
+	if _, err := exec.LookPath("nvidia-smi"); err != nil {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, gpuCommandTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "nvidia-smi",
+		"--query-gpu="+strings.Join(gpuQueryFields, ","), "--format=csv,noheader,nounits").Output()
+	if err != nil {
+		// nvidia-smi exits non-zero when no GPU or driver is present
+		utils.Debug("nvidia-smi failed: %v", err)
+		return nil, nil
+	}
+
+	records, err := csv.NewReader(strings.NewReader(string(out))).ReadAll()
+	if err != nil {
+		utils.Debug("Failed to parse nvidia-smi output: %v", err)
+		return nil, nil
+	}
+
+	var metrics []model.Metric
+	now := time.Now()
+	for _, rec := range records {
+		if len(rec) < len(gpuQueryFields) {
+			continue
+		}
+		for i := range rec {
+			rec[i] = strings.TrimSpace(rec[i])
+		}
+		dims := map[string]string{"gpu": rec[0], "uuid": rec[1], "name": rec[2]}
+
+		add := func(name, raw string, scale float64, unit string) {
+			if v, ok := parseGPUValue(raw); ok {
+				metrics = append(metrics, agentutils.Metric("System", "GPU", name, v*scale, "gauge", unit, dims, now))
+			}
+		}
+		add("utilization_percent", rec[3], 1, "percent")
+		add("mem_used_bytes", rec[4], 1024*1024, "bytes") // MiB
+		add("mem_total_bytes", rec[5], 1024*1024, "bytes")
+		add("temperature_c", rec[6], 1, "celsius")
+		add("power_watts", rec[7], 1, "watts")
+	}
+	return metrics, nil
+}
+
+// parseGPUValue parses a numeric nvidia-smi field. Placeholders such as
+// "[N/A]" and "[Not Supported]" report false.
+func parseGPUValue(s string) (float64, bool) {
+	v, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
@@ -0,0 +1,184 @@
+//go:build linux
+
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/collector/system/mdstat_linux.go
+// Package system provides collectors for system hardware (CPU/RAM/DISK/ETC)
+// mdstat_linux.go collects Linux software RAID (md) array state from /proc/mdstat.
+
+package system
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	agentutils "github.com/aaronlmathis/gosight-agent/internal/utils"
+	"github.com/aaronlmathis/gosight-shared/model"
+)
+
+const mdstatPath = "/proc/mdstat"
+
+var (
+	mdArrayRe    = regexp.MustCompile(`^(md\S*) : (\S+)(?: \([^)]*\))*(?: (raid\d+|linear|multipath|faulty))?`)
+	mdStatusRe   = regexp.MustCompile(`\[(\d+)/(\d+)\]`)
+	mdProgressRe = regexp.MustCompile(`(resync|recovery|reshape|check)\s*=\s*([\d.]+)%`)
+)
+
+// mdStates maps md array states to the value of the array_state gauge.
+// 0 is healthy; higher values are progressively worse.
+var mdStates = map[string]int{
+	"active":   0,
+	"check":    1,
+	"resync":   2,
+	"reshape":  3,
+	"recovery": 4,
+	"degraded": 5,
+	"inactive": 6,
+}
+
+// mdArray holds the parsed state of a single md array.
+type mdArray struct {
+	Name          string
+	Level         string
+	State         string // active, inactive, degraded, resync, recovery, reshape, check
+	Degraded      bool
+	FailedDevices int
+	SyncPercent   float64
+}
+
+type MDStatCollector struct{}
+
+// NewMDStatCollector creates a new MDStatCollector instance.
+func NewMDStatCollector() *MDStatCollector {
+	return &MDStatCollector{}
+}
+
+// Name returns the name of the collector.
+// This is used to identify the collector in logs and metrics.
+func (c *MDStatCollector) Name() string {
+	return "mdstat"
+}
+
+// Collect parses /proc/mdstat and emits array_degraded, failed_devices and
+// resync_percent per array, plus array_state labeled with the state. Only
+// array_state carries the state, so a resync does not start new series for
+// the other metrics. It returns nothing if no md arrays exist.
+func (c *MDStatCollector) Collect(_ context.Context) ([]model.Metric, error) {
+	f, err := os.Open(mdstatPath)
+	if err != nil {
+		return nil, nil
+	}
+	defer f.Close()
+
+	arrays := parseMDStat(bufio.NewScanner(f))
+	if len(arrays) == 0 {
+		return nil, nil
+	}
+
+	var metrics []model.Metric
+	now := time.Now()
+
+	for _, a := range arrays {
+		dims := map[string]string{"array": a.Name, "level": a.Level}
+		stateDims := map[string]string{"array": a.Name, "level": a.Level, "state": a.State}
+		degraded := 0
+		if a.Degraded {
+			degraded = 1
+		}
+		metrics = append(metrics,
+			agentutils.Metric("System", "MD", "array_degraded", degraded, "gauge", "bool", dims, now),
+			agentutils.Metric("System", "MD", "failed_devices", a.FailedDevices, "gauge", "count", dims, now),
+			agentutils.Metric("System", "MD", "resync_percent", a.SyncPercent, "gauge", "percent", dims, now),
+			agentutils.Metric("System", "MD", "array_state", mdStateValue(a.State), "gauge", "state", stateDims, now),
+		)
+	}
+
+	return metrics, nil
+}
+
+// parseMDStat parses the contents of /proc/mdstat. Arrays that are not
+// resyncing report a sync percentage of 100.
+func parseMDStat(scanner *bufio.Scanner) []mdArray {
+	var arrays []mdArray
+	var cur *mdArray
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if m := mdArrayRe.FindStringSubmatch(line); m != nil {
+			arrays = append(arrays, mdArray{
+				Name:          m[1],
+				State:         m[2],
+				Level:         m[3],
+				FailedDevices: strings.Count(line, "(F)"),
+				SyncPercent:   100,
+			})
+			cur = &arrays[len(arrays)-1]
+			continue
+		}
+		if cur == nil {
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			cur = nil
+			continue
+		}
+
+		if m := mdStatusRe.FindStringSubmatch(line); m != nil {
+			want, _ := strconv.Atoi(m[1])
+			have, _ := strconv.Atoi(m[2])
+			if have < want {
+				cur.Degraded = true
+				if cur.State == "active" {
+					cur.State = "degraded"
+				}
+			}
+		}
+		if m := mdProgressRe.FindStringSubmatch(line); m != nil {
+			if pct, err := strconv.ParseFloat(m[2], 64); err == nil {
+				cur.SyncPercent = pct
+			}
+			cur.State = m[1]
+		}
+	}
+
+	for i := range arrays {
+		if arrays[i].FailedDevices > 0 {
+			arrays[i].Degraded = true
+		}
+	}
+	return arrays
+}
+
+// mdStateValue converts an array state to the array_state gauge value.
+// Unknown states are reported as inactive.
+func mdStateValue(state string) int {
+	if v, ok := mdStates[state]; ok {
+		return v
+	}
+	return mdStates["inactive"]
+}
@@ -0,0 +1,98 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+
+package system
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+)
+
+func TestParseMDStat(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []mdArray
+	}{
+		{
+			name: "resync",
+			input: `Personalities : [raid1] [linear] [multipath] [raid0] [raid6] [raid5] [raid4] [raid10]
+md0 : active raid1 sdb1[1] sda1[0]
+      1953382464 blocks super 1.2 [2/2] [UU]
+      [==>..................]  resync = 12.6% (246412352/1953382464) finish=141.7min speed=200736K/sec
+      bitmap: 14/15 pages [56KB], 65536KB chunk
+
+unused devices: <none>
+`,
+			want: []mdArray{{Name: "md0", Level: "raid1", State: "resync", SyncPercent: 12.6}},
+		},
+		{
+			name: "degraded",
+			input: `Personalities : [raid6] [raid5] [raid4]
+md1 : active raid5 sdd1[3](F) sdc1[1] sdb1[0]
+      1953260544 blocks super 1.2 level 5, 512k chunk, algorithm 2 [3/2] [UU_]
+      bitmap: 2/8 pages [8KB], 65536KB chunk
+
+md2 : active (auto-read-only) raid1 sdf[1] sde[0]
+      976630336 blocks super 1.2 [2/1] [U_]
+      [=====>...............]  recovery = 27.5% (268740864/976630336) finish=57.1min speed=206474K/sec
+
+unused devices: <none>
+`,
+			want: []mdArray{
+				{Name: "md1", Level: "raid5", State: "degraded", Degraded: true, FailedDevices: 1, SyncPercent: 100},
+				{Name: "md2", Level: "raid1", State: "recovery", Degraded: true, SyncPercent: 27.5},
+			},
+		},
+		{
+			name: "inactive",
+			input: `Personalities :
+md127 : inactive sdb[1](S) sda[0](S)
+      3906764976 blocks super 1.2
+
+unused devices: <none>
+`,
+			want: []mdArray{{Name: "md127", State: "inactive", SyncPercent: 100}},
+		},
+		{
+			name: "no arrays",
+			input: `Personalities :
+unused devices: <none>
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseMDStat(bufio.NewScanner(strings.NewReader(tt.input)))
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d arrays, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("array %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
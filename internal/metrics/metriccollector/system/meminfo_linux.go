@@ -0,0 +1,119 @@
+//go:build linux
+
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/collector/system/meminfo_linux.go
+// Package system provides collectors for system hardware (CPU/RAM/DISK/ETC)
+// meminfo_linux.go collects hugepage and commit accounting from /proc/meminfo
+// that gopsutil's VirtualMemory does not expose.
+
+package system
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	agentutils "github.com/aaronlmathis/gosight-agent/internal/utils"
+	"github.com/aaronlmathis/gosight-shared/model"
+)
+
+const meminfoPath = "/proc/meminfo"
+
+// meminfoFields maps /proc/meminfo keys to the emitted metric name and unit.
+// Values reported in kB are converted to bytes.
+var meminfoFields = map[string]struct{ name, unit string }{
+	"HugePages_Total": {"hugepages_total", "count"},
+	"HugePages_Free":  {"hugepages_free", "count"},
+	"HugePages_Rsvd":  {"hugepages_reserved", "count"},
+	"HugePages_Surp":  {"hugepages_surplus", "count"},
+	"Hugepagesize":    {"hugepage_size", "bytes"},
+	"AnonHugePages":   {"anon_hugepages", "bytes"},
+	"Committed_AS":    {"committed_as", "bytes"},
+	"CommitLimit":     {"commit_limit", "bytes"},
+}
+
+type MemInfoCollector struct{}
+
+// NewMemInfoCollector creates a new MemInfoCollector instance.
+func NewMemInfoCollector() *MemInfoCollector {
+	return &MemInfoCollector{}
+}
+
+// Name returns the name of the collector.
+// This is used to identify the collector in logs and metrics.
+func (c *MemInfoCollector) Name() string {
+	return "meminfo"
+}
+
+// Collect reads /proc/meminfo and emits the hugepage and commit fields under
+// System/Memory with dimension source=meminfo.
+func (c *MemInfoCollector) Collect(_ context.Context) ([]model.Metric, error) {
+	f, err := os.Open(meminfoPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	values := parseMemInfo(bufio.NewScanner(f))
+
+	now := time.Now()
+	dims := map[string]string{"source": "meminfo"}
+	var metrics []model.Metric
+	for key, v := range values {
+		field := meminfoFields[key]
+		metrics = append(metrics, agentutils.Metric("System", "Memory", field.name, v, "gauge", field.unit, dims, now))
+	}
+	return metrics, nil
+}
+
+// parseMemInfo returns the known meminfo fields found by the scanner,
+// with kB values converted to bytes.
+func parseMemInfo(sc *bufio.Scanner) map[string]uint64 {
+	values := make(map[string]uint64)
+	for sc.Scan() {
+		key, rest, ok := strings.Cut(sc.Text(), ":")
+		if !ok {
+			continue
+		}
+		if _, known := meminfoFields[key]; !known {
+			continue
+		}
+
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			continue
+		}
+		v, err := strconv.ParseUint(fields[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		if len(fields) > 1 && fields[1] == "kB" {
+			v *= 1024
+		}
+		values[key] = v
+	}
+	return values
+}
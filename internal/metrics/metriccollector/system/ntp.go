@@ -0,0 +1,181 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/collector/system/ntp.go
+// Package system provides collectors for system hardware (CPU/RAM/DISK/ETC)
+// ntp.go reports clock synchronization status from chrony or ntpd.
+
+package system
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	agentutils "github.com/aaronlmathis/gosight-agent/internal/utils"
+	"github.com/aaronlmathis/gosight-shared/model"
+	"github.com/aaronlmathis/gosight-shared/utils"
+)
+
+// ntpCommandTimeout bounds each chronyc/ntpq invocation.
+const ntpCommandTimeout = 5 * time.Second
+
+// ntpStatus is the clock state reported by the time daemon.
+type ntpStatus struct {
+	OffsetSeconds float64
+	Stratum       int
+	Synchronized  bool
+}
+
+type NTPCollector struct {
+	daemon string // "auto", "chrony" or "ntpd"
+}
+
+// NewNTPCollector creates a collector for the given daemon. An empty value or
+// "auto" detects chrony first, then ntpd.
+func NewNTPCollector(daemon string) *NTPCollector {
+	if daemon == "" {
+		daemon = "auto"
+	}
+	return &NTPCollector{daemon: daemon}
+}
+
+// Name returns the name of the collector.
+// This is used to identify the collector in logs and metrics.
+func (c *NTPCollector) Name() string {
+	return "ntp"
+}
+
+// Collect emits offset_seconds, stratum and synchronized (1/0). If neither
+// chronyc nor ntpq is installed it returns no metrics and no error.
+func (c *NTPCollector) Collect(ctx context.Context) ([]model.Metric, error) {
+	daemon := c.detect()
+	if daemon == "" {
+		return nil, nil
+	}
+
+	var (
+		st  ntpStatus
+		err error
+	)
+	switch daemon {
+	case "chrony":
+		st, err = chronyStatus(ctx)
+	case "ntpd":
+		st, err = ntpdStatus(ctx)
+	}
+	if err != nil {
+		utils.Debug("NTP status from %s failed: %v", daemon, err)
+		return nil, err
+	}
+
+	now := time.Now()
+	dims := map[string]string{"daemon": daemon}
+	synced := 0
+	if st.Synchronized {
+		synced = 1
+	}
+
+	return []model.Metric{
+		agentutils.Metric("System", "NTP", "offset_seconds", st.OffsetSeconds, "gauge", "seconds", dims, now),
+		agentutils.Metric("System", "NTP", "stratum", st.Stratum, "gauge", "count", dims, now),
+		agentutils.Metric("System", "NTP", "synchronized", synced, "gauge", "bool", dims, now),
+	}, nil
+}
+
+// detect returns the daemon to query, or "" if none is available.
+func (c *NTPCollector) detect() string {
+	switch c.daemon {
+	case "chrony":
+		if _, err := exec.LookPath("chronyc"); err == nil {
+			return "chrony"
+		}
+	case "ntpd":
+		if _, err := exec.LookPath("ntpq"); err == nil {
+			return "ntpd"
+		}
+	default:
+		if _, err := exec.LookPath("chronyc"); err == nil {
+			return "chrony"
+		}
+		if _, err := exec.LookPath("ntpq"); err == nil {
+			return "ntpd"
+		}
+	}
+	return ""
+}
+
+// chronyStatus parses `chronyc -c tracking` (CSV):
+// refid,name,stratum,reftime,system_offset,last_offset,rms_offset,freq,
+// resid_freq,skew,root_delay,root_dispersion,update_interval,leap_status
+func chronyStatus(ctx context.Context) (ntpStatus, error) {
+	ctx, cancel := context.WithTimeout(ctx, ntpCommandTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, "chronyc", "-c", "tracking").Output()
+	if err != nil {
+		return ntpStatus{}, err
+	}
+	fields := strings.Split(strings.TrimSpace(string(out)), ",")
+	if len(fields) < 14 {
+		return ntpStatus{}, fmt.Errorf("unexpected chronyc output: %q", out)
+	}
+
+	var st ntpStatus
+	st.Stratum, _ = strconv.Atoi(fields[2])
+	st.OffsetSeconds, _ = strconv.ParseFloat(fields[4], 64)
+	st.Synchronized = fields[13] != "Not synchronised" && st.Stratum > 0 && st.Stratum < 16
+	return st, nil
+}
+
+// ntpdStatus parses the system variables from `ntpq -c rv`, e.g.
+// "leap=00, stratum=2, offset=-0.123, ...". ntpq reports offset in milliseconds.
+func ntpdStatus(ctx context.Context) (ntpStatus, error) {
+	ctx, cancel := context.WithTimeout(ctx, ntpCommandTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, "ntpq", "-c", "rv").Output()
+	if err != nil {
+		return ntpStatus{}, err
+	}
+
+	vars := make(map[string]string)
+	for _, part := range strings.FieldsFunc(string(out), func(r rune) bool { return r == ',' || r == '\n' }) {
+		k, v, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if ok {
+			vars[k] = strings.Trim(v, `"`)
+		}
+	}
+	if _, ok := vars["stratum"]; !ok {
+		return ntpStatus{}, fmt.Errorf("unexpected ntpq output")
+	}
+
+	var st ntpStatus
+	st.Stratum, _ = strconv.Atoi(vars["stratum"])
+	if ms, err := strconv.ParseFloat(vars["offset"], 64); err == nil {
+		st.OffsetSeconds = ms / 1000
+	}
+	st.Synchronized = vars["leap"] != "11" && vars["leap"] != "" && st.Stratum > 0 && st.Stratum < 16
+	return st, nil
+}
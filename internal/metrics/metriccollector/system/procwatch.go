@@ -0,0 +1,143 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/collector/system/procwatch.go
+// Package system provides collectors for system hardware (CPU/RAM/DISK/ETC)
+// procwatch.go tracks watched processes across snapshots to detect restarts.
+// It uses the gopsutil library to enumerate processes.
+
+package system
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	agentutils "github.com/aaronlmathis/gosight-agent/internal/utils"
+	"github.com/aaronlmathis/gosight-shared/model"
+	"github.com/aaronlmathis/gosight-shared/utils"
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// watchedProcess is the last observed identity of a watched process.
+type watchedProcess struct {
+	PID       int32
+	StartTime int64 // milliseconds since epoch
+}
+
+type ProcessWatchCollector struct {
+	names    []string
+	mu       sync.Mutex
+	last     map[string]watchedProcess
+	restarts map[string]uint64
+}
+
+// NewProcessWatchCollector creates a collector that watches the named processes
+// (matched against the process name, e.g. "nginx") for restarts.
+func NewProcessWatchCollector(names []string) *ProcessWatchCollector {
+	return &ProcessWatchCollector{
+		names:    names,
+		last:     make(map[string]watchedProcess),
+		restarts: make(map[string]uint64),
+	}
+}
+
+// Stateful marks the collector as reporting restarts relative to the previous call.
+func (c *ProcessWatchCollector) Stateful() {}
+
+// Name returns the name of the collector.
+// This is used to identify the collector in logs and metrics.
+func (c *ProcessWatchCollector) Name() string {
+	return "procwatch"
+}
+
+// Collect finds each watched process and compares its PID and start time to
+// the previous snapshot. When either changes it emits restarted=1 and bumps the
+// restarts counter. If several processes share a name, the oldest one (usually
+// the supervisor-started parent) is tracked. A watched process that is not
+// running reports running=0.
+func (c *ProcessWatchCollector) Collect(ctx context.Context) ([]model.Metric, error) {
+	procs, err := process.ProcessesWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	watched := make(map[string]struct{}, len(c.names))
+	for _, n := range c.names {
+		watched[n] = struct{}{}
+	}
+
+	current := make(map[string]watchedProcess)
+	for _, p := range procs {
+		name, err := p.NameWithContext(ctx)
+		if err != nil {
+			continue
+		}
+		if _, ok := watched[name]; !ok {
+			continue
+		}
+		start, err := p.CreateTimeWithContext(ctx)
+		if err != nil {
+			continue
+		}
+		if prev, ok := current[name]; !ok || start < prev.StartTime {
+			current[name] = watchedProcess{PID: p.Pid, StartTime: start}
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var metrics []model.Metric
+	now := time.Now()
+
+	for _, name := range c.names {
+		cur, running := current[name]
+		if !running {
+			metrics = append(metrics,
+				agentutils.Metric("System", "Process", "running", 0, "gauge", "bool", map[string]string{"process": name}, now))
+			continue
+		}
+
+		restarted := 0
+		if prev, seen := c.last[name]; seen && (prev.PID != cur.PID || prev.StartTime != cur.StartTime) {
+			restarted = 1
+			c.restarts[name]++
+			utils.Warn("Watched process %s restarted (pid %d -> %d)", name, prev.PID, cur.PID)
+		}
+		c.last[name] = cur
+
+		// The pid is reported as a value, not a dimension: it changes on
+		// every restart and would start new series
+		dims := map[string]string{"process": name}
+		uptime := now.Sub(time.UnixMilli(cur.StartTime)).Seconds()
+
+		metrics = append(metrics,
+			agentutils.Metric("System", "Process", "running", 1, "gauge", "bool", dims, now),
+			agentutils.Metric("System", "Process", "pid", cur.PID, "gauge", "id", dims, now),
+			agentutils.Metric("System", "Process", "uptime", uptime, "gauge", "seconds", dims, now),
+			agentutils.Metric("System", "Process", "restarted", restarted, "gauge", "bool", dims, now),
+			agentutils.Metric("System", "Process", "restarts", c.restarts[name], "counter", "count", dims, now),
+		)
+	}
+
+	return metrics, nil
+}
@@ -0,0 +1,83 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/collector/system/sensors.go
+// sensors.go collects hardware temperature sensors (CPU packages, NVMe,
+// ACPI thermal zones, ...) where the platform exposes them.
+
+package system
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	agentutils "github.com/aaronlmathis/gosight-agent/internal/utils"
+	"github.com/aaronlmathis/gosight-shared/model"
+	"github.com/aaronlmathis/gosight-shared/utils"
+	"github.com/shirou/gopsutil/v4/sensors"
+)
+
+type SensorsCollector struct {
+	once sync.Once
+}
+
+// NewSensorsCollector creates a new SensorsCollector instance.
+func NewSensorsCollector() *SensorsCollector {
+	return &SensorsCollector{}
+}
+
+// Name returns the name of the collector.
+// This is used to identify the collector in logs and metrics.
+func (c *SensorsCollector) Name() string {
+	return "sensors"
+}
+
+// Collect emits sensor_temperature_c per sensor, plus sensor_high_c and
+// sensor_critical_c when the sensor reports thresholds, keyed by the
+// sensor_key dimension. Sensors that can't be read on Linux are skipped; on
+// platforms without sensor support it returns nothing, noting it once.
+func (c *SensorsCollector) Collect(ctx context.Context) ([]model.Metric, error) {
+	temps, err := sensors.TemperaturesWithContext(ctx)
+	if err != nil {
+		// Linux reports unreadable sensors as warnings alongside the rest
+		var warns *sensors.Warnings
+		if !errors.As(err, &warns) || len(temps) == 0 {
+			c.once.Do(func() { utils.Debug("sensors collector: no temperature sensors available: %v", err) })
+			return nil, nil
+		}
+	}
+
+	var metrics []model.Metric
+	now := time.Now()
+	for _, t := range temps {
+		dims := map[string]string{"sensor_key": t.SensorKey}
+		metrics = append(metrics, agentutils.Metric("System", "Sensors", "sensor_temperature_c", t.Temperature, "gauge", "celsius", dims, now))
+		if t.High > 0 {
+			metrics = append(metrics, agentutils.Metric("System", "Sensors", "sensor_high_c", t.High, "gauge", "celsius", dims, now))
+		}
+		if t.Critical > 0 {
+			metrics = append(metrics, agentutils.Metric("System", "Sensors", "sensor_critical_c", t.Critical, "gauge", "celsius", dims, now))
+		}
+	}
+	return metrics, nil
+}
@@ -0,0 +1,247 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/collector/system/smart.go
+// Package system provides collectors for system hardware (CPU/RAM/DISK/ETC)
+// smart.go reports per-disk SMART health from `smartctl -j`.
+
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"sync"
+	"time"
+
+	agentutils "github.com/aaronlmathis/gosight-agent/internal/utils"
+	"github.com/aaronlmathis/gosight-shared/model"
+	"github.com/aaronlmathis/gosight-shared/utils"
+)
+
+// smartCommandTimeout bounds each smartctl invocation.
+const smartCommandTimeout = 15 * time.Second
+
+// ATA attribute IDs used for the reported metrics.
+const (
+	ataReallocatedSectors = 5
+	ataPendingSectors     = 197
+	ataWearLeveling       = 177 // Samsung et al. (normalized value = life remaining)
+	ataSSDLifeLeft        = 231
+	ataMediaWearout       = 233 // Intel (normalized value = life remaining)
+)
+
+// smartDevice is a block device as listed by `smartctl --scan -j`.
+type smartDevice struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// smartReport is the subset of `smartctl -j -a` output that is reported.
+type smartReport struct {
+	ModelName   string `json:"model_name"`
+	SmartStatus *struct {
+		Passed bool `json:"passed"`
+	} `json:"smart_status"`
+	Temperature *struct {
+		Current float64 `json:"current"`
+	} `json:"temperature"`
+	ATAAttributes *struct {
+		Table []struct {
+			ID    int `json:"id"`
+			Value int `json:"value"`
+			Raw   struct {
+				Value uint64 `json:"value"`
+			} `json:"raw"`
+		} `json:"table"`
+	} `json:"ata_smart_attributes"`
+	NVMeHealth *struct {
+		PercentageUsed float64 `json:"percentage_used"`
+		MediaErrors    uint64  `json:"media_errors"`
+	} `json:"nvme_smart_health_information_log"`
+}
+
+type SMARTCollector struct {
+	devices []string // configured devices; empty = auto-detect
+
+	mu     sync.Mutex
+	warned map[string]bool // devices (or "smartctl") already warned about
+}
+
+// NewSMARTCollector creates a collector for the given devices (e.g. /dev/sda).
+// If none are given, devices are auto-detected with `smartctl --scan`.
+func NewSMARTCollector(devices []string) *SMARTCollector {
+	return &SMARTCollector{devices: devices, warned: make(map[string]bool)}
+}
+
+// Name returns the name of the collector.
+// This is used to identify the collector in logs and metrics.
+func (c *SMARTCollector) Name() string {
+	return "smart"
+}
+
+// Collect emits reallocated_sectors, pending_sectors, temperature,
+// ssd_wear_percent and health_passed (1/0) per device, where available.
+// Reading SMART data usually requires root; devices that cannot be read are
+// skipped with a single warning instead of failing the collector.
+func (c *SMARTCollector) Collect(ctx context.Context) ([]model.Metric, error) {
+	if _, err := exec.LookPath("smartctl"); err != nil {
+		c.warnOnce("smartctl", "SMART collector: smartctl not found in PATH; no SMART metrics will be reported")
+		return nil, nil
+	}
+
+	devices, err := c.targets(ctx)
+	if err != nil {
+		c.warnOnce("scan", "SMART collector: device scan failed: %v", err)
+		return nil, nil
+	}
+
+	var metrics []model.Metric
+	now := time.Now()
+	for _, dev := range devices {
+		report, err := readSMART(ctx, dev)
+		if err != nil {
+			c.warnOnce(dev.Name, "SMART collector: cannot read %s (root privileges are usually required): %v", dev.Name, err)
+			continue
+		}
+		metrics = append(metrics, smartMetrics(dev.Name, report, now)...)
+	}
+	return metrics, nil
+}
+
+// targets returns the configured devices, or the auto-detected ones.
+func (c *SMARTCollector) targets(ctx context.Context) ([]smartDevice, error) {
+	if len(c.devices) > 0 {
+		devices := make([]smartDevice, 0, len(c.devices))
+		for _, name := range c.devices {
+			devices = append(devices, smartDevice{Name: name})
+		}
+		return devices, nil
+	}
+
+	out, err := runSmartctl(ctx, "--scan", "-j")
+	if err != nil {
+		return nil, err
+	}
+	var scan struct {
+		Devices []smartDevice `json:"devices"`
+	}
+	if err := json.Unmarshal(out, &scan); err != nil {
+		return nil, fmt.Errorf("parse smartctl --scan output: %w", err)
+	}
+	return scan.Devices, nil
+}
+
+// warnOnce logs a warning the first time it is called for key.
+func (c *SMARTCollector) warnOnce(key, format string, args ...any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.warned[key] {
+		return
+	}
+	c.warned[key] = true
+	utils.Warn(format, args...)
+}
+
+// readSMART runs `smartctl -j -a` for one device.
+func readSMART(ctx context.Context, dev smartDevice) (*smartReport, error) {
+	args := []string{"-j", "-a", dev.Name}
+	if dev.Type != "" {
+		args = append(args, "-d", dev.Type)
+	}
+	out, err := runSmartctl(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var report smartReport
+	if err := json.Unmarshal(out, &report); err != nil {
+		return nil, fmt.Errorf("parse smartctl output: %w", err)
+	}
+	return &report, nil
+}
+
+// runSmartctl runs smartctl and returns its stdout. smartctl's exit status is
+// a bit mask; only bits 0 and 1 (bad command line, device open failed) mean
+// the output is unusable, the others report disk conditions.
+func runSmartctl(ctx context.Context, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, smartCommandTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, "smartctl", args...).Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode()&0x3 == 0 {
+		return out, nil
+	}
+	return out, err
+}
+
+// smartMetrics converts a report into metrics for device.
+func smartMetrics(device string, r *smartReport, now time.Time) []model.Metric {
+	dims := map[string]string{"device": device}
+	if r.ModelName != "" {
+		dims["model"] = r.ModelName
+	}
+
+	var metrics []model.Metric
+	add := func(name string, value any, unit string) {
+		metrics = append(metrics, agentutils.Metric("System", "SMART", name, value, "gauge", unit, dims, now))
+	}
+
+	if r.SmartStatus != nil {
+		passed := 0
+		if r.SmartStatus.Passed {
+			passed = 1
+		}
+		add("health_passed", passed, "bool")
+	}
+	if r.Temperature != nil {
+		add("temperature", r.Temperature.Current, "celsius")
+	}
+
+	if r.ATAAttributes != nil {
+		wear := -1
+		for _, attr := range r.ATAAttributes.Table {
+			switch attr.ID {
+			case ataReallocatedSectors:
+				add("reallocated_sectors", attr.Raw.Value, "count")
+			case ataPendingSectors:
+				add("pending_sectors", attr.Raw.Value, "count")
+			case ataWearLeveling, ataSSDLifeLeft, ataMediaWearout:
+				if wear < 0 && attr.Value <= 100 {
+					wear = 100 - attr.Value
+				}
+			}
+		}
+		if wear >= 0 {
+			add("ssd_wear_percent", wear, "percent")
+		}
+	}
+
+	if r.NVMeHealth != nil {
+		add("ssd_wear_percent", r.NVMeHealth.PercentageUsed, "percent")
+		add("media_errors", r.NVMeHealth.MediaErrors, "count")
+	}
+
+	return metrics
+}
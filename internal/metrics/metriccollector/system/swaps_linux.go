@@ -0,0 +1,193 @@
+//go:build linux
+
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/collector/system/swaps_linux.go
+// Package system provides collectors for system hardware (CPU/RAM/DISK/ETC)
+// swaps_linux.go collects per-device swap usage from /proc/swaps and zram
+// compression stats from sysfs, which gopsutil's aggregate SwapMemory hides.
+
+package system
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	agentutils "github.com/aaronlmathis/gosight-agent/internal/utils"
+	"github.com/aaronlmathis/gosight-shared/model"
+)
+
+const (
+	swapsPath    = "/proc/swaps"
+	zramSysGlob  = "/sys/block/zram*"
+	zramMMStat   = "mm_stat"
+	zramDiskSize = "disksize"
+)
+
+// swapDevice is one line of /proc/swaps. Sizes are in bytes.
+type swapDevice struct {
+	Name     string
+	Type     string
+	Size     uint64
+	Used     uint64
+	Priority int
+}
+
+type SwapsCollector struct{}
+
+// NewSwapsCollector creates a new SwapsCollector instance.
+func NewSwapsCollector() *SwapsCollector {
+	return &SwapsCollector{}
+}
+
+// Name returns the name of the collector.
+// This is used to identify the collector in logs and metrics.
+func (c *SwapsCollector) Name() string {
+	return "swaps"
+}
+
+// Collect emits System/Swap device_size_bytes, device_used_bytes and
+// device_priority per swap device or file (dimensions device, type), and for
+// every initialized zram device System/Zram disksize_bytes, orig_data_bytes,
+// compr_data_bytes, mem_used_bytes and compression_ratio (dimension device).
+func (c *SwapsCollector) Collect(_ context.Context) ([]model.Metric, error) {
+	f, err := os.Open(swapsPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	now := time.Now()
+	var metrics []model.Metric
+	for _, d := range parseSwaps(bufio.NewScanner(f)) {
+		dims := map[string]string{"device": d.Name, "type": d.Type}
+		metrics = append(metrics,
+			agentutils.Metric("System", "Swap", "device_size_bytes", d.Size, "gauge", "bytes", dims, now),
+			agentutils.Metric("System", "Swap", "device_used_bytes", d.Used, "gauge", "bytes", dims, now),
+			agentutils.Metric("System", "Swap", "device_priority", d.Priority, "gauge", "count", dims, now),
+		)
+	}
+
+	zrams, _ := filepath.Glob(zramSysGlob)
+	for _, dir := range zrams {
+		metrics = append(metrics, zramMetrics(dir, now)...)
+	}
+	return metrics, nil
+}
+
+// parseSwaps parses /proc/swaps, skipping the header. Sizes are reported in
+// KiB and converted to bytes.
+func parseSwaps(sc *bufio.Scanner) []swapDevice {
+	var devices []swapDevice
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) < 5 || fields[0] == "Filename" {
+			continue
+		}
+		size, err1 := strconv.ParseUint(fields[2], 10, 64)
+		used, err2 := strconv.ParseUint(fields[3], 10, 64)
+		prio, err3 := strconv.Atoi(fields[4])
+		if err1 != nil || err2 != nil || err3 != nil {
+			continue
+		}
+		devices = append(devices, swapDevice{
+			Name:     unescapeOctal(fields[0]),
+			Type:     fields[1],
+			Size:     size * 1024,
+			Used:     used * 1024,
+			Priority: prio,
+		})
+	}
+	return devices
+}
+
+// unescapeOctal decodes the \ooo escapes the kernel uses for whitespace in
+// /proc/swaps file names (e.g. "\040" for a space).
+func unescapeOctal(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+3 < len(s) {
+			if v, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(v))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// zramMetrics reads the sysfs stats of one zram device. Devices that are not
+// initialized (disksize 0) are skipped.
+func zramMetrics(dir string, now time.Time) []model.Metric {
+	diskSize, err := readUintFile(filepath.Join(dir, zramDiskSize))
+	if err != nil || diskSize == 0 {
+		return nil
+	}
+	data, err := os.ReadFile(filepath.Join(dir, zramMMStat))
+	if err != nil {
+		return nil
+	}
+	// mm_stat: orig_data_size compr_data_size mem_used_total mem_limit mem_used_max ...
+	fields := strings.Fields(string(data))
+	if len(fields) < 3 {
+		return nil
+	}
+	var stat [3]uint64
+	for i := range stat {
+		if stat[i], err = strconv.ParseUint(fields[i], 10, 64); err != nil {
+			return nil
+		}
+	}
+	orig, compr, used := stat[0], stat[1], stat[2]
+
+	dims := map[string]string{"device": filepath.Base(dir)}
+	metrics := []model.Metric{
+		agentutils.Metric("System", "Zram", "disksize_bytes", diskSize, "gauge", "bytes", dims, now),
+		agentutils.Metric("System", "Zram", "orig_data_bytes", orig, "gauge", "bytes", dims, now),
+		agentutils.Metric("System", "Zram", "compr_data_bytes", compr, "gauge", "bytes", dims, now),
+		agentutils.Metric("System", "Zram", "mem_used_bytes", used, "gauge", "bytes", dims, now),
+	}
+	if compr > 0 {
+		metrics = append(metrics,
+			agentutils.Metric("System", "Zram", "compression_ratio", float64(orig)/float64(compr), "gauge", "ratio", dims, now))
+	}
+	return metrics
+}
+
+// readUintFile reads a file holding a single unsigned integer.
+func readUintFile(path string) (uint64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+}
@@ -0,0 +1,51 @@
+//go:build !linux
+
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/collector/system/swaps_other.go
+// Package system provides collectors for system hardware (CPU/RAM/DISK/ETC)
+// swaps_other.go is a no-op swaps collector for platforms without /proc/swaps.
+
+package system
+
+import (
+	"context"
+
+	"github.com/aaronlmathis/gosight-shared/model"
+)
+
+type SwapsCollector struct{}
+
+// NewSwapsCollector creates a new SwapsCollector instance.
+func NewSwapsCollector() *SwapsCollector {
+	return &SwapsCollector{}
+}
+
+// Name returns the name of the collector.
+func (c *SwapsCollector) Name() string {
+	return "swaps"
+}
+
+// Collect returns no metrics on non-Linux platforms.
+func (c *SwapsCollector) Collect(_ context.Context) ([]model.Metric, error) {
+	return nil, nil
+}
@@ -0,0 +1,210 @@
+//go:build linux
+
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/collector/system/systemd_linux.go
+// Package system provides collectors for system hardware (CPU/RAM/DISK/ETC)
+// systemd_linux.go reports the state of systemd units via systemctl.
+
+package system
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	agentutils "github.com/aaronlmathis/gosight-agent/internal/utils"
+	"github.com/aaronlmathis/gosight-shared/model"
+	"github.com/aaronlmathis/gosight-shared/utils"
+)
+
+// systemdRuntimeDir exists only when systemd is the init system (what
+// sd_booted checks).
+const systemdRuntimeDir = "/run/systemd/system"
+
+// systemdShowBatch is how many units are passed to one systemctl show.
+const systemdShowBatch = 200
+
+// DefaultSystemdUnits is the unit filter used when none is configured.
+var DefaultSystemdUnits = []string{"*.service"}
+
+// systemdUnit is one row of systemctl list-units.
+type systemdUnit struct {
+	Name      string
+	LoadState string
+	Active    string
+	SubState  string
+}
+
+type SystemdCollector struct {
+	units []string // unit name globs passed to systemctl
+
+	once sync.Once
+}
+
+// NewSystemdCollector creates a collector for the units matching the given
+// globs (e.g. "*.service", "nginx*"); DefaultSystemdUnits if empty.
+func NewSystemdCollector(units []string) *SystemdCollector {
+	if len(units) == 0 {
+		units = DefaultSystemdUnits
+	}
+	return &SystemdCollector{units: units}
+}
+
+// Name returns the name of the collector.
+// This is used to identify the collector in logs and metrics.
+func (c *SystemdCollector) Name() string {
+	return "systemd"
+}
+
+// Collect emits unit_active (1/0), unit_failed (1/0) and n_restarts per
+// matching loaded unit, with unit, load_state and sub_state dimensions. It
+// returns nothing when systemd isn't the init system.
+func (c *SystemdCollector) Collect(ctx context.Context) ([]model.Metric, error) {
+	if _, err := os.Stat(systemdRuntimeDir); err != nil {
+		c.once.Do(func() {
+			utils.Info("systemd collector: systemd is not the init system; no unit metrics will be reported")
+		})
+		return nil, nil
+	}
+
+	units, err := listSystemdUnits(ctx, c.units)
+	if err != nil {
+		return nil, err
+	}
+	restarts := systemdRestarts(ctx, units)
+
+	var metrics []model.Metric
+	now := time.Now()
+	for _, u := range units {
+		dims := func() map[string]string {
+			return map[string]string{"unit": u.Name, "load_state": u.LoadState, "sub_state": u.SubState}
+		}
+		active, failed := 0, 0
+		switch u.Active {
+		case "active", "reloading":
+			active = 1
+		case "failed":
+			failed = 1
+		}
+		metrics = append(metrics,
+			agentutils.Metric("System", "Systemd", "unit_active", active, "gauge", "bool", dims(), now),
+			agentutils.Metric("System", "Systemd", "unit_failed", failed, "gauge", "bool", dims(), now),
+		)
+		if n, ok := restarts[u.Name]; ok {
+			metrics = append(metrics, agentutils.Metric("System", "Systemd", "n_restarts", n, "counter", "count", dims(), now))
+		}
+	}
+	return metrics, nil
+}
+
+// listSystemdUnits returns the loaded units matching patterns.
+func listSystemdUnits(ctx context.Context, patterns []string) ([]systemdUnit, error) {
+	args := append([]string{"list-units", "--all", "--plain", "--no-legend", "--no-pager", "--full", "--"}, patterns...)
+	out, err := exec.CommandContext(ctx, "systemctl", args...).Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseSystemdUnits(out), nil
+}
+
+// parseSystemdUnits parses "UNIT LOAD ACTIVE SUB DESCRIPTION" rows. Units
+// that are referenced but not found on disk are skipped.
+func parseSystemdUnits(out []byte) []systemdUnit {
+	var units []systemdUnit
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		f := strings.Fields(sc.Text())
+		// Failed units may be marked with a leading bullet
+		if len(f) > 0 && (f[0] == "●" || f[0] == "*") {
+			f = f[1:]
+		}
+		if len(f) < 4 || f[1] == "not-found" {
+			continue
+		}
+		units = append(units, systemdUnit{Name: f[0], LoadState: f[1], Active: f[2], SubState: f[3]})
+	}
+	return units
+}
+
+// systemdRestarts returns NRestarts per unit (only services have it).
+func systemdRestarts(ctx context.Context, units []systemdUnit) map[string]uint64 {
+	var names []string
+	for _, u := range units {
+		if strings.HasSuffix(u.Name, ".service") {
+			names = append(names, u.Name)
+		}
+	}
+
+	restarts := make(map[string]uint64, len(names))
+	for start := 0; start < len(names); start += systemdShowBatch {
+		end := min(start+systemdShowBatch, len(names))
+		args := append([]string{"show", "--property=Id,NRestarts", "--"}, names[start:end]...)
+		out, err := exec.CommandContext(ctx, "systemctl", args...).Output()
+		if err != nil {
+			utils.Debug("systemd collector: systemctl show failed: %v", err)
+			continue
+		}
+
+		for id, n := range parseSystemdShow(out) {
+			restarts[id] = n
+		}
+	}
+	return restarts
+}
+
+// parseSystemdShow parses systemctl show output for Id and NRestarts: one
+// block of properties per unit, separated by blank lines, in whatever order
+// systemctl prints them.
+func parseSystemdShow(out []byte) map[string]uint64 {
+	restarts := make(map[string]uint64)
+	var id, n string
+	flush := func() {
+		if id != "" && n != "" {
+			if v, err := strconv.ParseUint(n, 10, 64); err == nil {
+				restarts[id] = v
+			}
+		}
+		id, n = "", ""
+	}
+
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		k, v, _ := strings.Cut(sc.Text(), "=")
+		switch k {
+		case "Id":
+			id = v
+		case "NRestarts":
+			n = v
+		case "":
+			flush()
+		}
+	}
+	flush()
+	return restarts
+}
@@ -0,0 +1,61 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+package system
+
+import "testing"
+
+func TestParseSystemdUnits(t *testing.T) {
+	out := []byte(`nginx.service       loaded    active   running Nginx HTTP server
+● backup.service    loaded    failed   failed  Nightly backup
+ghost.service       not-found inactive dead    ghost.service
+cron.service        loaded    inactive dead    Regular background program processing daemon
+`)
+	units := parseSystemdUnits(out)
+	if len(units) != 3 {
+		t.Fatalf("got %d units, want 3: %+v", len(units), units)
+	}
+	want := []systemdUnit{
+		{Name: "nginx.service", LoadState: "loaded", Active: "active", SubState: "running"},
+		{Name: "backup.service", LoadState: "loaded", Active: "failed", SubState: "failed"},
+		{Name: "cron.service", LoadState: "loaded", Active: "inactive", SubState: "dead"},
+	}
+	for i, u := range units {
+		if u != want[i] {
+			t.Errorf("unit %d = %+v, want %+v", i, u, want[i])
+		}
+	}
+}
+
+func TestParseSystemdShow(t *testing.T) {
+	// systemctl prints properties in its own order, NRestarts before Id
+	out := []byte("NRestarts=3\nId=nginx.service\n\nNRestarts=0\nId=cron.service\n\nId=oneshot.service\n\nId=last.service\nNRestarts=7\n")
+	got := parseSystemdShow(out)
+	want := map[string]uint64{"nginx.service": 3, "cron.service": 0, "last.service": 7}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for id, n := range want {
+		if got[id] != n {
+			t.Errorf("%s: got %d restarts, want %d", id, got[id], n)
+		}
+	}
+}
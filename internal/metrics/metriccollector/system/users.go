@@ -0,0 +1,82 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/collector/system/users.go
+// Package system provides collectors for system hardware (CPU/RAM/DISK/ETC)
+// users.go reports per-user CPU and memory usage aggregated by the process runner.
+
+package system
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aaronlmathis/gosight-agent/internal/processes/processcollector"
+	agentutils "github.com/aaronlmathis/gosight-agent/internal/utils"
+	"github.com/aaronlmathis/gosight-shared/model"
+)
+
+type UserUsageCollector struct {
+	mu   sync.Mutex
+	last time.Time
+}
+
+// NewUserUsageCollector creates a collector that reports the per-user
+// aggregates published by the process runner.
+func NewUserUsageCollector() *UserUsageCollector {
+	return &UserUsageCollector{}
+}
+
+// Stateful marks the collector as reporting each process runner snapshot only once.
+func (c *UserUsageCollector) Stateful() {}
+
+// Name returns the name of the collector.
+// This is used to identify the collector in logs and metrics.
+func (c *UserUsageCollector) Name() string {
+	return "users"
+}
+
+// Collect emits System.User cpu_percent, mem_percent and processes with a
+// "user" dimension from the latest process snapshot. Nothing is emitted until
+// the process runner has published a new snapshot since the previous call, so
+// a slow process interval does not produce repeated points.
+func (c *UserUsageCollector) Collect(_ context.Context) ([]model.Metric, error) {
+	ts, usage := processcollector.LatestUserUsage()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if ts.IsZero() || !ts.After(c.last) {
+		return nil, nil
+	}
+	c.last = ts
+
+	metrics := make([]model.Metric, 0, len(usage)*3)
+	for _, u := range usage {
+		dims := map[string]string{"user": u.User}
+		metrics = append(metrics,
+			agentutils.Metric("System", "User", "cpu_percent", u.CPUPercent, "gauge", "percent", dims, ts),
+			agentutils.Metric("System", "User", "mem_percent", u.MemPercent, "gauge", "percent", dims, ts),
+			agentutils.Metric("System", "User", "processes", u.Processes, "gauge", "count", dims, ts),
+		)
+	}
+	return metrics, nil
+}
@@ -0,0 +1,181 @@
+//go:build windows
+
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/collector/system/winservice_windows.go
+// Package system provides collectors for system hardware (CPU/RAM/DISK/ETC)
+// winservice_windows.go reports Windows service states via the Service Control Manager.
+
+package system
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	agentutils "github.com/aaronlmathis/gosight-agent/internal/utils"
+	"github.com/aaronlmathis/gosight-shared/model"
+	"github.com/aaronlmathis/gosight-shared/utils"
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+type WindowsServiceCollector struct {
+	services     []string
+	allAutoStart bool
+
+	mu     sync.Mutex
+	warned map[string]bool // listed services already warned about as missing
+}
+
+// NewWindowsServiceCollector creates a collector for the given service names.
+// If allAutoStart is true, every service configured to start automatically is
+// reported in addition to the explicit list.
+func NewWindowsServiceCollector(services []string, allAutoStart bool) *WindowsServiceCollector {
+	return &WindowsServiceCollector{
+		services:     services,
+		allAutoStart: allAutoStart,
+		warned:       make(map[string]bool),
+	}
+}
+
+// Name returns the name of the collector.
+// This is used to identify the collector in logs and metrics.
+func (c *WindowsServiceCollector) Name() string {
+	return "winservices"
+}
+
+// Collect queries the SCM and emits service_running (1/0), service_state
+// (the SCM state code) and service_start_type for each selected service.
+func (c *WindowsServiceCollector) Collect(_ context.Context) ([]model.Metric, error) {
+	m, err := mgr.Connect()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to service manager: %w", err)
+	}
+	defer m.Disconnect()
+
+	explicit := make(map[string]struct{}, len(c.services))
+	names := make(map[string]struct{}, len(c.services))
+	for _, n := range c.services {
+		explicit[n] = struct{}{}
+		names[n] = struct{}{}
+	}
+	if c.allAutoStart {
+		all, err := m.ListServices()
+		if err != nil {
+			utils.Warn("Failed to list Windows services: %v", err)
+		}
+		for _, n := range all {
+			names[n] = struct{}{}
+		}
+	}
+
+	var metrics []model.Metric
+	now := time.Now()
+
+	for name := range names {
+		_, listed := explicit[name]
+		s, err := m.OpenService(name)
+		if err != nil {
+			if listed {
+				c.warnOnce(name, "Failed to open Windows service %s: %v", name, err)
+			} else {
+				utils.Debug("Failed to open Windows service %s: %v", name, err)
+			}
+			continue
+		}
+		if listed {
+			c.clearWarned(name)
+		}
+		conf, cerr := s.Config()
+		status, qerr := s.Query()
+		s.Close()
+		if cerr != nil || qerr != nil {
+			continue
+		}
+
+		// In auto-start mode, services not explicitly listed must be auto-start
+		if !listed && conf.StartType != mgr.StartAutomatic {
+			continue
+		}
+
+		dims := map[string]string{
+			"service":      name,
+			"display_name": conf.DisplayName,
+			"start_type":   startTypeName(conf.StartType, conf.DelayedAutoStart),
+		}
+		running := 0
+		if status.State == svc.Running {
+			running = 1
+		}
+
+		metrics = append(metrics,
+			agentutils.Metric("Windows", "", "service_running", running, "gauge", "bool", dims, now),
+			agentutils.Metric("Windows", "", "service_state", uint32(status.State), "gauge", "enum", dims, now),
+			agentutils.Metric("Windows", "", "service_start_type", conf.StartType, "gauge", "enum", dims, now),
+		)
+	}
+
+	return metrics, nil
+}
+
+// startTypeName returns a readable name for an SCM start type.
+func startTypeName(t uint32, delayed bool) string {
+	switch t {
+	case windows.SERVICE_BOOT_START:
+		return "boot"
+	case windows.SERVICE_SYSTEM_START:
+		return "system"
+	case mgr.StartAutomatic:
+		if delayed {
+			return "auto_delayed"
+		}
+		return "auto"
+	case mgr.StartManual:
+		return "manual"
+	case mgr.StartDisabled:
+		return "disabled"
+	default:
+		return "unknown"
+	}
+}
+
+// warnOnce logs a warning the first time it is called for key.
+func (c *WindowsServiceCollector) warnOnce(key, format string, args ...any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.warned[key] {
+		return
+	}
+	c.warned[key] = true
+	utils.Warn(format, args...)
+}
+
+// clearWarned forgets an earlier warning for key so that the service is
+// reported again if it disappears later.
+func (c *WindowsServiceCollector) clearWarned(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.warned, key)
+}
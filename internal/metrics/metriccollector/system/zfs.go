@@ -0,0 +1,160 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/collector/system/zfs.go
+// Package system provides collectors for system hardware (CPU/RAM/DISK/ETC)
+// zfs.go collects ZFS pool health and usage metrics.
+// It shells out to the zpool and zfs utilities and stays silent if they are absent.
+
+package system
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	agentutils "github.com/aaronlmathis/gosight-agent/internal/utils"
+	"github.com/aaronlmathis/gosight-shared/model"
+	"github.com/aaronlmathis/gosight-shared/utils"
+)
+
+// zfsCommandTimeout bounds every zpool/zfs invocation.
+const zfsCommandTimeout = 10 * time.Second
+
+// poolHealthStates maps ZFS/Btrfs health strings to the value of the health gauge.
+// 0 is healthy; higher values are progressively worse.
+var poolHealthStates = map[string]int{
+	"ONLINE":    0,
+	"DEGRADED":  1,
+	"FAULTED":   2,
+	"OFFLINE":   3,
+	"UNAVAIL":   4,
+	"REMOVED":   5,
+	"SUSPENDED": 6,
+}
+
+type ZFSCollector struct{}
+
+// NewZFSCollector creates a new ZFSCollector instance.
+func NewZFSCollector() *ZFSCollector {
+	return &ZFSCollector{}
+}
+
+// Name returns the name of the collector.
+// This is used to identify the collector in logs and metrics.
+func (c *ZFSCollector) Name() string {
+	return "zfs"
+}
+
+// Collect gathers pool capacity, fragmentation, dedup and compression ratios,
+// health, and scrub state for every imported ZFS pool.
+// If the ZFS utilities are not installed it returns no metrics and no error.
+func (c *ZFSCollector) Collect(ctx context.Context) ([]model.Metric, error) {
+	if _, err := exec.LookPath("zpool"); err != nil {
+		return nil, nil
+	}
+
+	var metrics []model.Metric
+	now := time.Now()
+
+	out, err := runZFSCommand(ctx, "zpool", "list", "-Hp", "-o", "name,size,alloc,free,frag,cap,dedup,health")
+	if err != nil {
+		utils.Debug("zpool list failed: %v", err)
+		return nil, nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) < 8 {
+			continue
+		}
+		pool := fields[0]
+		health := strings.ToUpper(fields[7])
+		dims := map[string]string{"pool": pool}
+		healthDims := map[string]string{"pool": pool, "health": strings.ToLower(health)}
+
+		metrics = append(metrics,
+			agentutils.Metric("System", "ZFS", "pool_size", parseZFSNumber(fields[1]), "gauge", "bytes", dims, now),
+			agentutils.Metric("System", "ZFS", "pool_allocated", parseZFSNumber(fields[2]), "gauge", "bytes", dims, now),
+			agentutils.Metric("System", "ZFS", "pool_free", parseZFSNumber(fields[3]), "gauge", "bytes", dims, now),
+			agentutils.Metric("System", "ZFS", "pool_fragmentation_percent", parseZFSNumber(fields[4]), "gauge", "percent", dims, now),
+			agentutils.Metric("System", "ZFS", "pool_capacity_percent", parseZFSNumber(fields[5]), "gauge", "percent", dims, now),
+			agentutils.Metric("System", "ZFS", "pool_dedup_ratio", parseZFSNumber(fields[6]), "gauge", "ratio", dims, now),
+			agentutils.Metric("System", "ZFS", "pool_health", healthValue(health), "gauge", "state", healthDims, now),
+		)
+
+		if status, err := runZFSCommand(ctx, "zpool", "status", pool); err == nil {
+			scrubbing := 0
+			if strings.Contains(string(status), "scrub in progress") {
+				scrubbing = 1
+			}
+			metrics = append(metrics,
+				agentutils.Metric("System", "ZFS", "pool_scrub_in_progress", scrubbing, "gauge", "bool", map[string]string{"pool": pool}, now))
+		}
+	}
+
+	// Compression ratio of each pool's root dataset
+	if out, err := runZFSCommand(ctx, "zfs", "list", "-Hp", "-d", "0", "-o", "name,compressratio"); err == nil {
+		scanner := bufio.NewScanner(bytes.NewReader(out))
+		for scanner.Scan() {
+			fields := strings.Split(scanner.Text(), "\t")
+			if len(fields) < 2 {
+				continue
+			}
+			metrics = append(metrics,
+				agentutils.Metric("System", "ZFS", "pool_compress_ratio", parseZFSNumber(fields[1]), "gauge", "ratio", map[string]string{"pool": fields[0]}, now))
+		}
+	}
+
+	return metrics, nil
+}
+
+// runZFSCommand runs a ZFS utility with a bounded timeout and returns its stdout.
+func runZFSCommand(ctx context.Context, name string, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, zfsCommandTimeout)
+	defer cancel()
+	return exec.CommandContext(ctx, name, args...).Output()
+}
+
+// parseZFSNumber parses numeric zpool/zfs output, tolerating the "%" and "x"
+// suffixes and "-" placeholders used by some versions.
+func parseZFSNumber(s string) float64 {
+	s = strings.TrimRight(strings.TrimSpace(s), "%x")
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0
+	}
+	return v
+}
+
+// healthValue converts a health string to the pool health gauge value.
+// Unknown states are reported as faulted.
+func healthValue(state string) int {
+	if v, ok := poolHealthStates[state]; ok {
+		return v
+	}
+	return poolHealthStates["FAULTED"]
+}
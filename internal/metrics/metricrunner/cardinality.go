@@ -0,0 +1,130 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+// agent/internal/metrics/metricrunner/cardinality.go
+package metricrunner
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	agentutils "github.com/aaronlmathis/gosight-agent/internal/utils"
+	"github.com/aaronlmathis/gosight-shared/model"
+	"github.com/aaronlmathis/gosight-shared/utils"
+)
+
+const (
+	// seriesExpiryIntervals is how many of the longest collector interval a
+	// series may go unseen before it stops counting against the limit, so
+	// churned containers free their slots while series of slow collectors
+	// survive between their runs.
+	seriesExpiryIntervals = 10
+
+	// cardinalityWarnEvery rate-limits the "series dropped" warning.
+	cardinalityWarnEvery = time.Minute
+
+	// cardinalityTopOffenders is how many metric names the warning lists.
+	cardinalityTopOffenders = 5
+)
+
+// cardinalityLimiter caps the number of distinct series (name + dimensions)
+// the agent emits. Once the cap is reached, metrics of series not seen before
+// are dropped while known series keep flowing. It is used only from the
+// collection loop and is not safe for concurrent use.
+type cardinalityLimiter struct {
+	max      int
+	lastSeen map[string]time.Time // series key -> when it was last emitted
+	dropped  uint64
+	lastWarn time.Time
+}
+
+func newCardinalityLimiter(max int) *cardinalityLimiter {
+	if max <= 0 {
+		return nil
+	}
+	return &cardinalityLimiter{max: max, lastSeen: make(map[string]time.Time)}
+}
+
+// apply filters metrics in place and appends Agent series_active (gauge) and
+// cardinality_dropped_total (counter). Series unseen for seriesExpiryIntervals
+// times interval, the longest collector interval, are forgotten first. A nil
+// limiter returns metrics unchanged.
+func (l *cardinalityLimiter) apply(metrics []model.Metric, now time.Time, interval time.Duration) []model.Metric {
+	if l == nil {
+		return metrics
+	}
+	expiry := seriesExpiryIntervals * interval
+	for key, seen := range l.lastSeen {
+		if now.Sub(seen) > expiry {
+			delete(l.lastSeen, key)
+		}
+	}
+
+	offenders := make(map[string]int)
+	kept := metrics[:0]
+	for _, m := range metrics {
+		key := agentutils.SeriesKey(m)
+		if _, known := l.lastSeen[key]; !known && len(l.lastSeen) >= l.max {
+			offenders[metricName(m)]++
+			continue
+		}
+		l.lastSeen[key] = now
+		kept = append(kept, m)
+	}
+
+	if n := len(metrics) - len(kept); n > 0 {
+		l.dropped += uint64(n)
+		if now.Sub(l.lastWarn) >= cardinalityWarnEvery {
+			l.lastWarn = now
+			utils.Warn("Series limit of %d reached; dropped %d metrics of new series. Top offenders: %s",
+				l.max, n, formatOffenders(offenders))
+		}
+	}
+
+	return append(kept,
+		agentutils.Metric("Agent", "", "series_active", len(l.lastSeen), "gauge", "count", nil, now),
+		agentutils.Metric("Agent", "", "cardinality_dropped_total", l.dropped, "counter", "count", nil, now),
+	)
+}
+
+// formatOffenders lists the metric names with the most dropped metrics,
+// e.g. "container.podman.cpu_percent (120), system.process.running (4)".
+func formatOffenders(offenders map[string]int) string {
+	names := make([]string, 0, len(offenders))
+	for name := range offenders {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if offenders[names[i]] != offenders[names[j]] {
+			return offenders[names[i]] > offenders[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	if len(names) > cardinalityTopOffenders {
+		names = names[:cardinalityTopOffenders]
+	}
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s (%d)", name, offenders[name])
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,79 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+package metricrunner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aaronlmathis/gosight-shared/model"
+)
+
+func TestCardinalityLimiter(t *testing.T) {
+	l := newCardinalityLimiter(2)
+	series := func(ids ...string) []model.Metric {
+		var out []model.Metric
+		for _, id := range ids {
+			out = append(out, model.Metric{Namespace: "Container", Name: "cpu_percent", Dimensions: map[string]string{"container_id": id}})
+		}
+		return out
+	}
+
+	// Two slots: a and b are admitted, c is dropped
+	start := time.Now()
+	interval := 10 * time.Second
+	out := l.apply(series("a", "b", "c"), start, interval)
+	if len(out) != 4 || out[0].Dimensions["container_id"] != "a" || out[1].Dimensions["container_id"] != "b" {
+		t.Fatalf("first cycle kept %+v", out)
+	}
+	if dropped := out[3]; dropped.Name != "cardinality_dropped_total" || dropped.Value != 1 {
+		t.Fatalf("dropped counter = %+v, want 1", dropped)
+	}
+
+	// Known series keep flowing while the limit is reached
+	out = l.apply(series("b", "d"), start.Add(interval), interval)
+	if len(out) != 3 || out[0].Dimensions["container_id"] != "b" || out[2].Value != 2 {
+		t.Fatalf("second cycle kept %+v", out)
+	}
+
+	// A slower collector interval keeps a's slot however many ticks pass
+	now := start
+	for i := 0; i < 2*seriesExpiryIntervals; i++ {
+		now = now.Add(interval)
+		l.apply(series("b"), now, 5*time.Minute)
+	}
+	out = l.apply(series("b", "d"), now, 5*time.Minute)
+	if len(out) != 3 {
+		t.Fatalf("expected a to keep its slot within the longest interval's window, kept %+v", out)
+	}
+
+	// Once a stops reporting long enough, its slot is freed for d
+	now = now.Add(seriesExpiryIntervals * interval)
+	out = l.apply(series("b", "d"), now, interval)
+	if len(out) != 4 || out[1].Dimensions["container_id"] != "d" {
+		t.Fatalf("after expiry kept %+v", out)
+	}
+
+	if newCardinalityLimiter(0).apply(series("a"), time.Now(), interval)[0].Name != "cpu_percent" {
+		t.Fatal("nil limiter should pass metrics through")
+	}
+}
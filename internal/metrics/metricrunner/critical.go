@@ -0,0 +1,150 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+// agent/internal/metrics/metricrunner/critical.go
+package metricrunner
+
+import (
+	"context"
+	"time"
+
+	"github.com/aaronlmathis/gosight-agent/internal/config"
+	"github.com/aaronlmathis/gosight-agent/internal/metrics/metriccollector"
+	agentutils "github.com/aaronlmathis/gosight-agent/internal/utils"
+	"github.com/aaronlmathis/gosight-shared/model"
+	"github.com/aaronlmathis/gosight-shared/utils"
+)
+
+const defaultCriticalCheckInterval = 5 * time.Second
+
+// runCritical is the fast path for metrics listed under
+// metric_collection.critical. Every check interval it re-runs the collectors
+// that produce critical metrics and immediately queues any metric whose
+// crossed state changed since the last check, bypassing the regular ticker.
+// The check waits for a running collection cycle rather than calling the
+// collectors concurrently. Buffered and stateful collectors (fifo, statsd,
+// otlp_socket, docker, podman, procwatch, ...) are never re-collected: an
+// extra call would take their data or shift their rate windows away from
+// the regular cycle, so their metrics are only sent with it.
+func (r *MetricRunner) runCritical(ctx context.Context, taskQueue chan<- *model.MetricPayload) {
+	config.RLock()
+	rules := r.Config.Agent.MetricCollection.Critical.Rules
+	interval := r.Config.Agent.MetricCollection.Critical.CheckInterval
+	config.RUnlock()
+	if interval <= 0 {
+		interval = defaultCriticalCheckInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	utils.Info("Critical metric fast path enabled: %d rules, checking every %v", len(rules), interval)
+
+	// crossed tracks the last known state per metric series; series missing
+	// from a check are forgotten
+	crossed := make(map[string]bool)
+	// skipped holds the producers already reported as not re-collectable
+	skipped := make(map[string]bool)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case tick := <-ticker.C:
+			collectors := r.recollectable(r.MetricRegistry.Producers(func(m model.Metric) bool {
+				return matchCritical(rules, m) != nil
+			}), skipped)
+			if len(collectors) == 0 {
+				clear(crossed)
+				continue
+			}
+			config.RLock()
+
+			var changed []model.Metric
+			seen := make(map[string]bool, len(crossed))
+			for _, m := range r.relabel.apply(r.Config.Agent.MetricCollection.Relabel, r.MetricRegistry.CollectFrom(ctx, collectors)) {
+				rule := matchCritical(rules, m)
+				if rule == nil {
+					continue
+				}
+
+				key := agentutils.SeriesKey(m)
+				seen[key] = true
+				now := rule.Crossed(m.Value)
+				// Unseen series count as not crossed, so a first check only sends crossed values
+				if now == crossed[key] {
+					continue
+				}
+				crossed[key] = now
+
+				if now {
+					utils.Info("Critical metric %s crossed threshold (value %g); sending immediately", metricName(m), m.Value)
+				} else {
+					utils.Info("Critical metric %s back within threshold (value %g)", metricName(m), m.Value)
+				}
+				changed = append(changed, m)
+			}
+
+			for key := range crossed {
+				if !seen[key] {
+					delete(crossed, key)
+				}
+			}
+
+			if len(changed) > 0 {
+				r.dispatch(ctx, taskQueue, changed, 0, tick, true)
+			}
+			config.RUnlock()
+		}
+	}
+}
+
+// recollectable returns the names that may be collected outside the regular
+// cycle, logging each buffered or stateful producer once.
+func (r *MetricRunner) recollectable(names []string, skipped map[string]bool) []string {
+	active := r.MetricRegistry.Active()
+	out := names[:0]
+	for _, name := range names {
+		c, ok := active[name]
+		if !ok {
+			continue
+		}
+		if metriccollector.IsBuffered(c) || metriccollector.IsStateful(c) {
+			if !skipped[name] {
+				utils.Warn("Critical rules match metrics from collector %s, which cannot be re-collected between cycles; they are sent with the regular cycle", name)
+				skipped[name] = true
+			}
+			continue
+		}
+		out = append(out, name)
+	}
+	return out
+}
+
+// matchCritical returns the first rule matching the metric, or nil.
+func matchCritical(rules []config.CriticalRule, m model.Metric) *config.CriticalRule {
+	name := metricName(m)
+	for i := range rules {
+		if matchPattern(rules[i].Match, name) {
+			return &rules[i]
+		}
+	}
+	return nil
+}
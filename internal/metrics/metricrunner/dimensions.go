@@ -0,0 +1,80 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+// agent/internal/metrics/metricrunner/dimensions.go
+package metricrunner
+
+import (
+	agentutils "github.com/aaronlmathis/gosight-agent/internal/utils"
+	"github.com/aaronlmathis/gosight-shared/model"
+)
+
+// applyDefaultDimensions gives every metric without dimensions a copy of
+// defaults, so dimensionless series (e.g. system.cpu.count_logical) stay
+// distinct from same-named series in other scopes on backends that index by
+// dimensions alone. Metrics that already carry dimensions are left untouched.
+func applyDefaultDimensions(defaults map[string]string, metrics []model.Metric) {
+	if len(defaults) == 0 {
+		return
+	}
+	for i := range metrics {
+		if len(metrics[i].Dimensions) > 0 {
+			continue
+		}
+		dims := make(map[string]string, len(defaults))
+		for k, v := range defaults {
+			dims[k] = v
+		}
+		metrics[i].Dimensions = dims
+	}
+}
+
+// mergeDefaultDimensions adds defaults to the dimensions of every metric.
+// A key the collector already set keeps its value. Each metric gets its own
+// map, since collectors may share one dimensions map across metrics.
+func mergeDefaultDimensions(defaults map[string]string, metrics []model.Metric) {
+	if len(defaults) == 0 {
+		return
+	}
+	for i := range metrics {
+		dims := make(map[string]string, len(defaults)+len(metrics[i].Dimensions))
+		for k, v := range defaults {
+			dims[k] = v
+		}
+		for k, v := range metrics[i].Dimensions {
+			dims[k] = v
+		}
+		metrics[i].Dimensions = dims
+	}
+}
+
+// applySeriesFingerprint adds each metric's series fingerprint (see
+// agentutils.SeriesFingerprint) as the series_id dimension. It runs after the
+// default dimensions are applied so the fingerprint covers the final series.
+func applySeriesFingerprint(metrics []model.Metric) {
+	for i := range metrics {
+		dims := make(map[string]string, len(metrics[i].Dimensions)+1)
+		for k, v := range metrics[i].Dimensions {
+			dims[k] = v
+		}
+		dims[agentutils.SeriesFingerprintDimension] = agentutils.SeriesFingerprint(metrics[i])
+		metrics[i].Dimensions = dims
+	}
+}
@@ -0,0 +1,84 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+package metricrunner
+
+import (
+	"testing"
+
+	agentutils "github.com/aaronlmathis/gosight-agent/internal/utils"
+	"github.com/aaronlmathis/gosight-shared/model"
+)
+
+func TestMergeDefaultDimensions(t *testing.T) {
+	shared := map[string]string{"mountpoint": "/", "team": "storage"}
+	metrics := []model.Metric{
+		{Name: "count_logical"},
+		{Name: "used_percent", Dimensions: shared},
+		{Name: "free", Dimensions: shared},
+	}
+
+	mergeDefaultDimensions(map[string]string{"team": "payments", "tier": "web"}, metrics)
+
+	if d := metrics[0].Dimensions; d["team"] != "payments" || d["tier"] != "web" || len(d) != 2 {
+		t.Errorf("dimensionless metric got %v", d)
+	}
+	// The collector's team wins over the default
+	if d := metrics[1].Dimensions; d["team"] != "storage" || d["tier"] != "web" || d["mountpoint"] != "/" {
+		t.Errorf("collector dimensions not preferred: %v", d)
+	}
+	// The collector's shared map is not modified
+	if len(shared) != 2 {
+		t.Errorf("shared dimensions map was modified: %v", shared)
+	}
+	metrics[1].Dimensions["x"] = "y"
+	if _, leaked := metrics[2].Dimensions["x"]; leaked {
+		t.Error("metrics share a merged dimensions map")
+	}
+}
+
+func TestApplySeriesFingerprint(t *testing.T) {
+	dims := map[string]string{"mountpoint": "/", "device": "sda1"}
+	metrics := []model.Metric{
+		{Namespace: "System", SubNamespace: "Disk", Name: "used_percent", Value: 10, Dimensions: dims},
+		{Namespace: "system", SubNamespace: "disk", Name: "used_percent", Value: 99, Dimensions: map[string]string{"device": "sda1", "mountpoint": "/"}},
+		{Namespace: "System", SubNamespace: "Disk", Name: "used_percent", Dimensions: map[string]string{"mountpoint": "/boot"}},
+	}
+
+	applySeriesFingerprint(metrics)
+
+	id := metrics[0].Dimensions[agentutils.SeriesFingerprintDimension]
+	if len(id) != 16 {
+		t.Fatalf("fingerprint %q is not 16 hex digits", id)
+	}
+	if metrics[1].Dimensions[agentutils.SeriesFingerprintDimension] != id {
+		t.Error("same series with a different value or dimension order got a different fingerprint")
+	}
+	if metrics[2].Dimensions[agentutils.SeriesFingerprintDimension] == id {
+		t.Error("different series got the same fingerprint")
+	}
+	if agentutils.SeriesFingerprint(metrics[0]) != id {
+		t.Error("fingerprint changed after series_id was attached")
+	}
+	if _, modified := dims[agentutils.SeriesFingerprintDimension]; modified {
+		t.Error("collector dimensions map was modified")
+	}
+}
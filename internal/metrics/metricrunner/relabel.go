@@ -0,0 +1,120 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+// agent/internal/metrics/metricrunner/relabel.go
+package metricrunner
+
+import (
+	"regexp"
+	"sync"
+
+	"github.com/aaronlmathis/gosight-agent/internal/config"
+	"github.com/aaronlmathis/gosight-shared/model"
+	"github.com/aaronlmathis/gosight-shared/utils"
+)
+
+// relabeler applies agent.metric_collection.relabel rules. Rules are read
+// from the config on every call so a reload takes effect; compiled regexes
+// are cached by pattern.
+type relabeler struct {
+	mu    sync.Mutex
+	regex map[string]*regexp.Regexp
+}
+
+// apply runs the rules in order over every metric and returns the metrics
+// that were not dropped. The slice is filtered in place; a metric's
+// Dimensions map is copied before its first change, as collectors may share
+// one map between metrics.
+func (l *relabeler) apply(rules []config.RelabelRule, metrics []model.Metric) []model.Metric {
+	if len(rules) == 0 {
+		return metrics
+	}
+
+	kept := metrics[:0]
+next:
+	for _, m := range metrics {
+		copied := false
+		for _, rule := range rules {
+			if rule.Match != "" && !matchPattern(rule.Match, metricName(m)) {
+				continue
+			}
+
+			switch rule.Action {
+			case "drop":
+				if rule.Regex == "" || l.matches(rule.Regex, m.Dimensions[rule.Dimension]) {
+					continue next
+				}
+			case "rename_dimension":
+				v, ok := m.Dimensions[rule.Dimension]
+				if !ok {
+					continue
+				}
+				copied = copyDimensions(&m, copied)
+				delete(m.Dimensions, rule.Dimension)
+				m.Dimensions[rule.NewName] = v
+			case "set_dimension":
+				copied = copyDimensions(&m, copied)
+				m.Dimensions[rule.Dimension] = rule.Value
+			case "remove_dimension":
+				if _, ok := m.Dimensions[rule.Dimension]; !ok {
+					continue
+				}
+				copied = copyDimensions(&m, copied)
+				delete(m.Dimensions, rule.Dimension)
+			}
+		}
+		kept = append(kept, m)
+	}
+	return kept
+}
+
+// copyDimensions gives m its own Dimensions map unless already done.
+func copyDimensions(m *model.Metric, copied bool) bool {
+	if copied {
+		return true
+	}
+	dims := make(map[string]string, len(m.Dimensions)+1)
+	for k, v := range m.Dimensions {
+		dims[k] = v
+	}
+	m.Dimensions = dims
+	return true
+}
+
+// matches reports whether value fully matches pattern. An invalid pattern
+// (rejected by config validation) matches nothing.
+func (l *relabeler) matches(pattern, value string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	re, ok := l.regex[pattern]
+	if !ok {
+		var err error
+		re, err = regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			utils.Warn("Invalid relabel regex %q: %v", pattern, err)
+		}
+		if l.regex == nil {
+			l.regex = make(map[string]*regexp.Regexp)
+		}
+		l.regex[pattern] = re
+	}
+	return re != nil && re.MatchString(value)
+}
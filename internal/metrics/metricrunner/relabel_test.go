@@ -0,0 +1,71 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+package metricrunner
+
+import (
+	"testing"
+
+	"github.com/aaronlmathis/gosight-agent/internal/config"
+	"github.com/aaronlmathis/gosight-shared/model"
+)
+
+func TestRelabel(t *testing.T) {
+	rules := []config.RelabelRule{
+		{Match: "system.cpu.*", Action: "drop", Dimension: "core", Regex: "core[0-9]+"},
+		{Match: "system.disk.*", Action: "drop", Dimension: "fstype", Regex: "tmpfs|overlay"},
+		{Action: "rename_dimension", Dimension: "mountpoint", NewName: "mount"},
+		{Action: "set_dimension", Dimension: "team", Value: "infra"},
+		{Match: "system.disk.*", Action: "remove_dimension", Dimension: "fstype"},
+	}
+
+	shared := map[string]string{"mountpoint": "/", "fstype": "ext4"}
+	metrics := []model.Metric{
+		{Namespace: "System", SubNamespace: "CPU", Name: "usage_percent", Dimensions: map[string]string{"core": "core3"}},
+		{Namespace: "System", SubNamespace: "CPU", Name: "usage_percent", Dimensions: map[string]string{"core": "total"}},
+		{Namespace: "System", SubNamespace: "Disk", Name: "used_percent", Dimensions: map[string]string{"mountpoint": "/run", "fstype": "tmpfs"}},
+		{Namespace: "System", SubNamespace: "Disk", Name: "used_percent", Dimensions: shared},
+		{Namespace: "System", SubNamespace: "Disk", Name: "free_bytes", Dimensions: shared},
+	}
+
+	var l relabeler
+	got := l.apply(rules, metrics)
+	if len(got) != 3 {
+		t.Fatalf("expected 3 metrics after drops, got %d: %+v", len(got), got)
+	}
+	if got[0].Dimensions["core"] != "total" || got[0].Dimensions["team"] != "infra" {
+		t.Errorf("unexpected cpu total dimensions: %v", got[0].Dimensions)
+	}
+	for _, m := range got[1:] {
+		if m.Dimensions["mount"] != "/" || m.Dimensions["team"] != "infra" {
+			t.Errorf("unexpected disk dimensions: %v", m.Dimensions)
+		}
+		if _, ok := m.Dimensions["fstype"]; ok {
+			t.Errorf("expected fstype to be removed: %v", m.Dimensions)
+		}
+		if _, ok := m.Dimensions["mountpoint"]; ok {
+			t.Errorf("expected mountpoint to be renamed: %v", m.Dimensions)
+		}
+	}
+	if shared["mountpoint"] != "/" || shared["fstype"] != "ext4" || len(shared) != 2 {
+		t.Errorf("expected a collector's shared dimension map to be left untouched, got %v", shared)
+	}
+}
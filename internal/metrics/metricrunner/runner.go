@@ -30,6 +30,8 @@ import (
 	"github.com/aaronlmathis/gosight-agent/internal/meta"
 	"github.com/aaronlmathis/gosight-agent/internal/metrics/metriccollector"
 	"github.com/aaronlmathis/gosight-agent/internal/metrics/metricsender"
+	"github.com/aaronlmathis/gosight-agent/internal/selfmetrics"
+	agentutils "github.com/aaronlmathis/gosight-agent/internal/utils"
 	"github.com/aaronlmathis/gosight-shared/model"
 	"github.com/aaronlmathis/gosight-shared/utils"
 )
@@ -44,6 +46,20 @@ type MetricRunner struct {
 	MetricRegistry *metriccollector.MetricRegistry
 	StartTime      time.Time
 	Meta           *model.Meta
+
+	// sequence numbers collection cycles; see meta.StampHeartbeat
+	sequence uint64
+
+	// warnedEmpty is set once the configured collectors start producing
+	// nothing, so an agent sending only its self-metrics is explained with a
+	// single warning
+	warnedEmpty bool
+
+	// cardinality caps the number of distinct series; nil when unlimited
+	cardinality *cardinalityLimiter
+
+	// relabel applies the relabel rules to every collected metric
+	relabel relabeler
 }
 
 // NewRunner creates a new MetricRunner instance.
@@ -53,7 +69,10 @@ type MetricRunner struct {
 func NewRunner(ctx context.Context, cfg *config.Config, baseMeta *model.Meta) (*MetricRunner, error) {
 
 	// Init the collector registry
-	metricRegistry := metriccollector.NewRegistry(cfg)
+	metricRegistry, err := metriccollector.NewRegistry(cfg)
+	if err != nil {
+		return nil, err
+	}
 
 	// Init Metric Sender
 	metricSender, err := metricsender.NewSender(ctx, cfg)
@@ -67,6 +86,7 @@ func NewRunner(ctx context.Context, cfg *config.Config, baseMeta *model.Meta) (*
 		MetricRegistry: metricRegistry,
 		StartTime:      time.Now(),
 		Meta:           baseMeta,
+		cardinality:    newCardinalityLimiter(cfg.Agent.MetricCollection.MaxSeries),
 	}, nil
 }
 
@@ -89,131 +109,292 @@ func (r *MetricRunner) Run(ctx context.Context) {
 	defer r.MetricSender.Close()
 
 	taskQueue := make(chan *model.MetricPayload, 500)
-	go r.MetricSender.StartWorkerPool(ctx, taskQueue, r.Config.Agent.MetricCollection.Workers)
+	selfmetrics.TrackQueue("metrics", taskQueue)
+	workerCtx, stopWorkers := agentutils.WorkerContext(ctx)
+
+	config.RLock()
+	workers := r.Config.Agent.MetricCollection.Workers
+	critical := len(r.Config.Agent.MetricCollection.Critical.Rules) > 0
+	interval := r.Config.Agent.MetricCollection.Interval
+	deadline, markers := r.Config.Agent.CycleDeadline, r.Config.Agent.CycleMarkers
+	config.RUnlock()
+
+	go r.MetricSender.StartWorkerPool(workerCtx, taskQueue, workers)
+
+	if critical {
+		go r.runCritical(ctx, taskQueue)
+	}
 
-	ticker := time.NewTicker(r.Config.Agent.MetricCollection.Interval)
+	// Collectors with their own interval (collector_intervals) may need more
+	// frequent ticks than the global interval; they only run when due
+	tickInterval := r.MetricRegistry.TickInterval()
+	ticker := time.NewTicker(tickInterval)
 	defer ticker.Stop()
 
-	utils.Info("MetricRunner started. Sending metrics every %v", r.Config.Agent.MetricCollection.Interval)
+	utils.Info("MetricRunner started. Sending metrics every %v", interval)
+
+	cycles := selfmetrics.NewCycleTracker("metrics", tickInterval, deadline)
+	cycles.EnableMarkers(markers)
 
 	for {
 		select {
 		case <-ctx.Done():
 			utils.Warn("agent shutting down...")
+			config.RLock()
+			timeout := r.Config.Agent.ShutdownDrainTimeout
+			config.RUnlock()
+			agentutils.DrainQueue("metrics", taskQueue, timeout, stopWorkers)
 			return
-		case <-ticker.C:
-			metrics, err := r.MetricRegistry.Collect(ctx)
-			if err != nil {
-				utils.Error("metric collection failed: %v", err)
+		case tick := <-ticker.C:
+			// A config reload may have changed the intervals
+			if iv := r.MetricRegistry.TickInterval(); iv != tickInterval {
+				utils.Info("Metric tick interval changed from %v to %v", tickInterval, iv)
+				tickInterval = iv
+				ticker.Reset(iv)
+			}
+			if !r.MetricRegistry.Due(time.Now()) {
 				continue
 			}
+			start := cycles.Begin()
+			config.RLock()
+			counts := r.collectCycle(ctx, taskQueue, tick)
+			config.RUnlock()
+			cycles.End(start, counts)
+		}
+	}
+}
 
-			var hostMetrics []model.Metric
-			containerBatches := make(map[string][]model.Metric)
-			containerMetas := make(map[string]*model.Meta)
-
-			for _, m := range metrics {
-
-				if len(m.Dimensions) > 0 && m.Dimensions["container_id"] != "" {
-					id := m.Dimensions["container_id"]
-					if id == "" {
-						continue
-					}
-					// Add container metrics to containerBatches
-					containerBatches[id] = append(containerBatches[id], m)
-
-					// Initialize Meta only once per container ID
-					containerMeta, exists := containerMetas[id]
-					if !exists {
-						containerMeta = meta.CloneMetaWithTags(r.Meta, nil)
-						containerMetas[id] = containerMeta
-					}
-
-					// TODO metric runner add k8 namespace / cluster support
-					// Populate meta with container-specific information
-					for k, v := range m.Dimensions {
-						switch k {
-						case "container_id":
-							containerMeta.ContainerID = v
-						case "name", "container_name":
-							containerMeta.ContainerName = v
-						case "image_id":
-							containerMeta.ContainerImageID = v
-						case "image":
-							containerMeta.ContainerImageName = v
-						}
-					}
-
-					// Detect running status and apply tag
-					if m.Name == "running" {
-						if m.Value == 1 {
-							containerMeta.Tags["status"] = "running"
-						} else {
-							containerMeta.Tags["status"] = "stopped"
-						}
-					}
-					// Build tags for the container
-					meta.BuildStandardTags(containerMeta, m, true, r.StartTime)
-
-					// Set EndpointID for meta
-					containerMeta.EndpointID = utils.GenerateEndpointID(containerMeta)
-					containerMeta.Kind = "container"
+// collectCycle collects all metrics once and queues them for sending. It
+// returns the cycle's counts for the cycle markers.
+func (r *MetricRunner) collectCycle(ctx context.Context, taskQueue chan<- *model.MetricPayload, tick time.Time) selfmetrics.CycleCounts {
+	var counts selfmetrics.CycleCounts
 
-				} else {
-					// Host metrics, collect them separately
-					hostMetrics = append(hostMetrics, m)
-				}
-			}
+	metrics, err := r.MetricRegistry.Collect(ctx)
+	if err != nil {
+		utils.Error("metric collection failed: %v", err)
+		return counts
+	}
+	counts.Collected = len(metrics)
+	selfmetrics.RecordCollected(len(metrics))
 
-			// Send host metrics as a single payload
-			if len(hostMetrics) > 0 {
+	if r.Config.Agent.MetricCollection.AlignTimestamps {
+		ts := r.alignedTimestamp(tick)
+		for i := range metrics {
+			metrics[i].Timestamp = ts
+		}
+	}
 
-				// Build Host Meta
-				hostMeta := meta.CloneMetaWithTags(r.Meta, nil)
+	// The self-metric collectors always report, so judge the configured ones
+	if n := r.MetricRegistry.SourceMetrics(); n == 0 {
+		if !r.warnedEmpty {
+			utils.Warn("Metric collectors produced zero metrics; only the agent's self-metrics will be sent. Collectors: %s", r.MetricRegistry.Diagnostics())
+			r.warnedEmpty = true
+		}
+	} else if r.warnedEmpty {
+		utils.Info("Metric collection recovered: %d metrics collected", n)
+		r.warnedEmpty = false
+	}
+	if len(metrics) == 0 {
+		return counts
+	}
 
-				// Build tags
-				meta.BuildStandardTags(hostMeta, hostMetrics[0], false, r.StartTime)
+	metrics = r.relabel.apply(r.Config.Agent.MetricCollection.Relabel, metrics)
+	metrics = applyThresholds(r.Config.Agent.MetricCollection.Thresholds, metrics)
+	metrics = r.cardinality.apply(metrics, time.Now(), r.MetricRegistry.LongestInterval())
+	counts.Dropped = counts.Collected - len(metrics)
+	if len(metrics) == 0 {
+		return counts
+	}
 
-				// Set EndpointID for meta
-				hostMeta.EndpointID = utils.GenerateEndpointID(hostMeta)
-				hostMeta.Kind = "host"
+	// Every payload from this cycle carries the same sequence and collection time
+	r.sequence++
 
-				payload := model.MetricPayload{
-					AgentID:    hostMeta.AgentID,
-					HostID:     hostMeta.HostID,
-					Hostname:   hostMeta.Hostname,
-					EndpointID: hostMeta.EndpointID,
-					Timestamp:  time.Now(),
-					Metrics:    hostMetrics,
-					Meta:       hostMeta,
-				}
-				//utils.Info("META Payload for: %s - %v", payload.Host, payload.Meta)
-				select {
-				case taskQueue <- &payload:
-				default:
-					utils.Warn("Host task queue full! Dropping host metrics")
-				}
+	queued, dropped := r.dispatch(ctx, taskQueue, metrics, r.sequence, tick, false)
+	counts.Queued = queued
+	counts.Dropped += dropped
+	return counts
+}
+
+// dispatch splits metrics into a host payload and one payload per container and
+// queues them for sending. When seq is non-zero every payload is stamped with
+// the cycle heartbeat (see meta.StampHeartbeat). Urgent payloads wait for queue
+// space instead of being dropped when the queue is full. It returns how many
+// metrics were queued and how many were dropped because the queue was full.
+func (r *MetricRunner) dispatch(ctx context.Context, taskQueue chan<- *model.MetricPayload, metrics []model.Metric, seq uint64, collectedAt time.Time, urgent bool) (queued, dropped int) {
+	applyDefaultDimensions(r.Config.Agent.MetricCollection.DefaultDimensions, metrics)
+	mergeDefaultDimensions(r.Config.Agent.DefaultDimensions, metrics)
+	if r.Config.Agent.MetricCollection.SeriesFingerprint {
+		applySeriesFingerprint(metrics)
+	}
+
+	var hostMetrics []model.Metric
+	containerBatches := make(map[string][]model.Metric)
+	containerMetas := make(map[string]*model.Meta)
+
+	for _, m := range metrics {
+
+		if len(m.Dimensions) > 0 && m.Dimensions["container_id"] != "" {
+			id := m.Dimensions["container_id"]
+			if id == "" {
+				continue
+			}
+			// Add container metrics to containerBatches
+			containerBatches[id] = append(containerBatches[id], m)
+
+			// Initialize Meta only once per container ID
+			containerMeta, exists := containerMetas[id]
+			if !exists {
+				containerMeta = meta.CloneMetaWithTags(r.Meta, nil)
+				containerMetas[id] = containerMeta
 			}
 
-			// Send each container as a separate payload
-			for id, metrics := range containerBatches {
-				payload := model.MetricPayload{
-					AgentID:    containerMetas[id].AgentID,
-					HostID:     containerMetas[id].HostID,
-					Hostname:   containerMetas[id].Hostname,
-					EndpointID: containerMetas[id].EndpointID,
-					Timestamp:  time.Now(),
-					Metrics:    metrics,
-					Meta:       containerMetas[id],
+			// TODO metric runner add k8 namespace / cluster support
+			// Populate meta with container-specific information
+			for k, v := range m.Dimensions {
+				switch k {
+				case "container_id":
+					containerMeta.ContainerID = v
+				case "name", "container_name":
+					containerMeta.ContainerName = v
+				case "image_id":
+					containerMeta.ContainerImageID = v
+				case "image":
+					containerMeta.ContainerImageName = v
+				// Container labels promoted via container_label_fields
+				case "service":
+					containerMeta.Service = v
+				case "application":
+					containerMeta.Application = v
+				case "environment":
+					containerMeta.Environment = v
+				case "version":
+					containerMeta.Version = v
+				case "deployment_id":
+					containerMeta.DeploymentID = v
 				}
-				//utils.Info("META Payload for: %s - %s - %s - %v", payload.HostID, payload.AgentID, payload.Hostname, payload.Meta)
+			}
 
-				select {
-				case taskQueue <- &payload:
-				default:
-					utils.Warn("Task queue full! Dropping container metrics for %s", id)
+			// Detect running status and apply tag
+			if m.Name == "running" {
+				if m.Value == 1 {
+					containerMeta.Tags["status"] = "running"
+				} else {
+					containerMeta.Tags["status"] = "stopped"
 				}
 			}
+			// Build tags for the container
+			meta.BuildStandardTags(containerMeta, m, true, r.StartTime)
+
+			// Set EndpointID for meta
+			containerMeta.EndpointID = meta.GenerateEndpointID(containerMeta)
+			containerMeta.Kind = "container"
+
+		} else {
+			// Host metrics, collect them separately
+			hostMetrics = append(hostMetrics, m)
+		}
+	}
+
+	// Send host metrics as a single payload
+	if len(hostMetrics) > 0 {
+
+		// Build Host Meta
+		hostMeta := meta.CloneMetaWithTags(r.Meta, nil)
+
+		// Build tags
+		meta.BuildStandardTags(hostMeta, hostMetrics[0], false, r.StartTime)
+
+		// Set EndpointID for meta
+		hostMeta.EndpointID = meta.GenerateEndpointID(hostMeta)
+		hostMeta.Kind = "host"
+		if seq > 0 {
+			meta.StampHeartbeat(hostMeta, seq, collectedAt)
+		}
+
+		payload := model.MetricPayload{
+			AgentID:    hostMeta.AgentID,
+			HostID:     hostMeta.HostID,
+			Hostname:   hostMeta.Hostname,
+			EndpointID: hostMeta.EndpointID,
+			Timestamp:  time.Now(),
+			Metrics:    hostMetrics,
+			Meta:       hostMeta,
+		}
+		//utils.Info("META Payload for: %s - %v", payload.Host, payload.Meta)
+		if r.enqueue(ctx, taskQueue, &payload, urgent, "host") {
+			queued += len(hostMetrics)
+		} else {
+			dropped += len(hostMetrics)
+		}
+	}
+
+	// Send each container as a separate payload
+	for id, metrics := range containerBatches {
+		if seq > 0 {
+			meta.StampHeartbeat(containerMetas[id], seq, collectedAt)
+		}
+		payload := model.MetricPayload{
+			AgentID:    containerMetas[id].AgentID,
+			HostID:     containerMetas[id].HostID,
+			Hostname:   containerMetas[id].Hostname,
+			EndpointID: containerMetas[id].EndpointID,
+			Timestamp:  time.Now(),
+			Metrics:    metrics,
+			Meta:       containerMetas[id],
+		}
+		//utils.Info("META Payload for: %s - %s - %s - %v", payload.HostID, payload.AgentID, payload.Hostname, payload.Meta)
+
+		if r.enqueue(ctx, taskQueue, &payload, urgent, "container "+id) {
+			queued += len(metrics)
+		} else {
+			dropped += len(metrics)
+		}
+	}
+	return queued, dropped
+}
+
+// enqueue queues a payload and reports whether it was queued. Regular
+// payloads are dropped with a warning if the queue is full; urgent ones block
+// until there is room or ctx is done. The caller holds the config read lock,
+// which is released while an urgent payload waits: the senders emptying the
+// queue may need it.
+func (r *MetricRunner) enqueue(ctx context.Context, taskQueue chan<- *model.MetricPayload, payload *model.MetricPayload, urgent bool, what string) bool {
+	if urgent {
+		select {
+		case taskQueue <- payload:
+			return true
+		default:
+		}
+		config.RUnlock()
+		defer config.RLock()
+		select {
+		case taskQueue <- payload:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	select {
+	case taskQueue <- payload:
+		return true
+	default:
+		utils.Warn("Task queue full! Dropping %s metrics", what)
+		selfmetrics.RecordDropped("metrics", len(payload.Metrics))
+		return false
+	}
+}
+
+// alignedTimestamp returns the single timestamp used for every metric in a cycle
+// when align_timestamps is enabled, rounded down per timestamp_round.
+func (r *MetricRunner) alignedTimestamp(tick time.Time) time.Time {
+	switch r.Config.Agent.MetricCollection.TimestampRound {
+	case "second":
+		return tick.Truncate(time.Second)
+	case "interval":
+		if interval := r.Config.Agent.MetricCollection.Interval; interval > 0 {
+			return tick.Truncate(interval)
 		}
 	}
+	return tick
 }
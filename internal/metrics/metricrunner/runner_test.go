@@ -0,0 +1,61 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/metrics/metricrunner/runner_test.go
+
+package metricrunner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aaronlmathis/gosight-agent/internal/config"
+	"github.com/aaronlmathis/gosight-agent/internal/metrics/metriccollector"
+	"github.com/aaronlmathis/gosight-shared/model"
+)
+
+// emptyCollector is a configured source that produces nothing.
+type emptyCollector struct{}
+
+func (emptyCollector) Name() string { return "empty" }
+
+func (emptyCollector) Collect(_ context.Context) ([]model.Metric, error) { return nil, nil }
+
+func TestCollectCycleWarnsWithOnlySelfMetrics(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Agent.MetricCollection.Interval = 10 * time.Second
+
+	reg, err := metriccollector.NewRegistry(cfg)
+	if err != nil {
+		t.Fatalf("NewRegistry: %v", err)
+	}
+	// The self-metric collectors stay registered alongside the empty source
+	reg.Collectors["empty"] = emptyCollector{}
+
+	r := &MetricRunner{Config: cfg, MetricRegistry: reg, Meta: &model.Meta{}}
+	queue := make(chan *model.MetricPayload, 10)
+
+	r.collectCycle(context.Background(), queue, time.Now())
+	if !r.warnedEmpty {
+		t.Error("expected a warning when only the self-metric collectors produce metrics")
+	}
+}
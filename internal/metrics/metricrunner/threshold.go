@@ -0,0 +1,76 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+// agent/internal/metrics/metricrunner/threshold.go
+package metricrunner
+
+import (
+	"path"
+	"strings"
+
+	"github.com/aaronlmathis/gosight-agent/internal/config"
+	"github.com/aaronlmathis/gosight-shared/model"
+)
+
+// applyThresholds drops metrics whose value falls inside the ignore range of
+// the first threshold filter matching their name. Metrics matching no filter
+// are kept. The slice is filtered in place.
+func applyThresholds(filters []config.ThresholdFilter, metrics []model.Metric) []model.Metric {
+	if len(filters) == 0 {
+		return metrics
+	}
+
+	kept := metrics[:0]
+	for _, m := range metrics {
+		if f := matchThreshold(filters, m); f != nil && f.Ignores(m.Value) {
+			continue
+		}
+		kept = append(kept, m)
+	}
+	return kept
+}
+
+// matchThreshold returns the first filter whose pattern matches the metric's
+// lowercased "namespace.subnamespace.name", or nil.
+func matchThreshold(filters []config.ThresholdFilter, m model.Metric) *config.ThresholdFilter {
+	name := metricName(m)
+	for i := range filters {
+		if matchPattern(filters[i].Match, name) {
+			return &filters[i]
+		}
+	}
+	return nil
+}
+
+// metricName returns the lowercased "namespace.subnamespace.name" that
+// threshold and critical patterns are matched against.
+func metricName(m model.Metric) string {
+	parts := []string{m.Namespace}
+	if m.SubNamespace != "" {
+		parts = append(parts, m.SubNamespace)
+	}
+	return strings.ToLower(strings.Join(append(parts, m.Name), "."))
+}
+
+// matchPattern reports whether the glob pattern matches a metricName, case-insensitively.
+func matchPattern(pattern, name string) bool {
+	ok, _ := path.Match(strings.ToLower(pattern), name)
+	return ok
+}
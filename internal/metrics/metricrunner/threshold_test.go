@@ -0,0 +1,57 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+package metricrunner
+
+import (
+	"testing"
+
+	"github.com/aaronlmathis/gosight-agent/internal/config"
+	"github.com/aaronlmathis/gosight-shared/model"
+)
+
+func TestApplyThresholds(t *testing.T) {
+	seventy := 70.0
+	zero := 0.0
+	filters := []config.ThresholdFilter{
+		{Match: "system.disk.used_percent", IgnoreMax: &seventy},
+		{Match: "system.*.errors_*", IgnoreMin: &zero, IgnoreMax: &zero},
+	}
+
+	metrics := []model.Metric{
+		{Namespace: "System", SubNamespace: "Disk", Name: "used_percent", Value: 42},
+		{Namespace: "System", SubNamespace: "Disk", Name: "used_percent", Value: 85},
+		{Namespace: "System", SubNamespace: "Network", Name: "errors_in", Value: 0},
+		{Namespace: "System", SubNamespace: "Network", Name: "errors_out", Value: 3},
+		{Namespace: "System", SubNamespace: "CPU", Name: "usage_percent", Value: 10},
+	}
+
+	got := applyThresholds(filters, metrics)
+	want := []float64{85, 3, 10}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d metrics, got %d: %+v", len(want), len(got), got)
+	}
+	for i, v := range want {
+		if got[i].Value != v {
+			t.Errorf("metric %d: expected value %v, got %v", i, v, got[i].Value)
+		}
+	}
+}
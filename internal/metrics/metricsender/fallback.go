@@ -0,0 +1,120 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/metrics/metricsender/fallback.go
+// Sends metrics over the legacy command stream while OTLP export is failing.
+
+package metricsender
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aaronlmathis/gosight-agent/internal/config"
+	"github.com/aaronlmathis/gosight-agent/internal/protohelper"
+	"github.com/aaronlmathis/gosight-agent/internal/selfmetrics"
+	"github.com/aaronlmathis/gosight-shared/model"
+	"github.com/aaronlmathis/gosight-shared/proto"
+	"github.com/aaronlmathis/gosight-shared/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	goproto "google.golang.org/protobuf/proto"
+)
+
+// otlpProbeInterval is how often OTLP export is retried while in stream fallback.
+const otlpProbeInterval = time.Minute
+
+// fallbackEnabled reports whether metric_collection.stream_fallback is set.
+func (s *MetricSender) fallbackEnabled() bool {
+	config.RLock()
+	defer config.RUnlock()
+	return s.cfg.Agent.MetricCollection.StreamFallback
+}
+
+// enterFallback switches metric delivery to the legacy stream after OTLP
+// export failed exportFailureThreshold times in a row. It reports whether
+// fallback is now active.
+func (s *MetricSender) enterFallback() bool {
+	if !s.fallbackEnabled() {
+		return false
+	}
+	s.streamMu.Lock()
+	hasStream := s.stream != nil
+	s.streamMu.Unlock()
+	if !hasStream {
+		return false
+	}
+	if s.exportFailures.Load() < exportFailureThreshold {
+		return false
+	}
+	if !s.useStream.Swap(true) {
+		utils.Warn("OTLP metric export failed %d times in a row; sending metrics over the command stream until it recovers", s.exportFailures.Load())
+		s.lastOTLPProbe.Store(time.Now().UnixNano())
+	}
+	return true
+}
+
+// shouldProbeOTLP reports whether it is time to try OTLP again while in
+// fallback, and records the attempt.
+func (s *MetricSender) shouldProbeOTLP() bool {
+	last := s.lastOTLPProbe.Load()
+	if time.Since(time.Unix(0, last)) < otlpProbeInterval {
+		return false
+	}
+	return s.lastOTLPProbe.CompareAndSwap(last, time.Now().UnixNano())
+}
+
+// leaveFallback returns to OTLP export after a successful probe.
+func (s *MetricSender) leaveFallback() {
+	if s.useStream.Swap(false) {
+		utils.Info("OTLP metric export recovered; leaving command stream fallback")
+	}
+}
+
+// sendViaStream sends a payload as a legacy MetricPayload on the command stream.
+func (s *MetricSender) sendViaStream(payload *model.MetricPayload) error {
+	pb := protohelper.ConvertToProtoPayload(*payload)
+	b, err := goproto.Marshal(pb)
+	if err != nil {
+		return fmt.Errorf("marshal MetricPayload: %w", err)
+	}
+
+	sp := &proto.StreamPayload{
+		Payload: &proto.StreamPayload_Metric{
+			Metric: &proto.MetricWrapper{RawPayload: b},
+		},
+	}
+	selfmetrics.RecordExportSize("metrics", goproto.Size(sp))
+
+	s.streamMu.Lock()
+	defer s.streamMu.Unlock()
+
+	if s.stream == nil {
+		return status.Error(codes.Unavailable, "no active command stream")
+	}
+	if err := s.stream.Send(sp); err != nil {
+		utils.Warn("Metric stream fallback send failed: %v", err)
+		return err
+	}
+
+	utils.Debug("Sent %d metrics over the command stream (fallback)", len(payload.Metrics))
+	return nil
+}
@@ -0,0 +1,110 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+package metricsender
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"time"
+
+	"github.com/aaronlmathis/gosight-agent/internal/config"
+	grpcconn "github.com/aaronlmathis/gosight-agent/internal/grpc"
+	"github.com/aaronlmathis/gosight-agent/internal/otelconvert"
+	"github.com/aaronlmathis/gosight-shared/model"
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// PingResult describes the outcome of a one-shot connectivity check.
+type PingResult struct {
+	Server     string
+	Latency    time.Duration
+	StatusCode string
+	Rejected   int64
+
+	TLSVersion    string
+	CipherSuite   string
+	ServerSubject string
+	ServerIssuer  string
+	CertExpires   time.Time
+}
+
+// Ping dials the server with the agent's real TLS configuration, exports a
+// single payload, and blocks until the server acknowledges it or ctx expires.
+// Unlike NewSender it does not retry and does not open the command stream.
+func Ping(ctx context.Context, cfg *config.Config, payload *model.MetricPayload) (*PingResult, error) {
+	res := &PingResult{Server: cfg.Agent.ServerURL}
+
+	cc, err := grpcconn.GetGRPCConn(cfg)
+	if err != nil {
+		return res, fmt.Errorf("dial failed: %w", err)
+	}
+
+	// Wait for the transport (including the TLS handshake) to become ready.
+	// The first TRANSIENT_FAILURE means the dial or handshake failed; report it
+	// rather than waiting out gRPC's reconnect backoff.
+	cc.Connect()
+	for state := cc.GetState(); state != connectivity.Ready; state = cc.GetState() {
+		if state == connectivity.TransientFailure {
+			return res, fmt.Errorf("connection failed (TCP connect or TLS handshake); check server_url, firewall/proxy, and TLS files")
+		}
+		if !cc.WaitForStateChange(ctx, state) {
+			return res, fmt.Errorf("connection not ready (last state %s): %w", state, ctx.Err())
+		}
+	}
+
+	otlpReq := otelconvert.ConvertToOTLPMetrics(payload)
+	if otlpReq == nil {
+		return res, fmt.Errorf("failed to convert payload to OTLP")
+	}
+
+	var p peer.Peer
+	start := time.Now()
+	resp, err := colmetricpb.NewMetricsServiceClient(cc).Export(ctx, otlpReq, grpc.Peer(&p))
+	res.Latency = time.Since(start)
+	res.StatusCode = status.Code(err).String()
+
+	if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+		state := info.State
+		res.TLSVersion = tls.VersionName(state.Version)
+		res.CipherSuite = tls.CipherSuiteName(state.CipherSuite)
+		if len(state.PeerCertificates) > 0 {
+			cert := state.PeerCertificates[0]
+			res.ServerSubject = cert.Subject.String()
+			res.ServerIssuer = cert.Issuer.String()
+			res.CertExpires = cert.NotAfter
+		}
+	}
+
+	if err != nil {
+		return res, fmt.Errorf("export failed: %w", err)
+	}
+	if ps := resp.GetPartialSuccess(); ps != nil {
+		res.Rejected = ps.GetRejectedDataPoints()
+	}
+	return res, nil
+}
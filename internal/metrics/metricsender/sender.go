@@ -25,12 +25,17 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/aaronlmathis/gosight-agent/internal/command"
 	"github.com/aaronlmathis/gosight-agent/internal/config"
 	grpcconn "github.com/aaronlmathis/gosight-agent/internal/grpc"
 	"github.com/aaronlmathis/gosight-agent/internal/otelconvert"
+	"github.com/aaronlmathis/gosight-agent/internal/selfmetrics"
+	"github.com/aaronlmathis/gosight-agent/internal/sink"
+	"github.com/aaronlmathis/gosight-agent/internal/spool"
+	agentutils "github.com/aaronlmathis/gosight-agent/internal/utils"
 	"github.com/aaronlmathis/gosight-shared/model"
 	"github.com/aaronlmathis/gosight-shared/proto"
 	"github.com/aaronlmathis/gosight-shared/utils"
@@ -38,11 +43,18 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	goproto "google.golang.org/protobuf/proto"
 )
 
 const (
 	pauseDuration = 1 * time.Minute
 	totalCap      = 15 * time.Minute
+
+	// exportFailureThreshold is the number of consecutive failed exports after
+	// which the watchdog tears down the connection even if the command stream
+	// has not reported an error.
+	exportFailureThreshold = 5
+	watchdogInterval       = 10 * time.Second
 )
 
 // MetricSender handles OTLP metrics and control commands via dual connections.
@@ -58,6 +70,22 @@ type MetricSender struct {
 	wg  sync.WaitGroup
 	cfg *config.Config
 	ctx context.Context
+
+	// streamCancel cancels the current command stream; used by the watchdog
+	streamCancel context.CancelFunc
+
+	// exportFailures counts consecutive failed Export calls
+	exportFailures atomic.Int32
+
+	// Stream fallback state (metric_collection.stream_fallback, see fallback.go).
+	// streamMu serializes Send on the command stream and guards replacing
+	// it; the receive loop owns Recv.
+	streamMu      sync.Mutex
+	useStream     atomic.Bool
+	lastOTLPProbe atomic.Int64
+
+	// outbox spools exports while the server is unreachable (nil = disabled, see spool.go)
+	outbox *spool.Outbox
 }
 
 // NewSender returns immediately and starts a background connection manager.
@@ -66,20 +94,21 @@ func NewSender(ctx context.Context, cfg *config.Config) (*MetricSender, error) {
 		ctx: ctx,
 		cfg: cfg,
 	}
+	if sink.Enabled(cfg) {
+		// Dry run: print exports instead of connecting
+		s.metricsClient = sink.NewMetricsClient(nil)
+		utils.Info("Metric exports are printed to stdout; not connecting to the server")
+		return s, nil
+	}
+	s.openSpool()
 	go s.manageConnection()
 	return s, nil
 }
 
 // manageConnection dials/opens connections with backoff, handles global disconnects.
+// Backoff is shared with the other senders through grpcconn.ReportFailure and
+// grpcconn.WaitForRetry so an outage of the shared connection is retried once.
 func (s *MetricSender) manageConnection() {
-	const (
-		initial    = 1 * time.Second
-		maxBackoff = 15 * time.Minute
-		factor     = 2
-	)
-
-	backoff := initial
-
 	for {
 		// Check for context cancellation
 		select {
@@ -96,12 +125,8 @@ func (s *MetricSender) manageConnection() {
 		select {
 		case <-grpcconn.DisconnectNotify():
 			utils.Info("Global disconnect: closing metric connections")
-			if s.stream != nil {
-				_ = s.stream.CloseSend()
-			}
-			s.stream = nil
+			s.closeStream()
 			s.metricsClient = nil
-			backoff = initial
 			continue
 		default:
 		}
@@ -109,20 +134,10 @@ func (s *MetricSender) manageConnection() {
 		// Ensure we have a live ClientConn
 		cc, err := grpcconn.GetGRPCConn(s.cfg)
 		if err != nil {
-			utils.Info("Server offline (dial): retrying in %s", backoff)
-
-			select {
-			case <-time.After(backoff):
-			case <-s.ctx.Done():
+			grpcconn.ReportFailure("metrics dial")
+			if !grpcconn.WaitForRetry(s.ctx) {
 				return
 			}
-
-			if backoff < maxBackoff {
-				backoff = time.Duration(float64(backoff) * float64(factor))
-				if backoff > maxBackoff {
-					backoff = maxBackoff
-				}
-			}
 			continue
 		}
 
@@ -136,86 +151,163 @@ func (s *MetricSender) manageConnection() {
 
 		// Open the command stream if we don't have one yet
 		if s.stream == nil {
-			stream, err := s.streamClient.Stream(s.ctx)
+			streamCtx, streamCancel := context.WithCancel(s.ctx)
+			stream, err := s.streamClient.Stream(streamCtx)
 			if err != nil {
-				utils.Info("Server offline (command stream): retrying in %s", backoff)
+				streamCancel()
+				grpcconn.ReportFailure("metrics command stream")
 				s.metricsClient = nil
-				select {
-				case <-time.After(backoff):
-				case <-s.ctx.Done():
+				if !grpcconn.WaitForRetry(s.ctx) {
 					return
 				}
-
-				if backoff < maxBackoff {
-					backoff = time.Duration(float64(backoff) * float64(factor))
-					if backoff > maxBackoff {
-						backoff = maxBackoff
-					}
-				}
 				continue
 			}
-			s.stream = stream
+			s.setStream(stream)
+			s.streamCancel = streamCancel
+			s.exportFailures.Store(0)
 			utils.Info("Metrics OTLP client and command stream connected")
-			backoff = initial
+			grpcconn.ReportSuccess()
 		}
 
-		// Block in the receive loop until error or next disconnect
+		// Block in the receive loop until error or next disconnect,
+		// while the watchdog checks that exports are still succeeding
+		watchdogDone := make(chan struct{})
+		go s.exportWatchdog(watchdogDone, s.streamCancel)
 		s.manageReceive()
+		close(watchdogDone)
 
-		// On exit, close just the stream
-		if s.stream != nil {
-			_ = s.stream.CloseSend()
+		// On exit, close just the stream. Canceling it first unblocks a
+		// fallback Send holding streamMu
+		if s.streamCancel != nil {
+			s.streamCancel()
+			s.streamCancel = nil
 		}
-		s.stream = nil
+		s.closeStream()
 		s.metricsClient = nil
 
 		// Log and back off before the next full reconnect
-		utils.Info("Metrics connections lost: retrying connect in %s", backoff)
-
-		select {
-		case <-time.After(backoff):
-		case <-s.ctx.Done():
+		utils.Info("Metrics connections lost")
+		grpcconn.ReportFailure("metrics connection lost")
+		if !grpcconn.WaitForRetry(s.ctx) {
 			return
 		}
-
-		if backoff < maxBackoff {
-			backoff = time.Duration(float64(backoff) * float64(factor))
-			if backoff > maxBackoff {
-				backoff = maxBackoff
-			}
-		}
 	}
 }
 
 // SendMetrics converts to OTLP and sends via unary call.
+// With stream_fallback enabled, repeated OTLP failures switch delivery to the
+// legacy command stream; OTLP is probed periodically and preferred again as
+// soon as an export succeeds.
+//
+// With agent.spool.dir set, exports that fail because the server is
+// unreachable are spooled to disk instead of lost, and the spool is drained
+// oldest-first before live data once the server is back.
 func (s *MetricSender) SendMetrics(payload *model.MetricPayload) error {
 	if s.metricsClient == nil {
-		return status.Error(codes.Unavailable, "no active OTLP metrics client")
+		err := status.Error(codes.Unavailable, "no active OTLP metrics client")
+		if s.outbox != nil {
+			if otlpReq := s.buildRequest(payload); otlpReq != nil && s.spoolExport(otlpReq, err) {
+				return nil
+			}
+		}
+		return err
 	}
 
-	// Convert to OTLP format using our conversion function
-	otlpReq := otelconvert.ConvertToOTLPMetrics(payload)
+	if s.useStream.Load() && !s.shouldProbeOTLP() {
+		return s.sendViaStream(payload)
+	}
+
+	otlpReq := s.buildRequest(payload)
 	if otlpReq == nil {
 		utils.Warn("Failed to convert metrics to OTLP format")
 		return status.Error(codes.InvalidArgument, "failed to convert metrics to OTLP")
 	}
 
+	// Exports spooled during an outage go out before fresh data; while the
+	// backlog cannot be sent, fresh data joins it to keep the order
+	if err := s.drainSpool(); err != nil {
+		if s.spoolExport(otlpReq, err) {
+			return nil
+		}
+		return err
+	}
+
 	// Send via unary call (OTLP standard)
 	utils.Info("Sending %d metrics to server via OTLP", len(payload.Metrics))
 
+	agentutils.DebugPayload(s.cfg, "metrics", otlpReq)
+	selfmetrics.RecordExportSize("metrics", goproto.Size(otlpReq))
+
 	sendCtx, cancel := context.WithTimeout(s.ctx, 30*time.Second)
 	defer cancel()
 
-	_, err := s.metricsClient.Export(sendCtx, otlpReq)
+	_, err := s.metricsClient.Export(sendCtx, otlpReq, grpcconn.ExportCallOptions(s.cfg)...)
 	if err != nil {
+		s.exportFailures.Add(1)
 		utils.Warn("OTLP metrics export failed: %v", err)
+		if s.enterFallback() {
+			return s.sendViaStream(payload)
+		}
+		if s.spoolExport(otlpReq, err) {
+			return nil
+		}
 		return err
 	}
+	s.exportFailures.Store(0)
+	s.leaveFallback()
 
 	utils.Debug("Successfully exported %d metrics via OTLP", len(payload.Metrics))
 	return nil
 }
 
+// buildRequest converts payload to an OTLP export request with the
+// configured resource attribute mapping and scope version, or nil if the
+// conversion fails.
+func (s *MetricSender) buildRequest(payload *model.MetricPayload) *colmetricpb.ExportMetricsServiceRequest {
+	otlpReq := otelconvert.ConvertToOTLPMetrics(payload)
+	if otlpReq == nil {
+		return nil
+	}
+	config.RLock()
+	defer config.RUnlock()
+	if payload.Meta != nil {
+		otelconvert.MapMetricsResourceTags(otlpReq, payload.Meta.Tags, s.cfg.Agent.ResourceAttributeMapping)
+	}
+	otelconvert.SetMetricsScopeVersion(otlpReq, s.cfg.Agent.ScopeVersion)
+	return otlpReq
+}
+
+// exportWatchdog runs alongside manageReceive. The command stream can sit in
+// Recv() long after the underlying connection has failed, so if exports keep
+// failing the watchdog closes the shared connection and cancels the stream,
+// which unblocks manageReceive and lets manageConnection redial.
+func (s *MetricSender) exportWatchdog(done <-chan struct{}, cancelStream context.CancelFunc) {
+	ticker := time.NewTicker(watchdogInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			failures := s.exportFailures.Load()
+			if failures < exportFailureThreshold || s.useStream.Load() {
+				// Below threshold, or metrics are flowing over the stream fallback
+				continue
+			}
+			utils.Warn("Watchdog: %d consecutive metric exports failed; forcing reconnect", failures)
+			s.exportFailures.Store(0)
+			_ = grpcconn.CloseGRPCConn()
+			if cancelStream != nil {
+				cancelStream()
+			}
+			return
+		}
+	}
+}
+
 // manageReceive handles incoming commands; on a disconnect command, broadcasts global pause.
 func (s *MetricSender) manageReceive() {
 	for {
@@ -247,6 +339,12 @@ func (s *MetricSender) manageReceive() {
 	}
 }
 
+// Wait waits up to timeout for the stopped worker pool to finish its
+// in-flight exports. It returns false if they are still running.
+func (s *MetricSender) Wait(timeout time.Duration) bool {
+	return agentutils.WaitWorkers(&s.wg, timeout)
+}
+
 // Close waits for any in-flight work then closes the connection.
 func (s *MetricSender) Close() error {
 	utils.Info("Closing MetricSender... waiting for workers")
@@ -278,10 +376,27 @@ func (s *MetricSender) reconnectStream() error {
 	if err != nil {
 		return fmt.Errorf("failed to reopen stream: %w", err)
 	}
-	s.stream = stream
+	s.setStream(stream)
 	return nil
 }
 
+// setStream replaces the command stream under streamMu.
+func (s *MetricSender) setStream(stream proto.StreamService_StreamClient) {
+	s.streamMu.Lock()
+	defer s.streamMu.Unlock()
+	s.stream = stream
+}
+
+// closeStream half-closes and forgets the command stream under streamMu.
+func (s *MetricSender) closeStream() {
+	s.streamMu.Lock()
+	defer s.streamMu.Unlock()
+	if s.stream != nil {
+		_ = s.stream.CloseSend()
+	}
+	s.stream = nil
+}
+
 // sendCommandResponseWithRetry retries CommandResponse up to 3 times with backoff.
 func (s *MetricSender) sendCommandResponseWithRetry(resp *proto.CommandResponse) {
 	const maxAttempts = 3
@@ -291,6 +406,8 @@ func (s *MetricSender) sendCommandResponseWithRetry(resp *proto.CommandResponse)
 
 		done := make(chan error, 1)
 		go func() {
+			s.streamMu.Lock()
+			defer s.streamMu.Unlock()
 			done <- s.stream.Send(&proto.StreamPayload{
 				Payload: &proto.StreamPayload_CommandResponse{CommandResponse: resp},
 			})
@@ -0,0 +1,106 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+package metricsender
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	grpcconn "github.com/aaronlmathis/gosight-agent/internal/grpc"
+	"github.com/aaronlmathis/gosight-agent/internal/selfmetrics"
+	"github.com/aaronlmathis/gosight-agent/internal/spool"
+	"github.com/aaronlmathis/gosight-shared/utils"
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	goproto "google.golang.org/protobuf/proto"
+)
+
+// openSpool opens the metric spool under agent.spool.dir, if configured.
+// Without it, exports that fail while the server is unreachable are lost.
+func (s *MetricSender) openSpool() {
+	sc := s.cfg.Agent.Spool
+	if sc.Dir == "" {
+		return
+	}
+	ob, err := spool.OpenOutbox(filepath.Join(sc.Dir, "metrics"), sc.MaxBytes(), sc.MaxAge, sc.ReplayBatchSize(), s.replayExport)
+	if err != nil {
+		utils.Error("Metric spool disabled: %v", err)
+		return
+	}
+	s.outbox = ob
+	selfmetrics.TrackSpool("metrics", ob)
+	if n := ob.Pending(); n > 0 {
+		utils.Info("Metric spool holds %d exports from a previous outage; they are sent once connected", n)
+	}
+}
+
+// spoolExport saves req for later if the spool is enabled and err means the
+// server is unreachable. It reports whether req was spooled.
+func (s *MetricSender) spoolExport(req *colmetricpb.ExportMetricsServiceRequest, err error) bool {
+	if s.outbox == nil || !grpcconn.Unreachable(err) {
+		return false
+	}
+	data, merr := goproto.Marshal(req)
+	if merr == nil {
+		merr = s.outbox.Save(data)
+	}
+	if merr != nil {
+		utils.Warn("Failed to spool metrics export: %v", merr)
+		return false
+	}
+	utils.Debug("Server unreachable (%v); spooled metrics export", err)
+	return true
+}
+
+// drainSpool re-sends spooled exports oldest-first before live data.
+func (s *MetricSender) drainSpool() error {
+	if s.outbox == nil {
+		return nil
+	}
+	return s.outbox.Drain()
+}
+
+// replayExport re-sends one spooled export. Exports the server rejects are
+// dropped so they cannot block the rest of the spool.
+func (s *MetricSender) replayExport(data []byte) error {
+	client := s.metricsClient
+	if client == nil {
+		return status.Error(codes.Unavailable, "no active OTLP metrics client")
+	}
+	req := &colmetricpb.ExportMetricsServiceRequest{}
+	if err := goproto.Unmarshal(data, req); err != nil {
+		utils.Warn("Dropping undecodable spooled metrics export: %v", err)
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, 30*time.Second)
+	defer cancel()
+	if _, err := client.Export(ctx, req, grpcconn.ExportCallOptions(s.cfg)...); err != nil {
+		if grpcconn.Unreachable(err) {
+			return err
+		}
+		utils.Warn("Server rejected spooled metrics export, dropping it: %v", err)
+	}
+	return nil
+}
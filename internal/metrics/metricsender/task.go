@@ -29,6 +29,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/aaronlmathis/gosight-agent/internal/selfmetrics"
 	"github.com/aaronlmathis/gosight-shared/model"
 	"github.com/aaronlmathis/gosight-shared/utils"
 	"google.golang.org/grpc/codes"
@@ -54,8 +55,9 @@ func (s *MetricSender) StartWorkerPool(ctx context.Context, queue <-chan *model.
 				default:
 				}
 
-				// If not connected, wait and retry
-				if s.metricsClient == nil {
+				// If not connected, wait and retry (with a spool, keep
+				// draining the queue to disk instead)
+				if s.metricsClient == nil && s.outbox == nil {
 					time.Sleep(500 * time.Millisecond)
 					continue
 				}
@@ -72,6 +74,7 @@ func (s *MetricSender) StartWorkerPool(ctx context.Context, queue <-chan *model.
 				// 4) Send (errors will be logged)
 				if err := s.SendMetrics(payload); err != nil {
 					utils.Warn("Metric worker #%d failed to send payload: %v", id, err)
+					selfmetrics.RecordSendError("metrics")
 				}
 			}
 		}(i + 1)
@@ -0,0 +1,212 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// from_otlp.go converts OTLP metrics and spans pushed to the agent by local
+// applications back into GoSight metrics and trace spans, so they can be
+// enriched with the agent's metadata and forwarded with everything else.
+
+package otelconvert
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricpb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+
+	"github.com/aaronlmathis/gosight-shared/model"
+)
+
+// ReceivedNamespace is the namespace given to received metrics whose name has
+// no dot-separated namespace prefix (e.g. "queue_depth").
+const ReceivedNamespace = "Custom"
+
+// ConvertFromOTLPMetrics turns the gauge and sum data points of req into
+// GoSight metrics. A metric name "ns.sub.name" maps to Namespace, SubNamespace
+// and Name like fifo records do. Data point attributes become dimensions; the
+// resource's service.name is added as the "service" dimension unless a point
+// sets one. Monotonic cumulative sums become counters, everything else a gauge.
+// Points of other types (histograms, summaries) are skipped and counted in
+// unsupported.
+func ConvertFromOTLPMetrics(req *colmetricpb.ExportMetricsServiceRequest, now time.Time) (metrics []model.Metric, unsupported int) {
+	for _, rm := range req.GetResourceMetrics() {
+		service := ""
+		for _, kv := range rm.GetResource().GetAttributes() {
+			if kv.GetKey() == "service.name" {
+				service = anyValueString(kv.GetValue())
+			}
+		}
+
+		for _, sm := range rm.GetScopeMetrics() {
+			for _, m := range sm.GetMetrics() {
+				var points []*metricpb.NumberDataPoint
+				typ := "gauge"
+				switch data := m.GetData().(type) {
+				case *metricpb.Metric_Gauge:
+					points = data.Gauge.GetDataPoints()
+				case *metricpb.Metric_Sum:
+					points = data.Sum.GetDataPoints()
+					if data.Sum.GetIsMonotonic() && data.Sum.GetAggregationTemporality() == metricpb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE {
+						typ = "counter"
+					}
+				default:
+					unsupported++
+					continue
+				}
+
+				ns, sub, name := SplitMetricName(m.GetName())
+				for _, dp := range points {
+					out := model.Metric{
+						Namespace:    ns,
+						SubNamespace: sub,
+						Name:         name,
+						Type:         typ,
+						Unit:         m.GetUnit(),
+						Timestamp:    now,
+					}
+					if ts := dp.GetTimeUnixNano(); ts > 0 {
+						out.Timestamp = time.Unix(0, int64(ts))
+					}
+					switch v := dp.GetValue().(type) {
+					case *metricpb.NumberDataPoint_AsDouble:
+						out.Value = v.AsDouble
+					case *metricpb.NumberDataPoint_AsInt:
+						out.Value = float64(v.AsInt)
+					}
+
+					if len(dp.GetAttributes()) > 0 || service != "" {
+						out.Dimensions = make(map[string]string, len(dp.GetAttributes())+1)
+						for _, kv := range dp.GetAttributes() {
+							out.Dimensions[kv.GetKey()] = anyValueString(kv.GetValue())
+						}
+						if _, set := out.Dimensions["service"]; !set && service != "" {
+							out.Dimensions["service"] = service
+						}
+					}
+					metrics = append(metrics, out)
+				}
+			}
+		}
+	}
+	return metrics, unsupported
+}
+
+// SplitMetricName splits "ns.sub.name" into its parts. Names without a dot
+// get ReceivedNamespace; more than three parts keep the remainder in name.
+func SplitMetricName(full string) (ns, sub, name string) {
+	parts := strings.SplitN(full, ".", 3)
+	switch len(parts) {
+	case 1:
+		return ReceivedNamespace, "", parts[0]
+	case 2:
+		return parts[0], "", parts[1]
+	default:
+		return parts[0], parts[1], parts[2]
+	}
+}
+
+// anyValueString renders a scalar attribute value as a string.
+func anyValueString(v *commonpb.AnyValue) string {
+	switch x := v.GetValue().(type) {
+	case *commonpb.AnyValue_StringValue:
+		return x.StringValue
+	case *commonpb.AnyValue_BoolValue:
+		return fmt.Sprint(x.BoolValue)
+	case *commonpb.AnyValue_IntValue:
+		return fmt.Sprint(x.IntValue)
+	case *commonpb.AnyValue_DoubleValue:
+		return fmt.Sprint(x.DoubleValue)
+	default:
+		return ""
+	}
+}
+
+// ConvertFromOTLPTraces turns the spans of req into GoSight trace spans. The
+// resource's attributes become ResourceAttrs and its service.name the
+// ServiceName; span and event attributes are rendered as strings. Span kinds
+// and links have no GoSight equivalent and are not kept.
+func ConvertFromOTLPTraces(req *coltracepb.ExportTraceServiceRequest) []model.TraceSpan {
+	var spans []model.TraceSpan
+	for _, rs := range req.GetResourceSpans() {
+		resourceAttrs := attributeMap(rs.GetResource().GetAttributes())
+		service := resourceAttrs["service.name"]
+
+		for _, ss := range rs.GetScopeSpans() {
+			for _, sp := range ss.GetSpans() {
+				start := time.Unix(0, int64(sp.GetStartTimeUnixNano()))
+				end := time.Unix(0, int64(sp.GetEndTimeUnixNano()))
+				span := model.TraceSpan{
+					TraceID:       hex.EncodeToString(sp.GetTraceId()),
+					SpanID:        hex.EncodeToString(sp.GetSpanId()),
+					ParentSpanID:  hex.EncodeToString(sp.GetParentSpanId()),
+					Name:          sp.GetName(),
+					ServiceName:   service,
+					StartTime:     start,
+					EndTime:       end,
+					DurationMs:    float64(end.Sub(start)) / float64(time.Millisecond),
+					StatusCode:    spanStatusString(sp.GetStatus().GetCode()),
+					StatusMessage: sp.GetStatus().GetMessage(),
+					Attributes:    attributeMap(sp.GetAttributes()),
+					ResourceAttrs: resourceAttrs,
+				}
+				for _, ev := range sp.GetEvents() {
+					span.Events = append(span.Events, model.SpanEvent{
+						Name:       ev.GetName(),
+						Timestamp:  time.Unix(0, int64(ev.GetTimeUnixNano())),
+						Attributes: attributeMap(ev.GetAttributes()),
+					})
+				}
+				spans = append(spans, span)
+			}
+		}
+	}
+	return spans
+}
+
+// attributeMap renders attributes as strings, or returns nil if there are none.
+func attributeMap(attrs []*commonpb.KeyValue) map[string]string {
+	if len(attrs) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(attrs))
+	for _, kv := range attrs {
+		out[kv.GetKey()] = anyValueString(kv.GetValue())
+	}
+	return out
+}
+
+// spanStatusString maps an OTLP status code to a GoSight one ("OK", "ERROR",
+// or empty when unset).
+func spanStatusString(code tracepb.Status_StatusCode) string {
+	switch code {
+	case tracepb.Status_STATUS_CODE_OK:
+		return "OK"
+	case tracepb.Status_STATUS_CODE_ERROR:
+		return "ERROR"
+	default:
+		return ""
+	}
+}
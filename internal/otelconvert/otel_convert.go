@@ -25,6 +25,8 @@ along with GoSight. If not, see https://www.gnu.org/licenses/.
 package otelconvert
 
 import (
+	"strings"
+
 	collogpb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
 	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
 	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
@@ -32,6 +34,7 @@ import (
 	metricpb "go.opentelemetry.io/proto/otlp/metrics/v1"
 	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
 
+	agentmeta "github.com/aaronlmathis/gosight-agent/internal/meta"
 	"github.com/aaronlmathis/gosight-shared/model"
 )
 
@@ -43,6 +46,11 @@ func ConvertToOTLPMetrics(payload *model.MetricPayload) *colmetricpb.ExportMetri
 
 	resource := convertMetaToResource(payload.Meta)
 
+	endpointID := payload.EndpointID
+	if payload.Meta != nil && payload.Meta.EndpointID != "" {
+		endpointID = payload.Meta.EndpointID
+	}
+
 	// Group metrics by namespace/subnamespace for proper scoping
 	scopeMap := make(map[string][]*metricpb.Metric)
 
@@ -77,6 +85,30 @@ func ConvertToOTLPMetrics(payload *model.MetricPayload) *colmetricpb.ExportMetri
 					},
 				},
 			}
+		} else if isCounter(m.Type) {
+			// Counters are cumulative monotonic sums so backends can compute
+			// rates and detect resets
+			start := counterStartTime(seriesKey(endpointID, scopeName, m), m.Value, m.Timestamp)
+			metric = &metricpb.Metric{
+				Name: m.Name,
+				Unit: m.Unit,
+				Data: &metricpb.Metric_Sum{
+					Sum: &metricpb.Sum{
+						AggregationTemporality: metricpb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+						IsMonotonic:            true,
+						DataPoints: []*metricpb.NumberDataPoint{
+							{
+								StartTimeUnixNano: uint64(start.UnixNano()),
+								TimeUnixNano:      uint64(m.Timestamp.UnixNano()),
+								Attributes:        convertDimensions(m.Dimensions),
+								Value: &metricpb.NumberDataPoint_AsDouble{
+									AsDouble: m.Value,
+								},
+							},
+						},
+					},
+				},
+			}
 		} else {
 			// Convert to gauge for simple metrics
 			metric = &metricpb.Metric{
@@ -106,7 +138,8 @@ func ConvertToOTLPMetrics(payload *model.MetricPayload) *colmetricpb.ExportMetri
 	for scopeName, metrics := range scopeMap {
 		scopeMetrics = append(scopeMetrics, &metricpb.ScopeMetrics{
 			Scope: &commonpb.InstrumentationScope{
-				Name: scopeName,
+				Name:    scopeName,
+				Version: scopeVersion(payload.Meta),
 			},
 			Metrics: metrics,
 		})
@@ -122,6 +155,20 @@ func ConvertToOTLPMetrics(payload *model.MetricPayload) *colmetricpb.ExportMetri
 	}
 }
 
+// scopeVersion is the InstrumentationScope version of converted signals: the
+// version of the agent that produced them.
+func scopeVersion(meta *model.Meta) string {
+	if meta == nil {
+		return ""
+	}
+	return meta.AgentVersion
+}
+
+// isCounter reports whether a GoSight metric type denotes a monotonic counter.
+func isCounter(typ string) bool {
+	return strings.EqualFold(typ, "counter")
+}
+
 // ConvertToOTLPLogs builds an OTLP ExportLogsServiceRequest from a GoSight LogPayload.
 // This function ensures that host_id and agent_id are preserved in the resource attributes
 // to maintain proper identification and correlation of log data in OTLP-compatible systems.
@@ -162,7 +209,8 @@ func ConvertToOTLPLogs(payload *model.LogPayload) *collogpb.ExportLogsServiceReq
 	for scopeName, logRecords := range scopeMap {
 		scopeLogs = append(scopeLogs, &logpb.ScopeLogs{
 			Scope: &commonpb.InstrumentationScope{
-				Name: scopeName,
+				Name:    scopeName,
+				Version: scopeVersion(payload.Meta),
 			},
 			LogRecords: logRecords,
 		})
@@ -217,6 +265,11 @@ func convertMetaToResource(meta *model.Meta) *resourcepb.Resource {
 	add("resource.kind", meta.Kind)
 	add("agent.version", meta.AgentVersion)
 	add("endpoint.id", meta.EndpointID)
+	add("service.instance.id", meta.Tags[agentmeta.InstanceTag])
+
+	// Collection heartbeat (metric payloads only)
+	add("agent.sequence", meta.Tags[agentmeta.SequenceTag])
+	add("agent.collected_at", meta.Tags[agentmeta.CollectedAtTag])
 
 	// OS / Platform
 	add("os.type", meta.OS)
@@ -232,6 +285,7 @@ func convertMetaToResource(meta *model.Meta) *resourcepb.Resource {
 	add("cloud.provider", meta.CloudProvider)
 	add("cloud.region", meta.Region)
 	add("cloud.zone", meta.AvailabilityZone)
+	add("host.rack", meta.Tags[agentmeta.RackTag])
 	add("cloud.account.id", meta.AccountID)
 	add("cloud.project.id", meta.ProjectID)
 	add("cloud.instance.id", meta.InstanceID)
@@ -258,6 +312,7 @@ func convertMetaToResource(meta *model.Meta) *resourcepb.Resource {
 	add("service.version", meta.Version)
 	add("environment", meta.Environment)
 	add("deployment.id", meta.DeploymentID)
+	add("maintenance", meta.Tags[agentmeta.MaintenanceTag])
 
 	// Network
 	add("host.ip", meta.IPAddress)
@@ -25,7 +25,14 @@ import (
 	"testing"
 	"time"
 
+	agentutils "github.com/aaronlmathis/gosight-agent/internal/utils"
 	"github.com/aaronlmathis/gosight-shared/model"
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricpb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
 )
 
 func TestConvertToOTLPLogs(t *testing.T) {
@@ -156,12 +163,13 @@ func TestConvertToOTLPMetrics(t *testing.T) {
 			},
 		},
 		Meta: &model.Meta{
-			AgentID:    "test-agent-123",
-			HostID:     "test-host-456",
-			Hostname:   "test-hostname",
-			EndpointID: "test-endpoint-789",
-			Kind:       "host",
-			OS:         "linux",
+			AgentID:      "test-agent-123",
+			HostID:       "test-host-456",
+			Hostname:     "test-hostname",
+			EndpointID:   "test-endpoint-789",
+			Kind:         "host",
+			OS:           "linux",
+			AgentVersion: "1.2.3",
 		},
 	}
 
@@ -191,6 +199,13 @@ func TestConvertToOTLPMetrics(t *testing.T) {
 	if scopeMetric.Scope.Name != "system.cpu" {
 		t.Errorf("Expected scope name 'system.cpu', got '%s'", scopeMetric.Scope.Name)
 	}
+	if scopeMetric.Scope.Version != "1.2.3" {
+		t.Errorf("Expected scope version to be the agent version '1.2.3', got '%s'", scopeMetric.Scope.Version)
+	}
+	SetMetricsScopeVersion(otlpRequest, "custom")
+	if scopeMetric.Scope.Version != "custom" {
+		t.Errorf("Expected scope version override 'custom', got '%s'", scopeMetric.Scope.Version)
+	}
 
 	if len(scopeMetric.Metrics) != 1 {
 		t.Fatalf("Expected 1 Metric, got %d", len(scopeMetric.Metrics))
@@ -206,6 +221,183 @@ func TestConvertToOTLPMetrics(t *testing.T) {
 	}
 }
 
+func TestConvertToOTLPMetricsCounterAsSum(t *testing.T) {
+	testTime := time.Now()
+	metricPayload := &model.MetricPayload{
+		Timestamp: testTime,
+		Metrics: []model.Metric{
+			{
+				Namespace:    "system",
+				SubNamespace: "cpu",
+				Name:         "time_user",
+				Timestamp:    testTime,
+				Value:        1234.5,
+				Type:         "counter",
+				Unit:         "seconds",
+			},
+			{
+				Namespace:    "system",
+				SubNamespace: "cpu",
+				Name:         "usage_percent",
+				Timestamp:    testTime,
+				Value:        12.5,
+				Type:         "gauge",
+				Unit:         "percent",
+			},
+		},
+		Meta: &model.Meta{HostID: "test-host-456"},
+	}
+
+	otlpRequest := ConvertToOTLPMetrics(metricPayload)
+	if otlpRequest == nil {
+		t.Fatal("ConvertToOTLPMetrics returned nil")
+	}
+
+	metrics := otlpRequest.ResourceMetrics[0].ScopeMetrics[0].Metrics
+	if len(metrics) != 2 {
+		t.Fatalf("Expected 2 Metrics, got %d", len(metrics))
+	}
+
+	sum := metrics[0].GetSum()
+	if sum == nil {
+		t.Fatal("Expected counter to be converted to a Sum")
+	}
+	if !sum.IsMonotonic {
+		t.Error("Expected counter Sum to be monotonic")
+	}
+	if sum.AggregationTemporality != metricpb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE {
+		t.Errorf("Expected cumulative temporality, got %v", sum.AggregationTemporality)
+	}
+	if got := sum.DataPoints[0].GetAsDouble(); got != 1234.5 {
+		t.Errorf("Expected value 1234.5, got %v", got)
+	}
+	if got := sum.DataPoints[0].StartTimeUnixNano; got != uint64(testTime.UnixNano()) {
+		t.Errorf("Expected start time of first point to be its timestamp, got %v", got)
+	}
+
+	// A later point keeps the start time; a lower value (reset) moves it
+	later := testTime.Add(10 * time.Second)
+	metricPayload.Metrics[0].Timestamp = later
+	metricPayload.Metrics[0].Value = 1300
+	next := ConvertToOTLPMetrics(metricPayload).ResourceMetrics[0].ScopeMetrics[0].Metrics[0].GetSum()
+	if got := next.DataPoints[0].StartTimeUnixNano; got != uint64(testTime.UnixNano()) {
+		t.Errorf("Expected start time to be kept, got %v", got)
+	}
+
+	metricPayload.Metrics[0].Value = 5
+	reset := ConvertToOTLPMetrics(metricPayload).ResourceMetrics[0].ScopeMetrics[0].Metrics[0].GetSum()
+	if got := reset.DataPoints[0].StartTimeUnixNano; got != uint64(later.UnixNano()) {
+		t.Errorf("Expected start time to move on reset, got %v", got)
+	}
+
+	if metrics[1].GetGauge() == nil {
+		t.Error("Expected gauge to be converted to a Gauge")
+	}
+}
+
+func TestConvertToOTLPMetricsStatisticValuesAsHistogram(t *testing.T) {
+	testTime := time.Now()
+
+	var w agentutils.Window
+	for _, v := range []float64{12, 4, 20} {
+		w.Observe(v)
+	}
+	stats := w.Take()
+	if w.Take() != nil {
+		t.Error("Expected Take to start a new, empty window")
+	}
+
+	m := agentutils.SummaryMetric("agent", "", "cycle_duration_window_ms", stats, "ms", map[string]string{"runner": "metrics"}, testTime)
+	if m.Value != 12 {
+		t.Errorf("Expected summary value to be the mean 12, got %v", m.Value)
+	}
+
+	otlpRequest := ConvertToOTLPMetrics(&model.MetricPayload{
+		Timestamp: testTime,
+		Metrics:   []model.Metric{m},
+		Meta:      &model.Meta{HostID: "test-host-456"},
+	})
+	if otlpRequest == nil {
+		t.Fatal("ConvertToOTLPMetrics returned nil")
+	}
+
+	hist := otlpRequest.ResourceMetrics[0].ScopeMetrics[0].Metrics[0].GetHistogram()
+	if hist == nil {
+		t.Fatal("Expected StatisticValues to be converted to a Histogram")
+	}
+	dp := hist.DataPoints[0]
+	if dp.Count != 3 || dp.GetSum() != 36 || dp.GetMin() != 4 || dp.GetMax() != 20 {
+		t.Errorf("Unexpected histogram point: count=%d sum=%v min=%v max=%v", dp.Count, dp.GetSum(), dp.GetMin(), dp.GetMax())
+	}
+	if dp.TimeUnixNano != uint64(testTime.UnixNano()) {
+		t.Errorf("Expected point timestamp %d, got %d", testTime.UnixNano(), dp.TimeUnixNano)
+	}
+}
+
+func TestConvertToOTLPTraces(t *testing.T) {
+	start := time.Now()
+
+	payload := &model.TracePayload{
+		Meta: &model.Meta{HostID: "test-host-456", Service: "agent-default", AgentVersion: "1.2.3"},
+		Traces: []model.TraceSpan{
+			{
+				TraceID:      "0102030405060708090a0b0c0d0e0f10",
+				SpanID:       "0102030405060708",
+				ParentSpanID: "1112131415161718",
+				Name:         "GET /",
+				ServiceName:  "web",
+				StartTime:    start,
+				DurationMs:   250,
+				StatusCode:   "ERROR",
+				Attributes:   map[string]string{"http.method": "GET"},
+				Events:       []model.SpanEvent{{Name: "retry", Timestamp: start}},
+			},
+			{TraceID: "not-hex", SpanID: "0102030405060708", Name: "bad", ServiceName: "web"},
+		},
+	}
+
+	req := ConvertToOTLPTraces(payload)
+	if req == nil {
+		t.Fatal("ConvertToOTLPTraces returned nil")
+	}
+	if len(req.ResourceSpans) != 1 {
+		t.Fatalf("Expected 1 ResourceSpans, got %d", len(req.ResourceSpans))
+	}
+
+	rs := req.ResourceSpans[0]
+	service := ""
+	for _, kv := range rs.Resource.Attributes {
+		if kv.Key == "service.name" {
+			service = kv.Value.GetStringValue()
+		}
+	}
+	if service != "web" {
+		t.Errorf("Expected service.name web, got %q", service)
+	}
+
+	ss := rs.ScopeSpans[0]
+	if ss.Scope.Version != "1.2.3" {
+		t.Errorf("Expected scope version 1.2.3, got %q", ss.Scope.Version)
+	}
+	if len(ss.Spans) != 1 {
+		t.Fatalf("Expected the span with an invalid trace ID to be skipped, got %d spans", len(ss.Spans))
+	}
+
+	span := ss.Spans[0]
+	if len(span.TraceId) != 16 || len(span.SpanId) != 8 || len(span.ParentSpanId) != 8 {
+		t.Errorf("Unexpected ID lengths: trace=%d span=%d parent=%d", len(span.TraceId), len(span.SpanId), len(span.ParentSpanId))
+	}
+	if want := uint64(start.Add(250 * time.Millisecond).UnixNano()); span.EndTimeUnixNano != want {
+		t.Errorf("Expected end time derived from duration %d, got %d", want, span.EndTimeUnixNano)
+	}
+	if span.Status.Code != tracepb.Status_STATUS_CODE_ERROR {
+		t.Errorf("Expected ERROR status, got %v", span.Status.Code)
+	}
+	if len(span.Events) != 1 || len(span.Attributes) != 1 {
+		t.Errorf("Expected 1 event and 1 attribute, got %d and %d", len(span.Events), len(span.Attributes))
+	}
+}
+
 func TestConvertLogLevelToSeverity(t *testing.T) {
 	tests := map[string]int32{
 		"trace":   1,
@@ -230,3 +422,128 @@ func TestConvertLogLevelToSeverity(t *testing.T) {
 		}
 	}
 }
+
+func TestMapTagAttributes(t *testing.T) {
+	res := convertMetaToResource(&model.Meta{
+		Hostname: "test-hostname",
+		Region:   "us-east-1",
+		Tags:     map[string]string{"team": "storage", "dc": "dc-west"},
+	})
+
+	res = MapTagAttributes(res, map[string]string{"team": "storage", "dc": "dc-west"}, map[string]string{
+		"team":    "service.team",
+		"dc":      "cloud.region",
+		"missing": "service.owner",
+	})
+
+	attrs := make(map[string]string)
+	regionCount := 0
+	for _, attr := range res.Attributes {
+		attrs[attr.Key] = attr.Value.GetStringValue()
+		if attr.Key == "cloud.region" {
+			regionCount++
+		}
+	}
+
+	if attrs["service.team"] != "storage" {
+		t.Errorf("Expected service.team 'storage', got %q", attrs["service.team"])
+	}
+	if attrs["cloud.region"] != "dc-west" || regionCount != 1 {
+		t.Errorf("Expected a single cloud.region 'dc-west', got %q (%d attributes)", attrs["cloud.region"], regionCount)
+	}
+	if _, ok := attrs["service.owner"]; ok {
+		t.Error("Expected unset tag to be skipped")
+	}
+}
+
+func TestConvertFromOTLPMetrics(t *testing.T) {
+	testTime := time.Unix(1700000000, 0)
+	point := func(v float64, attrs ...*commonpb.KeyValue) []*metricpb.NumberDataPoint {
+		return []*metricpb.NumberDataPoint{{
+			TimeUnixNano: uint64(testTime.UnixNano()),
+			Attributes:   attrs,
+			Value:        &metricpb.NumberDataPoint_AsDouble{AsDouble: v},
+		}}
+	}
+	str := func(k, v string) *commonpb.KeyValue {
+		return &commonpb.KeyValue{Key: k, Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: v}}}
+	}
+
+	req := &colmetricpb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*metricpb.ResourceMetrics{{
+			Resource: &resourcepb.Resource{Attributes: []*commonpb.KeyValue{str("service.name", "shop")}},
+			ScopeMetrics: []*metricpb.ScopeMetrics{{
+				Metrics: []*metricpb.Metric{
+					{Name: "app.queue.depth", Unit: "count", Data: &metricpb.Metric_Gauge{Gauge: &metricpb.Gauge{DataPoints: point(7, str("queue", "orders"))}}},
+					{Name: "requests_total", Data: &metricpb.Metric_Sum{Sum: &metricpb.Sum{
+						IsMonotonic:            true,
+						AggregationTemporality: metricpb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+						DataPoints:             point(42),
+					}}},
+					{Name: "app.latency", Data: &metricpb.Metric_Histogram{Histogram: &metricpb.Histogram{}}},
+				},
+			}},
+		}},
+	}
+
+	metrics, unsupported := ConvertFromOTLPMetrics(req, time.Now())
+	if unsupported != 1 || len(metrics) != 2 {
+		t.Fatalf("got %d metrics, %d unsupported; want 2, 1", len(metrics), unsupported)
+	}
+
+	depth := metrics[0]
+	if depth.Namespace != "app" || depth.SubNamespace != "queue" || depth.Name != "depth" || depth.Value != 7 ||
+		depth.Type != "gauge" || depth.Dimensions["queue"] != "orders" || depth.Dimensions["service"] != "shop" ||
+		!depth.Timestamp.Equal(testTime) {
+		t.Errorf("unexpected gauge: %+v", depth)
+	}
+	if got := metrics[1]; got.Namespace != ReceivedNamespace || got.Name != "requests_total" || got.Type != "counter" || got.Value != 42 {
+		t.Errorf("unexpected counter: %+v", got)
+	}
+}
+
+func TestConvertFromOTLPTraces(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	req := &coltracepb.ExportTraceServiceRequest{
+		ResourceSpans: []*tracepb.ResourceSpans{{
+			Resource: &resourcepb.Resource{Attributes: []*commonpb.KeyValue{
+				{Key: "service.name", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: "web"}}},
+			}},
+			ScopeSpans: []*tracepb.ScopeSpans{{Spans: []*tracepb.Span{{
+				TraceId:           []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+				SpanId:            []byte{1, 2, 3, 4, 5, 6, 7, 8},
+				Name:              "GET /",
+				StartTimeUnixNano: uint64(start.UnixNano()),
+				EndTimeUnixNano:   uint64(start.Add(250 * time.Millisecond).UnixNano()),
+				Attributes: []*commonpb.KeyValue{
+					{Key: "http.status_code", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: 500}}},
+				},
+				Status: &tracepb.Status{Code: tracepb.Status_STATUS_CODE_ERROR, Message: "boom"},
+				Events: []*tracepb.Span_Event{{Name: "retry", TimeUnixNano: uint64(start.UnixNano())}},
+			}}}},
+		}},
+	}
+
+	spans := ConvertFromOTLPTraces(req)
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 span, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.TraceID != "0102030405060708090a0b0c0d0e0f10" || span.SpanID != "0102030405060708" || span.ParentSpanID != "" {
+		t.Errorf("Unexpected IDs: trace=%q span=%q parent=%q", span.TraceID, span.SpanID, span.ParentSpanID)
+	}
+	if span.ServiceName != "web" || span.DurationMs != 250 || span.StatusCode != "ERROR" || span.StatusMessage != "boom" {
+		t.Errorf("Unexpected span: %+v", span)
+	}
+	if span.Attributes["http.status_code"] != "500" || len(span.Events) != 1 {
+		t.Errorf("Expected the attribute and event to be kept, got %+v", span)
+	}
+
+	// Converting back yields the same IDs and timing
+	back := ConvertToOTLPTraces(&model.TracePayload{Meta: &model.Meta{}, Traces: spans})
+	out := back.ResourceSpans[0].ScopeSpans[0].Spans[0]
+	in := req.ResourceSpans[0].ScopeSpans[0].Spans[0]
+	if string(out.TraceId) != string(in.TraceId) || out.EndTimeUnixNano != in.EndTimeUnixNano {
+		t.Errorf("Round trip changed the span: %+v", out)
+	}
+}
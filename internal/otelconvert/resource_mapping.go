@@ -0,0 +1,96 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+package otelconvert
+
+import (
+	collogpb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+)
+
+// MapMetricsResourceTags applies MapTagAttributes to every resource in req.
+func MapMetricsResourceTags(req *colmetricpb.ExportMetricsServiceRequest, tags, mapping map[string]string) {
+	if req == nil || len(mapping) == 0 {
+		return
+	}
+	for _, rm := range req.ResourceMetrics {
+		rm.Resource = MapTagAttributes(rm.Resource, tags, mapping)
+	}
+}
+
+// MapLogsResourceTags applies MapTagAttributes to every resource in req.
+func MapLogsResourceTags(req *collogpb.ExportLogsServiceRequest, tags, mapping map[string]string) {
+	if req == nil || len(mapping) == 0 {
+		return
+	}
+	for _, rl := range req.ResourceLogs {
+		rl.Resource = MapTagAttributes(rl.Resource, tags, mapping)
+	}
+}
+
+// MapTracesResourceTags applies MapTagAttributes to every resource in req.
+func MapTracesResourceTags(req *coltracepb.ExportTraceServiceRequest, tags, mapping map[string]string) {
+	if req == nil || len(mapping) == 0 {
+		return
+	}
+	for _, rs := range req.ResourceSpans {
+		rs.Resource = MapTagAttributes(rs.Resource, tags, mapping)
+	}
+}
+
+// MapTagAttributes copies selected meta tags onto resource attributes.
+// mapping is tag key -> attribute name (e.g. "team" -> "service.team",
+// "dc" -> "cloud.region"); a mapped attribute replaces any attribute of the
+// same name produced by the default conversion. Tags that are unset or empty
+// are skipped.
+func MapTagAttributes(res *resourcepb.Resource, tags, mapping map[string]string) *resourcepb.Resource {
+	if res == nil {
+		res = &resourcepb.Resource{}
+	}
+
+	for tag, attr := range mapping {
+		val := tags[tag]
+		if val == "" || attr == "" {
+			continue
+		}
+
+		kv := &commonpb.KeyValue{
+			Key:   attr,
+			Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: val}},
+		}
+
+		replaced := false
+		for i, existing := range res.Attributes {
+			if existing.Key == attr {
+				res.Attributes[i] = kv
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			res.Attributes = append(res.Attributes, kv)
+		}
+	}
+	return res
+}
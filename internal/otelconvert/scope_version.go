@@ -0,0 +1,73 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+package otelconvert
+
+import (
+	collogpb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+)
+
+// SetMetricsScopeVersion overrides the InstrumentationScope version (the agent
+// version by default) of every scope in req. An empty version is a no-op.
+func SetMetricsScopeVersion(req *colmetricpb.ExportMetricsServiceRequest, version string) {
+	if req == nil || version == "" {
+		return
+	}
+	for _, rm := range req.ResourceMetrics {
+		for _, sm := range rm.ScopeMetrics {
+			if sm.Scope != nil {
+				sm.Scope.Version = version
+			}
+		}
+	}
+}
+
+// SetLogsScopeVersion overrides the InstrumentationScope version (the agent
+// version by default) of every scope in req. An empty version is a no-op.
+func SetLogsScopeVersion(req *collogpb.ExportLogsServiceRequest, version string) {
+	if req == nil || version == "" {
+		return
+	}
+	for _, rl := range req.ResourceLogs {
+		for _, sl := range rl.ScopeLogs {
+			if sl.Scope != nil {
+				sl.Scope.Version = version
+			}
+		}
+	}
+}
+
+// SetTracesScopeVersion overrides the InstrumentationScope version (the agent
+// version by default) of every scope in req. An empty version is a no-op.
+func SetTracesScopeVersion(req *coltracepb.ExportTraceServiceRequest, version string) {
+	if req == nil || version == "" {
+		return
+	}
+	for _, rs := range req.ResourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			if ss.Scope != nil {
+				ss.Scope.Version = version
+			}
+		}
+	}
+}
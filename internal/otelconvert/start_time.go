@@ -0,0 +1,84 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+package otelconvert
+
+import (
+	"sync"
+	"time"
+
+	agentutils "github.com/aaronlmathis/gosight-agent/internal/utils"
+	"github.com/aaronlmathis/gosight-shared/model"
+)
+
+// counterStaleAfter is how long a counter series may go unseen before its
+// start time is forgotten (e.g. a removed container).
+const counterStaleAfter = time.Hour
+
+// counterStart tracks the start of the cumulative window of one series.
+type counterStart struct {
+	start    time.Time
+	last     float64
+	lastSeen time.Time
+}
+
+var (
+	counterMu     sync.Mutex
+	counterStarts = make(map[string]*counterStart)
+	lastSweep     time.Time
+)
+
+// counterStartTime returns the StartTimeUnixNano to report for a cumulative
+// counter point. The start is the time the series was first seen by this
+// agent process, and is moved to the point's own timestamp whenever the value
+// goes down (a counter reset), so backends compute correct rates from the
+// first point and across resets.
+func counterStartTime(key string, value float64, ts time.Time) time.Time {
+	counterMu.Lock()
+	defer counterMu.Unlock()
+
+	now := time.Now()
+	if now.Sub(lastSweep) > counterStaleAfter {
+		for k, cs := range counterStarts {
+			if now.Sub(cs.lastSeen) > counterStaleAfter {
+				delete(counterStarts, k)
+			}
+		}
+		lastSweep = now
+	}
+
+	cs, ok := counterStarts[key]
+	if !ok {
+		cs = &counterStart{start: ts}
+		counterStarts[key] = cs
+	} else if value < cs.last {
+		cs.start = ts
+	}
+	cs.last = value
+	cs.lastSeen = now
+	return cs.start
+}
+
+// seriesKey identifies a metric series across payloads: the endpoint it
+// belongs to, its scope, and the series key shared with the rest of the agent.
+func seriesKey(endpointID, scope string, m model.Metric) string {
+	return endpointID + "|" + scope + "|" + agentutils.SeriesKey(m)
+}
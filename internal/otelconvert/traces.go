@@ -0,0 +1,198 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+package otelconvert
+
+import (
+	"encoding/hex"
+	"sort"
+	"time"
+
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+
+	"github.com/aaronlmathis/gosight-shared/model"
+)
+
+// traceScopeName is the InstrumentationScope of spans forwarded by the agent.
+const traceScopeName = "gosight-agent"
+
+// ConvertToOTLPTraces builds an OTLP ExportTraceServiceRequest from a GoSight
+// TracePayload. Spans are grouped into one ResourceSpans per service name;
+// the resource is the payload meta with service.name set to the span's
+// ServiceName and the ResourceAttrs of the group's first span added. Spans
+// whose trace or span ID is not valid hex of the right length are skipped.
+func ConvertToOTLPTraces(payload *model.TracePayload) *coltracepb.ExportTraceServiceRequest {
+	if payload == nil || len(payload.Traces) == 0 {
+		return nil
+	}
+
+	var order []string
+	groups := make(map[string][]model.TraceSpan)
+	for _, span := range payload.Traces {
+		if _, ok := groups[span.ServiceName]; !ok {
+			order = append(order, span.ServiceName)
+		}
+		groups[span.ServiceName] = append(groups[span.ServiceName], span)
+	}
+
+	req := &coltracepb.ExportTraceServiceRequest{}
+	for _, service := range order {
+		spans := groups[service]
+
+		var otlpSpans []*tracepb.Span
+		for _, span := range spans {
+			if s := convertSpan(span); s != nil {
+				otlpSpans = append(otlpSpans, s)
+			}
+		}
+		if len(otlpSpans) == 0 {
+			continue
+		}
+
+		req.ResourceSpans = append(req.ResourceSpans, &tracepb.ResourceSpans{
+			Resource: traceResource(payload.Meta, service, spans[0].ResourceAttrs),
+			ScopeSpans: []*tracepb.ScopeSpans{
+				{
+					Scope: &commonpb.InstrumentationScope{
+						Name:    traceScopeName,
+						Version: scopeVersion(payload.Meta),
+					},
+					Spans: otlpSpans,
+				},
+			},
+		})
+	}
+
+	if len(req.ResourceSpans) == 0 {
+		return nil
+	}
+	return req
+}
+
+// traceResource returns the meta resource with service.name and the span's
+// resource attributes overriding attributes of the same name.
+func traceResource(meta *model.Meta, service string, resourceAttrs map[string]string) *resourcepb.Resource {
+	res := convertMetaToResource(meta)
+
+	overrides := make(map[string]string, len(resourceAttrs)+1)
+	for k, v := range resourceAttrs {
+		overrides[k] = v
+	}
+	if service != "" {
+		overrides["service.name"] = service
+	}
+	if len(overrides) == 0 {
+		return res
+	}
+
+	attrs := res.Attributes[:0]
+	for _, kv := range res.Attributes {
+		if _, ok := overrides[kv.Key]; !ok {
+			attrs = append(attrs, kv)
+		}
+	}
+	keys := make([]string, 0, len(overrides))
+	for k := range overrides {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if overrides[k] == "" {
+			continue
+		}
+		attrs = append(attrs, &commonpb.KeyValue{
+			Key:   k,
+			Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: overrides[k]}},
+		})
+	}
+	res.Attributes = attrs
+	return res
+}
+
+// convertSpan converts one span, returning nil if its IDs are invalid.
+func convertSpan(span model.TraceSpan) *tracepb.Span {
+	traceID, ok := decodeID(span.TraceID, 16)
+	if !ok {
+		return nil
+	}
+	spanID, ok := decodeID(span.SpanID, 8)
+	if !ok {
+		return nil
+	}
+	var parentID []byte
+	if span.ParentSpanID != "" {
+		if parentID, ok = decodeID(span.ParentSpanID, 8); !ok {
+			parentID = nil
+		}
+	}
+
+	end := span.EndTime
+	if end.IsZero() && span.DurationMs > 0 {
+		end = span.StartTime.Add(time.Duration(span.DurationMs * float64(time.Millisecond)))
+	}
+
+	out := &tracepb.Span{
+		TraceId:           traceID,
+		SpanId:            spanID,
+		ParentSpanId:      parentID,
+		Name:              span.Name,
+		Kind:              tracepb.Span_SPAN_KIND_INTERNAL,
+		StartTimeUnixNano: uint64(span.StartTime.UnixNano()),
+		EndTimeUnixNano:   uint64(end.UnixNano()),
+		Attributes:        convertDimensions(span.Attributes),
+		Status: &tracepb.Status{
+			Code:    convertSpanStatus(span.StatusCode),
+			Message: span.StatusMessage,
+		},
+	}
+	for _, ev := range span.Events {
+		out.Events = append(out.Events, &tracepb.Span_Event{
+			TimeUnixNano: uint64(ev.Timestamp.UnixNano()),
+			Name:         ev.Name,
+			Attributes:   convertDimensions(ev.Attributes),
+		})
+	}
+	return out
+}
+
+// decodeID decodes a hex trace or span ID of exactly size bytes.
+func decodeID(id string, size int) ([]byte, bool) {
+	b, err := hex.DecodeString(id)
+	if err != nil || len(b) != size {
+		return nil, false
+	}
+	return b, true
+}
+
+// convertSpanStatus maps a GoSight status code ("OK", "ERROR") to OTLP.
+func convertSpanStatus(code string) tracepb.Status_StatusCode {
+	switch code {
+	case "OK", "ok", "Ok":
+		return tracepb.Status_STATUS_CODE_OK
+	case "ERROR", "error", "Error":
+		return tracepb.Status_STATUS_CODE_ERROR
+	default:
+		return tracepb.Status_STATUS_CODE_UNSET
+	}
+}
@@ -0,0 +1,63 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+// agent/processes/processcollector/age.go
+
+package processcollector
+
+import (
+	"context"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// excludeYoung reports whether a process should be left out of the snapshot
+// because it started less than minAge before now (e.g. the short-lived
+// "sh -c" wrappers of cron jobs) and its name matches none of the include
+// glob patterns. Processes with an unknown start time are always kept.
+func excludeYoung(ctx context.Context, p *process.Process, start, now time.Time, minAge time.Duration, include []string) bool {
+	if minAge <= 0 || start.IsZero() || now.Sub(start) >= minAge {
+		return false
+	}
+	if len(include) == 0 {
+		return true
+	}
+
+	name, err := p.NameWithContext(ctx)
+	if err != nil {
+		return true
+	}
+	exe, _ := p.ExeWithContext(ctx)
+
+	for _, pattern := range include {
+		if ok, _ := path.Match(pattern, name); ok {
+			return false
+		}
+		if exe != "" {
+			if ok, _ := path.Match(pattern, filepath.Base(exe)); ok {
+				return false
+			}
+		}
+	}
+	return true
+}
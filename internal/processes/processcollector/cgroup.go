@@ -0,0 +1,87 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+// agent/processes/processcollector/cgroup.go
+
+package processcollector
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/aaronlmathis/gosight-shared/model"
+)
+
+// containerIDPattern matches the 64 hex digit container ID that docker,
+// podman, containerd and CRI-O embed in a container's cgroup path, e.g.
+// /system.slice/docker-<id>.scope or /kubepods/burstable/pod<uid>/<id>.
+var containerIDPattern = regexp.MustCompile(`[0-9a-f]{64}`)
+
+// applyCgroup sets the "cgroup" label of info to path and, if the path
+// belongs to a container, the "container_id" label, so processes can be
+// joined to container metrics. An existing container_id is kept.
+func applyCgroup(info *model.ProcessInfo, path string) {
+	if path == "" {
+		return
+	}
+	if info.Labels == nil {
+		info.Labels = make(map[string]string)
+	}
+	info.Labels["cgroup"] = path
+	if id := containerIDFromCgroup(path); id != "" && info.Labels["container_id"] == "" {
+		info.Labels["container_id"] = id
+	}
+}
+
+// parseCgroupFile returns the cgroup path of a process from the contents of
+// /proc/<pid>/cgroup ("hierarchy-ID:controllers:path" per line). The unified
+// cgroup v2 hierarchy is preferred; on cgroup v1 the systemd, then cpu, then
+// memory hierarchy is used, which is where container runtimes place processes.
+func parseCgroupFile(data string) string {
+	var byController = map[string]string{}
+	for _, line := range strings.Split(data, "\n") {
+		parts := strings.SplitN(strings.TrimSpace(line), ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		if parts[0] == "0" && parts[1] == "" {
+			return parts[2]
+		}
+		for _, c := range strings.Split(parts[1], ",") {
+			byController[c] = parts[2]
+		}
+	}
+	for _, c := range []string{"name=systemd", "cpu", "memory"} {
+		if path, ok := byController[c]; ok {
+			return path
+		}
+	}
+	return ""
+}
+
+// containerIDFromCgroup returns the container ID embedded in a cgroup path,
+// or "" for processes that are not in a container.
+func containerIDFromCgroup(path string) string {
+	ids := containerIDPattern.FindAllString(path, -1)
+	if len(ids) == 0 {
+		return ""
+	}
+	return ids[len(ids)-1]
+}
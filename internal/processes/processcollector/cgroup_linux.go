@@ -0,0 +1,43 @@
+//go:build linux
+
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+// agent/processes/processcollector/cgroup_linux.go
+
+package processcollector
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/aaronlmathis/gosight-shared/model"
+)
+
+// attachCgroup labels info with the cgroup of its process, read from
+// /proc/<pid>/cgroup (see applyCgroup). Processes that exited meanwhile are
+// left unlabeled.
+func attachCgroup(info *model.ProcessInfo) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/cgroup", info.PID))
+	if err != nil {
+		return
+	}
+	applyCgroup(info, parseCgroupFile(string(data)))
+}
@@ -0,0 +1,52 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+package processcollector
+
+import (
+	"testing"
+
+	"github.com/aaronlmathis/gosight-shared/model"
+)
+
+func TestCgroupAttribution(t *testing.T) {
+	id := "4f3c2b1a0e9d8c7b6a5f4e3d2c1b0a9f8e7d6c5b4a3f2e1d0c9b8a7f6e5d4c3b"
+
+	tests := []struct {
+		name, file, path, container string
+	}{
+		{"cgroup v2 docker", "0::/system.slice/docker-" + id + ".scope\n", "/system.slice/docker-" + id + ".scope", id},
+		{"cgroup v2 host service", "0::/system.slice/sshd.service\n", "/system.slice/sshd.service", ""},
+		{"cgroup v1 kubernetes", "12:memory:/kubepods/burstable/pod1234/" + id + "\n11:cpu,cpuacct:/kubepods/burstable/pod1234/" + id + "\n1:name=systemd:/kubepods/burstable/pod1234/" + id + "\n",
+			"/kubepods/burstable/pod1234/" + id, id},
+		{"empty", "", "", ""},
+	}
+	for _, tt := range tests {
+		info := model.ProcessInfo{PID: 1}
+		applyCgroup(&info, parseCgroupFile(tt.file))
+		if got := info.Labels["cgroup"]; got != tt.path {
+			t.Errorf("%s: cgroup = %q, want %q", tt.name, got, tt.path)
+		}
+		if got := info.Labels["container_id"]; got != tt.container {
+			t.Errorf("%s: container_id = %q, want %q", tt.name, got, tt.container)
+		}
+	}
+}
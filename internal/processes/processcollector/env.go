@@ -0,0 +1,81 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+// agent/processes/processcollector/env.go
+
+package processcollector
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/shirou/gopsutil/v4/process"
+
+	"github.com/aaronlmathis/gosight-shared/model"
+)
+
+// attachEnv reads the environment of p (/proc/<pid>/environ on Linux) and copies
+// only the allowlisted variables into info.Labels as "env.<NAME>". The full
+// environment is never exported. Processes owned by other users are skipped
+// unless the agent is running privileged.
+func attachEnv(ctx context.Context, p *process.Process, info *model.ProcessInfo, allowlist []string) {
+	if p == nil || !canReadEnv(ctx, p) {
+		return
+	}
+
+	environ, err := p.EnvironWithContext(ctx)
+	if err != nil {
+		return
+	}
+
+	allowed := make(map[string]struct{}, len(allowlist))
+	for _, name := range allowlist {
+		allowed[name] = struct{}{}
+	}
+
+	for _, kv := range environ {
+		name, value, ok := strings.Cut(kv, "=")
+		if !ok {
+			continue
+		}
+		if _, ok := allowed[name]; !ok {
+			continue
+		}
+		if info.Labels == nil {
+			info.Labels = make(map[string]string)
+		}
+		info.Labels["env."+name] = value
+	}
+}
+
+// canReadEnv reports whether the agent should read the environment of p:
+// either the agent is privileged or p is owned by the agent's own user.
+func canReadEnv(ctx context.Context, p *process.Process) bool {
+	euid := os.Geteuid()
+	if euid == 0 {
+		return true
+	}
+	uids, err := p.UidsWithContext(ctx)
+	if err != nil || len(uids) == 0 {
+		return false
+	}
+	return int(uids[0]) == euid
+}
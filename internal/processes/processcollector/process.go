@@ -30,21 +30,56 @@ import (
 
 	"github.com/shirou/gopsutil/v4/process"
 
+	"github.com/aaronlmathis/gosight-agent/internal/config"
 	"github.com/aaronlmathis/gosight-shared/model"
 )
 
 const topN = 20
 
 // Collector captures running processes
-func CollectProcesses(ctx context.Context) (*model.ProcessSnapshot, error) {
+// If cfg configures an environment variable allowlist, the allowed variables
+// are read for the selected processes and attached as "env.<NAME>" labels.
+// Processes younger than the configured min_age are skipped (see excludeYoung).
+// Selected processes are labeled with their cgroup and, for processes inside
+// a container, its container_id (see applyCgroup).
+func CollectProcesses(ctx context.Context, cfg *config.Config) (*model.ProcessSnapshot, error) {
+	snapshot, _, err := CollectProcessesWithAll(ctx, cfg, nil)
+	return snapshot, err
+}
+
+// CollectProcessesWithAll is CollectProcesses but also returns every process
+// seen in the pass, not just the top-N selected for the snapshot, so callers
+// can derive host-wide aggregates (see AggregateByUser) without a second scan.
+// If pick is not nil it selects the snapshot's processes from every process
+// instead of the default top 20 by CPU and memory; only the picked processes
+// are enriched with env and cgroup labels.
+func CollectProcessesWithAll(ctx context.Context, cfg *config.Config, pick func([]model.ProcessInfo) []model.ProcessInfo) (*model.ProcessSnapshot, []model.ProcessInfo, error) {
 	procs, err := process.ProcessesWithContext(ctx)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	all := make([]model.ProcessInfo, 0, len(procs))
+	handles := make(map[int]*process.Process, len(procs))
+
+	var minAge time.Duration
+	var include []string
+	if cfg != nil {
+		minAge = cfg.Agent.ProcessCollection.MinAge
+		include = cfg.Agent.ProcessCollection.MinAgeInclude
+	}
+	now := time.Now()
 
 	for _, p := range procs {
-		info := model.ProcessInfo{PID: int(p.Pid)}
+		var startTime time.Time
+		if start, err := p.CreateTimeWithContext(ctx); err == nil {
+			startTime = time.UnixMilli(start)
+		}
+		if excludeYoung(ctx, p, startTime, now, minAge, include) {
+			continue
+		}
+
+		handles[int(p.Pid)] = p
+		info := model.ProcessInfo{PID: int(p.Pid), StartTime: startTime}
 
 		if pp, err := p.PpidWithContext(ctx); err == nil {
 			info.PPID = int(pp)
@@ -67,13 +102,39 @@ func CollectProcesses(ctx context.Context) (*model.ProcessSnapshot, error) {
 		if threads, err := p.NumThreadsWithContext(ctx); err == nil {
 			info.Threads = int(threads)
 		}
-		if start, err := p.CreateTimeWithContext(ctx); err == nil {
-			info.StartTime = time.UnixMilli(start)
-		}
 		all = append(all, info)
 
 	}
 
+	var picked []model.ProcessInfo
+	if pick != nil {
+		picked = pick(all)
+	} else {
+		picked = TopProcesses(all)
+	}
+
+	final := make([]model.ProcessInfo, 0, len(picked))
+	for _, p := range picked {
+		// Entries that are not a single process (e.g. a summary) have no handle
+		if h, ok := handles[p.PID]; ok && p.PID != 0 {
+			if cfg != nil && len(cfg.Agent.ProcessCollection.EnvAllowlist) > 0 {
+				attachEnv(ctx, h, &p, cfg.Agent.ProcessCollection.EnvAllowlist)
+			}
+			attachCgroup(&p)
+		}
+		final = append(final, p)
+	}
+
+	return &model.ProcessSnapshot{
+		Timestamp: time.Now(),
+		Processes: final,
+	}, all, nil
+
+}
+
+// TopProcesses returns the default snapshot selection: the union of the top
+// 20 processes by CPU and by memory, up to 40 processes.
+func TopProcesses(all []model.ProcessInfo) []model.ProcessInfo {
 	// Sort by CPU to get top 20
 	byCPU := make([]model.ProcessInfo, len(all))
 	copy(byCPU, all)
@@ -103,14 +164,9 @@ func CollectProcesses(ctx context.Context) (*model.ProcessSnapshot, error) {
 		selected[p.PID] = p
 	}
 
-	final := make([]model.ProcessInfo, 0, len(selected))
+	top := make([]model.ProcessInfo, 0, len(selected))
 	for _, p := range selected {
-		final = append(final, p)
+		top = append(top, p)
 	}
-
-	return &model.ProcessSnapshot{
-		Timestamp: time.Now(),
-		Processes: final,
-	}, nil
-
+	return top
 }
@@ -0,0 +1,92 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// agent/processes/processcollector/users.go
+// Per-user aggregation of process resource usage.
+
+package processcollector
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aaronlmathis/gosight-shared/model"
+)
+
+// UserUsage is the combined resource usage of all processes owned by one user.
+type UserUsage struct {
+	User       string
+	CPUPercent float64
+	MemPercent float64
+	Processes  int
+}
+
+// AggregateByUser sums CPU and memory usage per owning user. Processes whose
+// owner could not be resolved are grouped under "unknown". The result is
+// sorted by user name.
+func AggregateByUser(procs []model.ProcessInfo) []UserUsage {
+	byUser := make(map[string]*UserUsage)
+	for _, p := range procs {
+		user := p.User
+		if user == "" {
+			user = "unknown"
+		}
+		u := byUser[user]
+		if u == nil {
+			u = &UserUsage{User: user}
+			byUser[user] = u
+		}
+		u.CPUPercent += p.CPUPercent
+		u.MemPercent += p.MemPercent
+		u.Processes++
+	}
+
+	out := make([]UserUsage, 0, len(byUser))
+	for _, u := range byUser {
+		out = append(out, *u)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].User < out[j].User })
+	return out
+}
+
+var (
+	userUsageMu sync.Mutex
+	userUsage   []UserUsage
+	userUsageAt time.Time
+)
+
+// PublishUserUsage stores the latest per-user aggregate computed by the
+// process runner so the metric pipeline can report it.
+func PublishUserUsage(ts time.Time, usage []UserUsage) {
+	userUsageMu.Lock()
+	defer userUsageMu.Unlock()
+	userUsage = usage
+	userUsageAt = ts
+}
+
+// LatestUserUsage returns the most recently published aggregate and the time
+// of the snapshot it was computed from (zero if none has been published).
+func LatestUserUsage() (time.Time, []UserUsage) {
+	userUsageMu.Lock()
+	defer userUsageMu.Unlock()
+	return userUsageAt, userUsage
+}
@@ -0,0 +1,132 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// agent/processes/processrunner/filter.go
+
+package processrunner
+
+import (
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aaronlmathis/gosight-agent/internal/config"
+	"github.com/aaronlmathis/gosight-shared/model"
+)
+
+// filterProcesses trims a snapshot per process_collection top_n, top_by,
+// min_cpu_percent, min_mem_percent and include, keeping the original order.
+// If nothing is configured the processes are returned unchanged. With
+// summarize_other the dropped processes are appended as one "other" entry.
+func filterProcesses(pc config.ProcessCollectionConfig, procs []model.ProcessInfo) []model.ProcessInfo {
+	if !filtering(pc) {
+		return procs
+	}
+
+	keep := make([]bool, len(procs))
+	if pc.TopN > 0 {
+		byUsage := make([]int, len(procs))
+		for i := range byUsage {
+			byUsage[i] = i
+		}
+		usage := func(p model.ProcessInfo) float64 { return p.CPUPercent }
+		if pc.TopBy == "mem" {
+			usage = func(p model.ProcessInfo) float64 { return p.MemPercent }
+		}
+		sort.SliceStable(byUsage, func(a, b int) bool { return usage(procs[byUsage[a]]) > usage(procs[byUsage[b]]) })
+		for _, i := range byUsage[:min(pc.TopN, len(byUsage))] {
+			keep[i] = true
+		}
+	}
+	for i, p := range procs {
+		if keep[i] {
+			continue
+		}
+		keep[i] = (pc.MinCPUPercent > 0 && p.CPUPercent >= pc.MinCPUPercent) ||
+			(pc.MinMemPercent > 0 && p.MemPercent >= pc.MinMemPercent) ||
+			matchesInclude(p, pc.Include)
+	}
+
+	kept := make([]model.ProcessInfo, 0, min(len(procs), pc.TopN+len(pc.Include)+1))
+	other := model.ProcessInfo{Executable: "other"}
+	dropped := 0
+	for i, p := range procs {
+		if keep[i] {
+			kept = append(kept, p)
+			continue
+		}
+		dropped++
+		other.CPUPercent += p.CPUPercent
+		other.MemPercent += p.MemPercent
+		other.Threads += p.Threads
+	}
+	if pc.SummarizeOther && dropped > 0 {
+		other.Cmdline = strconv.Itoa(dropped) + " processes"
+		other.Labels = map[string]string{"aggregate": "true", "processes": strconv.Itoa(dropped)}
+		kept = append(kept, other)
+	}
+	return kept
+}
+
+// appendIncluded adds the processes in all that match an include glob and
+// are not already in selected.
+func appendIncluded(selected, all []model.ProcessInfo, include []string) []model.ProcessInfo {
+	have := make(map[int]bool, len(selected))
+	for _, p := range selected {
+		have[p.PID] = true
+	}
+	for _, p := range all {
+		if !have[p.PID] && matchesInclude(p, include) {
+			selected = append(selected, p)
+		}
+	}
+	return selected
+}
+
+// filtering reports whether process_collection configures any filter.
+func filtering(pc config.ProcessCollectionConfig) bool {
+	return pc.TopN > 0 || pc.MinCPUPercent > 0 || pc.MinMemPercent > 0
+}
+
+// matchesInclude reports whether the executable's base name or the command
+// name (first word of the command line) matches one of the glob patterns.
+func matchesInclude(p model.ProcessInfo, include []string) bool {
+	if len(include) == 0 {
+		return false
+	}
+	var names []string
+	if p.Executable != "" {
+		names = append(names, filepath.Base(p.Executable))
+	}
+	if fields := strings.Fields(p.Cmdline); len(fields) > 0 {
+		names = append(names, filepath.Base(fields[0]))
+	}
+	for _, pattern := range include {
+		for _, name := range names {
+			if ok, _ := path.Match(pattern, name); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+package processrunner
+
+import (
+	"testing"
+
+	"github.com/aaronlmathis/gosight-agent/internal/config"
+	"github.com/aaronlmathis/gosight-shared/model"
+)
+
+func TestFilterProcesses(t *testing.T) {
+	procs := []model.ProcessInfo{
+		{PID: 1, Executable: "/sbin/init", CPUPercent: 0.1, MemPercent: 0.2, Threads: 1},
+		{PID: 2, Executable: "/usr/bin/java", CPUPercent: 40, MemPercent: 10, Threads: 50},
+		{PID: 3, Executable: "/usr/bin/postgres", CPUPercent: 5, MemPercent: 30, Threads: 4},
+		{PID: 4, Executable: "/usr/sbin/sshd", CPUPercent: 0, MemPercent: 0.1, Threads: 1},
+		{PID: 5, Executable: "/usr/bin/node", CPUPercent: 20, MemPercent: 2, Threads: 10},
+	}
+
+	pc := config.ProcessCollectionConfig{TopN: 1, MinMemPercent: 25, Include: []string{"sshd"}, SummarizeOther: true}
+	got := filterProcesses(pc, procs)
+	var pids []int
+	for _, p := range got {
+		pids = append(pids, p.PID)
+	}
+	// java (top cpu), postgres (mem threshold), sshd (include), then "other"
+	if len(got) != 4 || pids[0] != 2 || pids[1] != 3 || pids[2] != 4 {
+		t.Fatalf("unexpected processes kept: %v", pids)
+	}
+	other := got[3]
+	if other.Executable != "other" || other.Threads != 11 || other.CPUPercent != 20.1 || other.Labels["processes"] != "2" {
+		t.Errorf("unexpected other summary: %+v", other)
+	}
+
+	pc = config.ProcessCollectionConfig{TopN: 2, TopBy: "mem"}
+	if got := filterProcesses(pc, procs); len(got) != 2 || got[0].PID != 2 || got[1].PID != 3 {
+		t.Errorf("unexpected top 2 by memory: %+v", got)
+	}
+
+	if got := filterProcesses(config.ProcessCollectionConfig{}, procs); len(got) != len(procs) {
+		t.Errorf("expected no filtering without options, got %d processes", len(got))
+	}
+
+	// Include alone extends a selection without duplicating its entries
+	got = appendIncluded(append([]model.ProcessInfo(nil), procs[1:3]...), procs, []string{"sshd", "java"})
+	if len(got) != 3 || got[2].PID != 4 {
+		t.Errorf("unexpected selection with include: %+v", got)
+	}
+}
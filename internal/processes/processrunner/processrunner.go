@@ -32,6 +32,8 @@ import (
 	"github.com/aaronlmathis/gosight-agent/internal/meta"
 	"github.com/aaronlmathis/gosight-agent/internal/processes/processcollector"
 	"github.com/aaronlmathis/gosight-agent/internal/processes/processsender"
+	"github.com/aaronlmathis/gosight-agent/internal/selfmetrics"
+	agentutils "github.com/aaronlmathis/gosight-agent/internal/utils"
 
 	"github.com/aaronlmathis/gosight-shared/model"
 	"github.com/aaronlmathis/gosight-shared/utils"
@@ -87,44 +89,95 @@ func (r *ProcessRunner) Close() {
 // The worker pool is managed by the ProcessSender, which handles the sending of data to the server.
 func (r *ProcessRunner) Run(ctx context.Context) {
 	taskQueue := make(chan *model.ProcessPayload, 100)
-	go r.ProcessSender.StartWorkerPool(ctx, taskQueue, r.Config.Agent.ProcessCollection.Workers)
+	selfmetrics.TrackQueue("processes", taskQueue)
+	workerCtx, stopWorkers := agentutils.WorkerContext(ctx)
 
-	ticker := time.NewTicker(r.Config.Agent.ProcessCollection.Interval)
+	config.RLock()
+	workers := r.Config.Agent.ProcessCollection.Workers
+	interval := r.Config.Agent.ProcessCollection.Interval
+	deadline := r.Config.Agent.CycleDeadline
+	config.RUnlock()
+
+	go r.ProcessSender.StartWorkerPool(workerCtx, taskQueue, workers)
+
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
-	utils.Info("ProcessRunner started. Collecting processes every %v", r.Config.Agent.ProcessCollection.Interval)
+	utils.Info("ProcessRunner started. Collecting processes every %v", interval)
+
+	cycles := selfmetrics.NewCycleTracker("processes", interval, deadline)
 
 	for {
 		select {
 		case <-ctx.Done():
 			utils.Warn("ProcessRunner shutting down")
+			config.RLock()
+			timeout := r.Config.Agent.ShutdownDrainTimeout
+			config.RUnlock()
+			agentutils.DrainQueue("processes", taskQueue, timeout, stopWorkers)
 			return
 		case <-ticker.C:
-			snapshot, err := processcollector.CollectProcesses(ctx)
-			if err != nil {
-				utils.Error("Failed to collect processes: %v", err)
-				continue
-			}
-
-			metaCopy := meta.CloneMetaWithTags(r.Meta, nil)
-			metaCopy.EndpointID = utils.GenerateEndpointID(metaCopy)
-
-			payload := &model.ProcessPayload{
-				AgentID:    metaCopy.AgentID,
-				HostID:     metaCopy.HostID,
-				Hostname:   metaCopy.Hostname,
-				EndpointID: metaCopy.EndpointID,
-				Timestamp:  snapshot.Timestamp,
-				Processes:  snapshot.Processes,
-				Meta:       metaCopy,
+			start := time.Now()
+			config.RLock()
+			// A config reload may have changed the interval
+			if iv := r.Config.Agent.ProcessCollection.Interval; iv > 0 && iv != interval {
+				utils.Info("Process collection interval changed from %v to %v", interval, iv)
+				interval = iv
+				ticker.Reset(iv)
 			}
+			r.collectCycle(ctx, taskQueue)
+			config.RUnlock()
+			cycles.Observe(start)
+		}
+	}
+}
 
-			select {
-			case taskQueue <- payload:
-				// ok
-			default:
-				utils.Warn("Process task queue full. Dropping snapshot")
-			}
+// collectCycle collects processes once and queues the snapshot for sending.
+// The caller holds the config read lock.
+func (r *ProcessRunner) collectCycle(ctx context.Context, taskQueue chan<- *model.ProcessPayload) {
+	pc := r.Config.Agent.ProcessCollection
+
+	// Filters apply to every process, not just the default top-N selection.
+	// Include on its own adds its matches to the default selection.
+	var pick func([]model.ProcessInfo) []model.ProcessInfo
+	switch {
+	case filtering(pc):
+		pick = func(all []model.ProcessInfo) []model.ProcessInfo { return filterProcesses(pc, all) }
+	case len(pc.Include) > 0:
+		pick = func(all []model.ProcessInfo) []model.ProcessInfo {
+			return appendIncluded(processcollector.TopProcesses(all), all, pc.Include)
 		}
 	}
+	snapshot, all, err := processcollector.CollectProcessesWithAll(ctx, r.Config, pick)
+	if err != nil {
+		utils.Error("Failed to collect processes: %v", err)
+		return
+	}
+	if pc.PerUser {
+		processcollector.PublishUserUsage(snapshot.Timestamp, processcollector.AggregateByUser(all))
+		if pc.PerUserOnly {
+			return
+		}
+	}
+
+	metaCopy := meta.CloneMetaWithTags(r.Meta, nil)
+	metaCopy.EndpointID = meta.GenerateEndpointID(metaCopy)
+
+	payload := &model.ProcessPayload{
+		AgentID:    metaCopy.AgentID,
+		HostID:     metaCopy.HostID,
+		Hostname:   metaCopy.Hostname,
+		EndpointID: metaCopy.EndpointID,
+		Timestamp:  snapshot.Timestamp,
+		Processes:  snapshot.Processes,
+		Meta:       metaCopy,
+	}
+
+	select {
+	case taskQueue <- payload:
+		// ok
+	default:
+		utils.Warn("Process task queue full. Dropping snapshot")
+		selfmetrics.RecordDropped("processes", 1)
+	}
 }
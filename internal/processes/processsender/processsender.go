@@ -32,6 +32,9 @@ import (
 	"github.com/aaronlmathis/gosight-agent/internal/config"
 	grpcconn "github.com/aaronlmathis/gosight-agent/internal/grpc"
 	"github.com/aaronlmathis/gosight-agent/internal/protohelper"
+	"github.com/aaronlmathis/gosight-agent/internal/selfmetrics"
+	"github.com/aaronlmathis/gosight-agent/internal/sink"
+	agentutils "github.com/aaronlmathis/gosight-agent/internal/utils"
 	"github.com/aaronlmathis/gosight-shared/model"
 	"github.com/aaronlmathis/gosight-shared/proto"
 	"github.com/aaronlmathis/gosight-shared/utils"
@@ -61,23 +64,32 @@ type ProcessSender struct {
 // It returns immediately and launches the background connection manager.
 func NewSender(ctx context.Context, cfg *config.Config) (*ProcessSender, error) {
 	s := &ProcessSender{ctx: ctx, cfg: cfg}
+	if sink.Enabled(cfg) {
+		// Dry run: print snapshots instead of connecting
+		s.stream = sink.NewProcessStream(nil)
+		utils.Info("Process snapshots are printed to stdout; not connecting to the server")
+		return s, nil
+	}
 	go s.manageConnection()
 	return s, nil
 }
 
 // manageConnection dials + opens the Stream, tears down on global disconnect,
-// and retries with exponential backoff up to totalCap.
+// and retries with the backoff shared with the other senders. A server that is down
+// at startup therefore never blocks agent construction; snapshots are simply
+// rejected with Unavailable until the stream is open. It exits when ctx ends.
 func (s *ProcessSender) manageConnection() {
-	const (
-		initial    = 1 * time.Second
-		maxBackoff = 15 * time.Minute // Changed from 10s to 15min
-		factor     = 2                // Multiplier for exponential backoff
-	)
-
-	backoff := initial
 	var lastPause time.Time
 
 	for {
+		// Check for context cancellation
+		select {
+		case <-s.ctx.Done():
+			utils.Info("Process connection manager shutting down")
+			return
+		default:
+		}
+
 		// If we've entered a new global pause window, tear down our stream
 		pu := grpcconn.GetPauseUntil()
 		if pu.After(lastPause) {
@@ -86,7 +98,6 @@ func (s *ProcessSender) manageConnection() {
 				_ = s.stream.CloseSend()
 			}
 			s.stream = nil
-			backoff = initial
 			lastPause = pu
 		}
 
@@ -96,14 +107,9 @@ func (s *ProcessSender) manageConnection() {
 		// Dial (or reuse) the gRPC connection
 		cc, err := grpcconn.GetGRPCConn(s.cfg)
 		if err != nil {
-			utils.Info("Server offline (dial): retrying in %s", backoff)
-			time.Sleep(backoff)
-			// Calculate next backoff duration
-			if backoff < maxBackoff {
-				backoff = time.Duration(float64(backoff) * float64(factor))
-				if backoff > maxBackoff {
-					backoff = maxBackoff
-				}
+			grpcconn.ReportFailure("process dial")
+			if !grpcconn.WaitForRetry(s.ctx) {
+				return
 			}
 			continue
 		}
@@ -114,25 +120,33 @@ func (s *ProcessSender) manageConnection() {
 		if s.stream == nil {
 			st, err := s.client.Stream(s.ctx)
 			if err != nil {
-				utils.Info("Server offline (stream): retrying in %s", backoff)
-				time.Sleep(backoff)
-				// Calculate next backoff duration
-				if backoff < maxBackoff {
-					backoff = time.Duration(float64(backoff) * float64(factor))
-					if backoff > maxBackoff {
-						backoff = maxBackoff
-					}
+				grpcconn.ReportFailure("process stream")
+				if !grpcconn.WaitForRetry(s.ctx) {
+					return
 				}
 				continue
 			}
 			s.stream = st
 			utils.Info("Process stream connected")
-			// reset backoff now that we're actually online
-			backoff = initial
+			// end the shared backoff now that we're actually online
+			grpcconn.ReportSuccess()
 		}
 
 		// Short sleep so we can check for future disconnects
-		time.Sleep(time.Second)
+		if !s.sleep(time.Second) {
+			return
+		}
+	}
+}
+
+// sleep waits for d or until the sender's context is cancelled.
+// It returns false if the context was cancelled.
+func (s *ProcessSender) sleep(d time.Duration) bool {
+	select {
+	case <-time.After(d):
+		return true
+	case <-s.ctx.Done():
+		return false
 	}
 }
 
@@ -175,13 +189,27 @@ func (s *ProcessSender) SendSnapshot(payload *model.ProcessPayload) error {
 			Process: &proto.ProcessWrapper{RawPayload: b},
 		},
 	}
-	// send without additional retries
-	if err := s.stream.Send(sp); err != nil {
+	selfmetrics.RecordExportSize("processes", goproto.Size(sp))
+
+	// send without additional retries; drop a broken stream so the
+	// connection manager reopens it on its next pass
+	st := s.stream
+	if st == nil {
+		return status.Error(codes.Unavailable, "no active process stream")
+	}
+	if err := st.Send(sp); err != nil {
+		s.stream = nil
 		return fmt.Errorf("stream send failed: %w", err)
 	}
 	return nil
 }
 
+// Wait waits up to timeout for the stopped worker pool to finish its
+// in-flight exports. It returns false if they are still running.
+func (s *ProcessSender) Wait(timeout time.Duration) bool {
+	return agentutils.WaitWorkers(&s.wg, timeout)
+}
+
 // Close waits for workers then closes the gRPC connection.
 func (s *ProcessSender) Close() error {
 	utils.Info("Closing ProcessSender...")
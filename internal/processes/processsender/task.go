@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/aaronlmathis/gosight-agent/internal/selfmetrics"
 	"github.com/aaronlmathis/gosight-shared/model"
 	"github.com/aaronlmathis/gosight-shared/utils"
 	"google.golang.org/grpc/codes"
@@ -47,6 +48,7 @@ func (s *ProcessSender) StartWorkerPool(ctx context.Context, queue <-chan *model
 				// Try to send it
 				if err := s.SendSnapshot(payload); err != nil {
 					utils.Warn("Process worker %d failed to send payload: %v", id, err)
+					selfmetrics.RecordSendError("processes")
 				}
 			}
 		}(i + 1)
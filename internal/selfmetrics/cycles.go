@@ -0,0 +1,142 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// agent/internal/selfmetrics/cycles.go
+// Times the runners' collection cycles and reports overruns.
+package selfmetrics
+
+import (
+	"sync"
+	"time"
+
+	agentutils "github.com/aaronlmathis/gosight-agent/internal/utils"
+	"github.com/aaronlmathis/gosight-shared/model"
+	"github.com/aaronlmathis/gosight-shared/utils"
+)
+
+// overrunLogInterval is the minimum time between overrun warnings per runner.
+const overrunLogInterval = time.Minute
+
+// CycleStats describes the collection cycles of one runner.
+type CycleStats struct {
+	LastDuration time.Duration
+	Overruns     uint64
+}
+
+var (
+	cycleMu    sync.Mutex
+	cycleStats = make(map[string]CycleStats)
+
+	// cycleWindows holds cycle durations in milliseconds since the last
+	// report, per runner.
+	cycleWindows = make(map[string]*agentutils.Window)
+)
+
+// CycleTracker times the collection cycles of one runner (metrics, logs,
+// processes), from collecting to queuing the payloads, and warns when a cycle
+// takes longer than its deadline, which means ticks are backing up and
+// payloads will start being dropped.
+type CycleTracker struct {
+	runner   string
+	deadline time.Duration
+
+	lastWarn   time.Time
+	suppressed int
+
+	// markers and seq drive the cycle boundary markers (see markers.go)
+	markers bool
+	seq     uint64
+}
+
+// NewCycleTracker creates a tracker for runner. A zero deadline defaults to
+// the runner's interval.
+func NewCycleTracker(runner string, interval, deadline time.Duration) *CycleTracker {
+	if deadline <= 0 {
+		deadline = interval
+	}
+	return &CycleTracker{runner: runner, deadline: deadline}
+}
+
+// Observe records the duration of a cycle that started at start.
+func (t *CycleTracker) Observe(start time.Time) {
+	d := time.Since(start)
+	over := t.deadline > 0 && d > t.deadline
+
+	cycleMu.Lock()
+	st := cycleStats[t.runner]
+	st.LastDuration = d
+	if over {
+		st.Overruns++
+	}
+	cycleStats[t.runner] = st
+	w := cycleWindows[t.runner]
+	if w == nil {
+		w = &agentutils.Window{}
+		cycleWindows[t.runner] = w
+	}
+	cycleMu.Unlock()
+	w.Observe(float64(d) / float64(time.Millisecond))
+
+	if !over {
+		return
+	}
+	if time.Since(t.lastWarn) < overrunLogInterval {
+		t.suppressed++
+		return
+	}
+	if t.suppressed > 0 {
+		utils.Warn("%s collection cycle took %s, over its %s deadline (%d more overruns since last report); increase the interval or disable collectors",
+			t.runner, d.Round(time.Millisecond), t.deadline, t.suppressed)
+	} else {
+		utils.Warn("%s collection cycle took %s, over its %s deadline; increase the interval or disable collectors",
+			t.runner, d.Round(time.Millisecond), t.deadline)
+	}
+	t.lastWarn = time.Now()
+	t.suppressed = 0
+}
+
+// CycleDurations returns the latest cycle stats per runner.
+func CycleDurations() map[string]CycleStats {
+	cycleMu.Lock()
+	defer cycleMu.Unlock()
+
+	out := make(map[string]CycleStats, len(cycleStats))
+	for runner, st := range cycleStats {
+		out[runner] = st
+	}
+	return out
+}
+
+// TakeCycleWindows returns, per runner, the distribution of cycle durations
+// in milliseconds since the previous call. Runners without cycles in the
+// window are omitted.
+func TakeCycleWindows() map[string]*model.StatisticValues {
+	cycleMu.Lock()
+	defer cycleMu.Unlock()
+
+	out := make(map[string]*model.StatisticValues, len(cycleWindows))
+	for runner, w := range cycleWindows {
+		if stats := w.Take(); stats != nil {
+			out[runner] = stats
+		}
+	}
+	return out
+}
@@ -0,0 +1,85 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// agent/internal/selfmetrics/exports.go
+// Package selfmetrics accumulates measurements about the agent's own
+// operation that are reported by the self collectors.
+package selfmetrics
+
+import "sync"
+
+// ExportSizeStats summarizes the serialized size of the export requests sent
+// for one signal (metrics, logs, processes) during a reporting period.
+type ExportSizeStats struct {
+	Count      uint64
+	TotalBytes uint64
+	MaxBytes   uint64
+}
+
+// AvgBytes returns the mean request size, or 0 if nothing was sent.
+func (s ExportSizeStats) AvgBytes() float64 {
+	if s.Count == 0 {
+		return 0
+	}
+	return float64(s.TotalBytes) / float64(s.Count)
+}
+
+var (
+	exportMu    sync.Mutex
+	exportSizes = make(map[string]*ExportSizeStats)
+)
+
+// RecordExportSize records the serialized size of one request for signal,
+// measured just before it is sent.
+func RecordExportSize(signal string, bytes int) {
+	if bytes < 0 {
+		return
+	}
+	n := uint64(bytes)
+
+	exportMu.Lock()
+	defer exportMu.Unlock()
+
+	st := exportSizes[signal]
+	if st == nil {
+		st = &ExportSizeStats{}
+		exportSizes[signal] = st
+	}
+	st.Count++
+	st.TotalBytes += n
+	if n > st.MaxBytes {
+		st.MaxBytes = n
+	}
+}
+
+// TakeExportSizes returns the per-signal stats recorded since the previous
+// call and starts a new period.
+func TakeExportSizes() map[string]ExportSizeStats {
+	exportMu.Lock()
+	defer exportMu.Unlock()
+
+	out := make(map[string]ExportSizeStats, len(exportSizes))
+	for signal, st := range exportSizes {
+		out[signal] = *st
+	}
+	exportSizes = make(map[string]*ExportSizeStats)
+	return out
+}
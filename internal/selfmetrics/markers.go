@@ -0,0 +1,66 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// agent/internal/selfmetrics/markers.go
+// Logs the boundaries of collection cycles for timing investigations.
+
+package selfmetrics
+
+import (
+	"time"
+
+	"github.com/aaronlmathis/gosight-shared/utils"
+)
+
+// CycleCounts are the item counts (metrics or log entries) of one cycle.
+// Queued items were handed to the sender workers; dropped items were
+// discarded by the agent (filters, series cap or a full queue).
+type CycleCounts struct {
+	Collected int
+	Queued    int
+	Dropped   int
+}
+
+// EnableMarkers makes Begin and End log debug-level markers at the start and
+// end of every cycle (agent.cycle_markers).
+func (t *CycleTracker) EnableMarkers(enabled bool) {
+	t.markers = enabled
+}
+
+// Begin starts a cycle and returns its start time for End. With markers
+// enabled it logs the cycle's start marker.
+func (t *CycleTracker) Begin() time.Time {
+	t.seq++
+	if t.markers {
+		utils.Debug("cycle marker: runner=%s cycle=%d event=start", t.runner, t.seq)
+	}
+	return time.Now()
+}
+
+// End records the duration of the cycle started at start (see Observe). With
+// markers enabled it logs the cycle's end marker with counts.
+func (t *CycleTracker) End(start time.Time, counts CycleCounts) {
+	t.Observe(start)
+	if t.markers {
+		utils.Debug("cycle marker: runner=%s cycle=%d event=end collected=%d queued=%d dropped=%d duration=%s",
+			t.runner, t.seq, counts.Collected, counts.Queued, counts.Dropped, time.Since(start).Round(time.Millisecond))
+	}
+}
@@ -0,0 +1,164 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// agent/internal/selfmetrics/pipeline.go
+// Counts what flows through the collect -> queue -> send pipeline.
+package selfmetrics
+
+import (
+	"sync"
+	"time"
+)
+
+// Spool describes a sender's on-disk spool of exports that failed while the
+// server was unreachable.
+type Spool struct {
+	Pending     int
+	Evicted     uint64 // oldest records evicted to respect the size cap
+	Expired     uint64 // records dropped for exceeding the maximum age
+	Quarantined uint64 // corrupt records moved aside instead of sent
+	Replayed    uint64
+}
+
+// Queue reports the current length and capacity of a runner's task queue.
+type Queue struct {
+	Length   int
+	Capacity int
+}
+
+var (
+	pipelineMu sync.Mutex
+
+	metricsCollected   uint64
+	queueDropped       = make(map[string]uint64)
+	sendErrors         = make(map[string]uint64)
+	collectorDurations = make(map[string]time.Duration)
+	queues             = make(map[string]func() Queue)
+	spools             = make(map[string]func() Spool)
+)
+
+// RecordCollected adds n to the number of metrics collected since start.
+func RecordCollected(n int) {
+	if n <= 0 {
+		return
+	}
+	pipelineMu.Lock()
+	metricsCollected += uint64(n)
+	pipelineMu.Unlock()
+}
+
+// RecordDropped adds n items (metrics, log entries, process snapshots,
+// spans) that runner dropped because its task queue was full.
+func RecordDropped(runner string, n int) {
+	if n <= 0 {
+		return
+	}
+	pipelineMu.Lock()
+	queueDropped[runner] += uint64(n)
+	pipelineMu.Unlock()
+}
+
+// RecordSendError counts a payload of signal that a sender worker failed to send.
+func RecordSendError(signal string) {
+	pipelineMu.Lock()
+	sendErrors[signal]++
+	pipelineMu.Unlock()
+}
+
+// RecordCollectorDuration records how long the latest run of a collector took.
+func RecordCollectorDuration(collector string, d time.Duration) {
+	pipelineMu.Lock()
+	collectorDurations[collector] = d
+	pipelineMu.Unlock()
+}
+
+// TrackQueue registers a runner's task queue so its length is reported.
+func TrackQueue[T any](runner string, queue chan T) {
+	pipelineMu.Lock()
+	queues[runner] = func() Queue { return Queue{Length: len(queue), Capacity: cap(queue)} }
+	pipelineMu.Unlock()
+}
+
+// SpoolSource is implemented by spool.Outbox.
+type SpoolSource interface {
+	Pending() int
+	Evicted() uint64
+	Expired() uint64
+	Quarantined() uint64
+	Replayed() uint64
+}
+
+// TrackSpool registers a sender's spool so its backlog and losses are reported.
+func TrackSpool(signal string, src SpoolSource) {
+	pipelineMu.Lock()
+	spools[signal] = func() Spool {
+		return Spool{
+			Pending:     src.Pending(),
+			Evicted:     src.Evicted(),
+			Expired:     src.Expired(),
+			Quarantined: src.Quarantined(),
+			Replayed:    src.Replayed(),
+		}
+	}
+	pipelineMu.Unlock()
+}
+
+// PipelineStats is a snapshot of the pipeline counters; counters are totals
+// since the agent started.
+type PipelineStats struct {
+	MetricsCollected   uint64
+	QueueDropped       map[string]uint64
+	SendErrors         map[string]uint64
+	CollectorDurations map[string]time.Duration
+	Queues             map[string]Queue
+	Spools             map[string]Spool
+}
+
+// Pipeline returns a snapshot of the pipeline counters and queue lengths.
+func Pipeline() PipelineStats {
+	pipelineMu.Lock()
+	defer pipelineMu.Unlock()
+
+	st := PipelineStats{
+		MetricsCollected:   metricsCollected,
+		QueueDropped:       make(map[string]uint64, len(queueDropped)),
+		SendErrors:         make(map[string]uint64, len(sendErrors)),
+		CollectorDurations: make(map[string]time.Duration, len(collectorDurations)),
+		Queues:             make(map[string]Queue, len(queues)),
+		Spools:             make(map[string]Spool, len(spools)),
+	}
+	for k, v := range queueDropped {
+		st.QueueDropped[k] = v
+	}
+	for k, v := range sendErrors {
+		st.SendErrors[k] = v
+	}
+	for k, v := range collectorDurations {
+		st.CollectorDurations[k] = v
+	}
+	for k, q := range queues {
+		st.Queues[k] = q()
+	}
+	for k, sp := range spools {
+		st.Spools[k] = sp()
+	}
+	return st
+}
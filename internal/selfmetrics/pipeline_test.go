@@ -0,0 +1,57 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+package selfmetrics
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPipelineStats(t *testing.T) {
+	queue := make(chan int, 4)
+	queue <- 1
+	TrackQueue("test", queue)
+
+	before := Pipeline()
+	RecordCollected(5)
+	RecordDropped("test", 3)
+	RecordDropped("test", 0)
+	RecordSendError("test")
+	RecordCollectorDuration("cpu", 20*time.Millisecond)
+
+	st := Pipeline()
+	if got := st.MetricsCollected - before.MetricsCollected; got != 5 {
+		t.Errorf("metrics collected = %d, want 5", got)
+	}
+	if got := st.QueueDropped["test"] - before.QueueDropped["test"]; got != 3 {
+		t.Errorf("dropped = %d, want 3", got)
+	}
+	if got := st.SendErrors["test"] - before.SendErrors["test"]; got != 1 {
+		t.Errorf("send errors = %d, want 1", got)
+	}
+	if st.CollectorDurations["cpu"] != 20*time.Millisecond {
+		t.Errorf("cpu duration = %v", st.CollectorDurations["cpu"])
+	}
+	if q := st.Queues["test"]; q.Length != 1 || q.Capacity != 4 {
+		t.Errorf("queue = %+v, want length 1 capacity 4", q)
+	}
+}
@@ -0,0 +1,3 @@
+// internal/sink/doc.go
+// Package sink contains the stdout export sink used for dry runs
+package sink
@@ -0,0 +1,188 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// internal/sink/stdout.go
+// stdout.go implements the senders' OTLP clients and process stream by
+// printing each request as a line of JSON instead of sending it.
+
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/aaronlmathis/gosight-agent/internal/config"
+	"github.com/aaronlmathis/gosight-shared/proto"
+	collogpb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	goproto "google.golang.org/protobuf/proto"
+)
+
+// Stdout is the value of agent.sink that prints payloads instead of sending them.
+const Stdout = "stdout"
+
+// Enabled reports whether cfg selects the stdout sink.
+func Enabled(cfg *config.Config) bool {
+	return cfg != nil && cfg.Agent.Sink == Stdout
+}
+
+// Writer prints exports as newline-delimited JSON objects of the form
+// {"signal":"metrics","time":"...","request":{...}}, where request is the
+// protobuf JSON form of what would have been sent. It is safe for
+// concurrent use by the sender workers.
+type Writer struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+var stdout = &Writer{out: os.Stdout}
+
+// NewWriter returns a Writer printing to out.
+func NewWriter(out io.Writer) *Writer {
+	return &Writer{out: out}
+}
+
+// Write prints one export of signal.
+func (w *Writer) Write(signal string, msg goproto.Message) error {
+	req, err := protojson.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshal %s export: %w", signal, err)
+	}
+	line, err := json.Marshal(struct {
+		Signal  string          `json:"signal"`
+		Time    time.Time       `json:"time"`
+		Request json.RawMessage `json:"request"`
+	}{signal, time.Now(), req})
+	if err != nil {
+		return fmt.Errorf("marshal %s export: %w", signal, err)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, err = w.out.Write(append(line, '\n'))
+	return err
+}
+
+// MetricsClient is a colmetricpb.MetricsServiceClient that prints requests.
+type MetricsClient struct{ w *Writer }
+
+// NewMetricsClient returns a metrics client printing to w (stdout if nil).
+func NewMetricsClient(w *Writer) *MetricsClient {
+	return &MetricsClient{w: orStdout(w)}
+}
+
+// Export prints req and reports success.
+func (c *MetricsClient) Export(_ context.Context, req *colmetricpb.ExportMetricsServiceRequest, _ ...grpc.CallOption) (*colmetricpb.ExportMetricsServiceResponse, error) {
+	if err := c.w.Write("metrics", req); err != nil {
+		return nil, err
+	}
+	return &colmetricpb.ExportMetricsServiceResponse{}, nil
+}
+
+// LogsClient is a collogpb.LogsServiceClient that prints requests.
+type LogsClient struct{ w *Writer }
+
+// NewLogsClient returns a logs client printing to w (stdout if nil).
+func NewLogsClient(w *Writer) *LogsClient {
+	return &LogsClient{w: orStdout(w)}
+}
+
+// Export prints req and reports success.
+func (c *LogsClient) Export(_ context.Context, req *collogpb.ExportLogsServiceRequest, _ ...grpc.CallOption) (*collogpb.ExportLogsServiceResponse, error) {
+	if err := c.w.Write("logs", req); err != nil {
+		return nil, err
+	}
+	return &collogpb.ExportLogsServiceResponse{}, nil
+}
+
+// TracesClient is a coltracepb.TraceServiceClient that prints requests.
+type TracesClient struct{ w *Writer }
+
+// NewTracesClient returns a traces client printing to w (stdout if nil).
+func NewTracesClient(w *Writer) *TracesClient {
+	return &TracesClient{w: orStdout(w)}
+}
+
+// Export prints req and reports success.
+func (c *TracesClient) Export(_ context.Context, req *coltracepb.ExportTraceServiceRequest, _ ...grpc.CallOption) (*coltracepb.ExportTraceServiceResponse, error) {
+	if err := c.w.Write("traces", req); err != nil {
+		return nil, err
+	}
+	return &coltracepb.ExportTraceServiceResponse{}, nil
+}
+
+// ProcessStream is a proto.StreamService_StreamClient that prints the
+// process snapshots sent on it, decoded from their raw wrapper. Only Send and
+// CloseSend are supported; the agent never receives on the process stream.
+type ProcessStream struct {
+	grpc.ClientStream
+	w *Writer
+}
+
+// NewProcessStream returns a process stream printing to w (stdout if nil).
+func NewProcessStream(w *Writer) *ProcessStream {
+	return &ProcessStream{w: orStdout(w)}
+}
+
+// Send prints the process payload carried by p; other payloads are printed as is.
+func (s *ProcessStream) Send(p *proto.StreamPayload) error {
+	if wrapper := p.GetProcess(); wrapper != nil {
+		var payload proto.ProcessPayload
+		if err := goproto.Unmarshal(wrapper.RawPayload, &payload); err != nil {
+			return fmt.Errorf("decode process payload: %w", err)
+		}
+		return s.w.Write("processes", &payload)
+	}
+	return s.w.Write("stream", p)
+}
+
+// Recv blocks forever: the stdout sink never sends commands.
+func (s *ProcessStream) Recv() (*proto.StreamResponse, error) {
+	select {}
+}
+
+// CloseSend is a no-op.
+func (s *ProcessStream) CloseSend() error {
+	return nil
+}
+
+func orStdout(w *Writer) *Writer {
+	if w == nil {
+		return stdout
+	}
+	return w
+}
+
+// Compile-time checks that the sink satisfies the senders' client interfaces.
+var (
+	_ colmetricpb.MetricsServiceClient = (*MetricsClient)(nil)
+	_ collogpb.LogsServiceClient       = (*LogsClient)(nil)
+	_ coltracepb.TraceServiceClient    = (*TracesClient)(nil)
+	_ proto.StreamService_StreamClient = (*ProcessStream)(nil)
+)
@@ -0,0 +1,70 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/aaronlmathis/gosight-shared/proto"
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	goproto "google.golang.org/protobuf/proto"
+)
+
+func TestStdoutSink(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+
+	if _, err := NewMetricsClient(w).Export(context.Background(), &colmetricpb.ExportMetricsServiceRequest{}); err != nil {
+		t.Fatalf("metrics export: %v", err)
+	}
+	raw, err := goproto.Marshal(&proto.ProcessPayload{Hostname: "web-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sp := &proto.StreamPayload{Payload: &proto.StreamPayload_Process{Process: &proto.ProcessWrapper{RawPayload: raw}}}
+	if err := NewProcessStream(w).Send(sp); err != nil {
+		t.Fatalf("process send: %v", err)
+	}
+
+	type line struct {
+		Signal  string          `json:"signal"`
+		Request json.RawMessage `json:"request"`
+	}
+	var lines []line
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var l line
+		if err := dec.Decode(&l); err != nil {
+			t.Fatalf("decode output: %v", err)
+		}
+		lines = append(lines, l)
+	}
+	if len(lines) != 2 || lines[0].Signal != "metrics" || lines[1].Signal != "processes" {
+		t.Fatalf("unexpected output: %+v", lines)
+	}
+	if !bytes.Contains(lines[1].Request, []byte(`"web-1"`)) {
+		t.Errorf("process payload not decoded: %s", lines[1].Request)
+	}
+}
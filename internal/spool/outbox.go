@@ -0,0 +1,94 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/spool/outbox.go
+// outbox.go couples a spool with its replayer for one sender.
+
+package spool
+
+import (
+	"time"
+)
+
+// Outbox is the spool of one sender: exports that fail because the server
+// is unreachable are saved, and Drain re-sends a batch of the backlog,
+// oldest-first, before each live export.
+type Outbox struct {
+	spool    *Spool
+	replayer *Replayer
+	batch    int
+}
+
+// OpenOutbox opens the spool in dir, capped at maxBytes (0 = unbounded).
+// Records older than maxAge are dropped on replay (0 = kept), and Drain
+// re-sends up to batch records per call (0 = all) through send.
+func OpenOutbox(dir string, maxBytes int64, maxAge time.Duration, batch int, send func(data []byte) error) (*Outbox, error) {
+	s, err := Open(dir)
+	if err != nil {
+		return nil, err
+	}
+	s.SetMaxBytes(maxBytes)
+	return &Outbox{
+		spool:    s,
+		replayer: NewReplayer(s, ReplayOptions{MaxAge: maxAge}, send),
+		batch:    batch,
+	}, nil
+}
+
+// Save spools data for a later Drain.
+func (o *Outbox) Save(data []byte) error {
+	return o.spool.Put(data)
+}
+
+// Drain re-sends spooled records oldest-first, up to the batch size. It
+// returns the first send error; the failed record stays at the head.
+func (o *Outbox) Drain() error {
+	if o.spool.Len() == 0 {
+		return nil
+	}
+	_, err := o.replayer.Replay(o.batch)
+	return err
+}
+
+// Pending returns the number of spooled records.
+func (o *Outbox) Pending() int {
+	return o.spool.Len()
+}
+
+// Evicted returns how many records were evicted to respect the size cap.
+func (o *Outbox) Evicted() uint64 {
+	return o.spool.Evicted()
+}
+
+// Expired returns how many records were dropped for exceeding the maximum age.
+func (o *Outbox) Expired() uint64 {
+	return o.replayer.Expired()
+}
+
+// Quarantined returns how many corrupt records were moved aside instead of sent.
+func (o *Outbox) Quarantined() uint64 {
+	return o.spool.Quarantined()
+}
+
+// Replayed returns how many records have been re-sent.
+func (o *Outbox) Replayed() uint64 {
+	return o.replayer.Replayed()
+}
@@ -0,0 +1,121 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/spool/replay.go
+// replay.go drains a spool oldest-first, skipping records that are too old.
+
+package spool
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aaronlmathis/gosight-shared/utils"
+)
+
+// ReplayOptions is the replay policy of a Replayer.
+type ReplayOptions struct {
+	// MaxAge drops records spooled longer ago than this instead of sending
+	// them. Zero keeps records regardless of age.
+	MaxAge time.Duration
+}
+
+// Replayer re-sends spooled records in strict oldest-first order. A record is
+// removed only after send succeeds; on failure it stays at the head of the
+// spool and is retried first next time.
+type Replayer struct {
+	spool *Spool
+	opts  ReplayOptions
+	send  func(data []byte) error
+
+	// mu serialises replays so records are never sent twice or out of order.
+	mu sync.Mutex
+
+	replayed atomic.Uint64
+	expired  atomic.Uint64
+}
+
+// NewReplayer creates a Replayer that hands record data to send.
+func NewReplayer(s *Spool, opts ReplayOptions, send func(data []byte) error) *Replayer {
+	return &Replayer{spool: s, opts: opts, send: send}
+}
+
+// Replay sends up to n of the oldest records (all of them if n <= 0) and
+// returns how many were sent. Expired records are dropped along the way and
+// do not count toward n. It stops at the first send error, which is returned.
+func (r *Replayer) Replay(n int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	sent, dropped := 0, 0
+	defer func() {
+		if dropped > 0 {
+			utils.Warn("Dropped %d spooled records older than %v", dropped, r.opts.MaxAge)
+		}
+	}()
+
+	for n <= 0 || sent < n {
+		rec, err := r.spool.Next()
+		if err != nil {
+			return sent, err
+		}
+		if rec == nil {
+			return sent, nil
+		}
+
+		if r.expiredAt(rec, now) {
+			if err := r.spool.Remove(rec); err != nil {
+				return sent, err
+			}
+			dropped++
+			r.expired.Add(1)
+			continue
+		}
+
+		if err := r.send(rec.Data); err != nil {
+			return sent, err
+		}
+		if err := r.spool.Remove(rec); err != nil {
+			return sent, err
+		}
+		sent++
+		r.replayed.Add(1)
+	}
+	return sent, nil
+}
+
+// Replayed returns how many records have been re-sent.
+func (r *Replayer) Replayed() uint64 {
+	return r.replayed.Load()
+}
+
+// Expired returns how many records have been dropped for exceeding MaxAge.
+func (r *Replayer) Expired() uint64 {
+	return r.expired.Load()
+}
+
+// expiredAt reports whether rec is older than MaxAge at now. Records whose
+// spool time is unknown are kept.
+func (r *Replayer) expiredAt(rec *Record, now time.Time) bool {
+	return r.opts.MaxAge > 0 && !rec.Created.IsZero() && now.Sub(rec.Created) > r.opts.MaxAge
+}
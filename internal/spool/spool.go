@@ -0,0 +1,316 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/spool/spool.go
+
+// Package spool provides a durable on-disk queue for payloads that could not
+// be sent to the server. Each payload is stored as its own record file with a
+// CRC32 checksum so partial writes and corruption (e.g. after power loss) are
+// detected on replay. Corrupt records are moved to a quarantine directory and
+// counted instead of being sent. A Replayer re-sends records oldest-first,
+// dropping those older than a maximum age.
+package spool
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aaronlmathis/gosight-shared/utils"
+)
+
+const (
+	recordExt     = ".rec"
+	quarantineDir = "quarantine"
+	headerSize    = 12 // magic(4) + crc32(4) + length(4)
+)
+
+// recordMagic identifies a spool record and its format version.
+var recordMagic = []byte("GSP1")
+
+// ErrCorrupt is returned when a record fails validation.
+var ErrCorrupt = errors.New("spool: corrupt record")
+
+// Record is a single spooled payload.
+type Record struct {
+	ID      string    // file name of the record, ordered oldest-first
+	Created time.Time // when the record was spooled
+	Data    []byte
+}
+
+// Spool is a directory of checksummed record files.
+type Spool struct {
+	dir string
+
+	mu  sync.Mutex
+	seq uint64
+
+	// maxBytes caps the total size of the records (0 = unbounded); limitMu
+	// serialises enforcing it
+	maxBytes atomic.Int64
+	limitMu  sync.Mutex
+
+	quarantined atomic.Uint64
+	evicted     atomic.Uint64
+}
+
+// Open opens (creating if needed) a spool rooted at dir.
+func Open(dir string) (*Spool, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create spool dir %s: %w", dir, err)
+	}
+	return &Spool{dir: dir}, nil
+}
+
+// SetMaxBytes caps the total size of the spooled records. When a Put takes
+// the spool over the cap, the oldest records are evicted (and counted, see
+// Evicted) until it fits again; the newest record is always kept. Zero or
+// less removes the cap.
+func (s *Spool) SetMaxBytes(n int64) {
+	s.maxBytes.Store(n)
+}
+
+// Dir returns the spool directory.
+func (s *Spool) Dir() string {
+	return s.dir
+}
+
+// Put durably writes data as a new record. The record is written to a
+// temporary file, synced, and renamed so readers never see a partial file
+// under its final name.
+func (s *Spool) Put(data []byte) error {
+	s.mu.Lock()
+	s.seq++
+	name := fmt.Sprintf("%020d-%06d%s", time.Now().UnixNano(), s.seq%1000000, recordExt)
+	s.mu.Unlock()
+
+	buf := encodeRecord(data)
+
+	final := filepath.Join(s.dir, name)
+	tmp := final + ".tmp"
+
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(buf); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, final); err != nil {
+		return err
+	}
+	return s.enforceLimit()
+}
+
+// enforceLimit evicts the oldest records while the spool exceeds maxBytes.
+func (s *Spool) enforceLimit() error {
+	limit := s.maxBytes.Load()
+	if limit <= 0 {
+		return nil
+	}
+	s.limitMu.Lock()
+	defer s.limitMu.Unlock()
+
+	ids, err := s.list()
+	if err != nil {
+		return err
+	}
+	sizes := make([]int64, len(ids))
+	var total int64
+	for i, id := range ids {
+		if fi, err := os.Stat(filepath.Join(s.dir, id)); err == nil {
+			sizes[i] = fi.Size()
+			total += sizes[i]
+		}
+	}
+
+	evicted := 0
+	for i := 0; total > limit && i < len(ids)-1; i++ {
+		if err := os.Remove(filepath.Join(s.dir, ids[i])); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		total -= sizes[i]
+		evicted++
+	}
+	if evicted > 0 {
+		s.evicted.Add(uint64(evicted))
+		utils.Warn("Spool %s over its %d byte cap; evicted %d oldest records", s.dir, limit, evicted)
+	}
+	return nil
+}
+
+// Next returns the oldest valid record, or nil if the spool is empty.
+// Corrupt records encountered along the way are quarantined and skipped.
+// The record stays in the spool until Remove is called.
+func (s *Spool) Next() (*Record, error) {
+	ids, err := s.list()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		rec, err := s.read(id)
+		if errors.Is(err, ErrCorrupt) {
+			s.quarantine(id, err)
+			continue
+		}
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return rec, nil
+	}
+	return nil, nil
+}
+
+// Remove deletes a record after it has been delivered.
+func (s *Spool) Remove(rec *Record) error {
+	err := os.Remove(filepath.Join(s.dir, rec.ID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// Len returns the number of records currently spooled.
+func (s *Spool) Len() int {
+	ids, err := s.list()
+	if err != nil {
+		return 0
+	}
+	return len(ids)
+}
+
+// Quarantined returns how many corrupt records have been quarantined since Open.
+func (s *Spool) Quarantined() uint64 {
+	return s.quarantined.Load()
+}
+
+// Evicted returns how many records have been evicted to respect the size cap since Open.
+func (s *Spool) Evicted() uint64 {
+	return s.evicted.Load()
+}
+
+// list returns record IDs sorted oldest-first. Leftover temp files from
+// interrupted writes are ignored.
+func (s *Spool) list() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), recordExt) {
+			continue
+		}
+		ids = append(ids, e.Name())
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// read loads and validates a record.
+func (s *Spool) read(id string) (*Record, error) {
+	raw, err := os.ReadFile(filepath.Join(s.dir, id))
+	if err != nil {
+		return nil, err
+	}
+	data, err := decodeRecord(raw)
+	if err != nil {
+		return nil, err
+	}
+	return &Record{ID: id, Created: recordTime(id), Data: data}, nil
+}
+
+// quarantine moves a corrupt record aside so it is never replayed.
+func (s *Spool) quarantine(id string, cause error) {
+	s.quarantined.Add(1)
+	utils.Warn("Quarantining corrupt spool record %s: %v", id, cause)
+
+	qdir := filepath.Join(s.dir, quarantineDir)
+	if err := os.MkdirAll(qdir, 0700); err == nil {
+		if err := os.Rename(filepath.Join(s.dir, id), filepath.Join(qdir, id)); err == nil {
+			return
+		}
+	}
+	// If it can't be moved, at least make sure it is not replayed again
+	_ = os.Remove(filepath.Join(s.dir, id))
+}
+
+// encodeRecord frames data as magic | crc32 | length | data.
+func encodeRecord(data []byte) []byte {
+	buf := make([]byte, headerSize+len(data))
+	copy(buf[0:4], recordMagic)
+	binary.BigEndian.PutUint32(buf[4:8], crc32.ChecksumIEEE(data))
+	binary.BigEndian.PutUint32(buf[8:12], uint32(len(data)))
+	copy(buf[headerSize:], data)
+	return buf
+}
+
+// decodeRecord validates the framing and checksum and returns the payload.
+func decodeRecord(raw []byte) ([]byte, error) {
+	if len(raw) < headerSize || !bytes.Equal(raw[0:4], recordMagic) {
+		return nil, fmt.Errorf("%w: bad header", ErrCorrupt)
+	}
+	sum := binary.BigEndian.Uint32(raw[4:8])
+	n := binary.BigEndian.Uint32(raw[8:12])
+	if uint64(len(raw)-headerSize) != uint64(n) {
+		return nil, fmt.Errorf("%w: length %d, have %d bytes", ErrCorrupt, n, len(raw)-headerSize)
+	}
+	data := raw[headerSize:]
+	if crc32.ChecksumIEEE(data) != sum {
+		return nil, fmt.Errorf("%w: checksum mismatch", ErrCorrupt)
+	}
+	return data, nil
+}
+
+// recordTime recovers the spool time encoded in a record ID.
+func recordTime(id string) time.Time {
+	ts, _, _ := strings.Cut(id, "-")
+	ns, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(0, ns)
+}
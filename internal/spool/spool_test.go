@@ -0,0 +1,219 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+package spool
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPutNextRemove(t *testing.T) {
+	s, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	for _, p := range []string{"first", "second"} {
+		if err := s.Put([]byte(p)); err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+	}
+
+	rec, err := s.Next()
+	if err != nil || rec == nil {
+		t.Fatalf("Next: %v, %v", rec, err)
+	}
+	if string(rec.Data) != "first" {
+		t.Errorf("expected oldest record first, got %q", rec.Data)
+	}
+	if err := s.Remove(rec); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+
+	rec, _ = s.Next()
+	if rec == nil || string(rec.Data) != "second" {
+		t.Fatalf("expected second record, got %v", rec)
+	}
+	_ = s.Remove(rec)
+
+	if rec, _ := s.Next(); rec != nil {
+		t.Errorf("expected empty spool, got %q", rec.Data)
+	}
+}
+
+func TestMaxBytesEvictsOldest(t *testing.T) {
+	s, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	// Room for two 10-byte records with their headers
+	s.SetMaxBytes(2 * (headerSize + 10))
+
+	for i := 0; i < 4; i++ {
+		if err := s.Put([]byte(fmt.Sprintf("record-%03d", i))); err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+	}
+	if s.Len() != 2 || s.Evicted() != 2 {
+		t.Fatalf("expected 2 records kept and 2 evicted, got %d and %d", s.Len(), s.Evicted())
+	}
+	if rec, _ := s.Next(); rec == nil || string(rec.Data) != "record-002" {
+		t.Errorf("expected the oldest kept record to be record-002, got %v", rec)
+	}
+}
+
+func TestCorruptRecordIsQuarantined(t *testing.T) {
+	dir := t.TempDir()
+	s, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	if err := s.Put([]byte("will be corrupted")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if err := s.Put([]byte("good")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	// Flip a payload byte in the oldest record
+	ids, _ := s.list()
+	path := filepath.Join(dir, ids[0])
+	raw, _ := os.ReadFile(path)
+	raw[len(raw)-1] ^= 0xff
+	if err := os.WriteFile(path, raw, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	rec, err := s.Next()
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	if rec == nil || string(rec.Data) != "good" {
+		t.Fatalf("expected corrupt record to be skipped, got %v", rec)
+	}
+	if got := s.Quarantined(); got != 1 {
+		t.Errorf("expected 1 quarantined record, got %d", got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, quarantineDir, ids[0])); err != nil {
+		t.Errorf("expected record in quarantine dir: %v", err)
+	}
+}
+
+func TestTruncatedRecord(t *testing.T) {
+	buf := encodeRecord([]byte("payload"))
+	if _, err := decodeRecord(buf[:len(buf)-2]); err == nil {
+		t.Error("expected error for truncated record")
+	}
+}
+
+func TestReplayOrderAndMaxAge(t *testing.T) {
+	dir := t.TempDir()
+	s, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	for _, p := range []string{"stale", "first", "second", "third"} {
+		if err := s.Put([]byte(p)); err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+	}
+
+	// Backdate the oldest record by two days
+	ids, _ := s.list()
+	old := fmt.Sprintf("%020d-%06d%s", time.Now().Add(-48*time.Hour).UnixNano(), 0, recordExt)
+	if err := os.Rename(filepath.Join(dir, ids[0]), filepath.Join(dir, old)); err != nil {
+		t.Fatal(err)
+	}
+
+	var sent []string
+	fail := false
+	r := NewReplayer(s, ReplayOptions{MaxAge: 24 * time.Hour}, func(data []byte) error {
+		if fail {
+			return errors.New("unavailable")
+		}
+		sent = append(sent, string(data))
+		return nil
+	})
+
+	if _, err := r.Replay(1); err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if len(sent) != 1 || sent[0] != "first" || r.Expired() != 1 {
+		t.Fatalf("expected stale record dropped and first sent, got %v (expired %d)", sent, r.Expired())
+	}
+
+	fail = true
+	if _, err := r.Replay(0); err == nil {
+		t.Fatal("expected send error")
+	}
+	if s.Len() != 2 {
+		t.Errorf("expected failed record to stay spooled, have %d records", s.Len())
+	}
+
+	fail = false
+	if n, err := r.Replay(0); err != nil || n != 2 {
+		t.Fatalf("Replay: %d, %v", n, err)
+	}
+	if got := strings.Join(sent, ","); got != "first,second,third" {
+		t.Errorf("expected oldest-first replay, got %s", got)
+	}
+	if r.Replayed() != 3 || s.Len() != 0 {
+		t.Errorf("expected 3 replayed and empty spool, got %d replayed, %d left", r.Replayed(), s.Len())
+	}
+}
+
+func TestOutboxDrain(t *testing.T) {
+	var sent []string
+	down := true
+	ob, err := OpenOutbox(t.TempDir(), 0, 0, 0, func(data []byte) error {
+		if down {
+			return errors.New("unreachable")
+		}
+		sent = append(sent, string(data))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("OpenOutbox: %v", err)
+	}
+	for _, p := range []string{"a", "b", "c"} {
+		if err := ob.Save([]byte(p)); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+
+	if err := ob.Drain(); err == nil || ob.Pending() != 3 {
+		t.Fatalf("expected the backlog kept while sends fail, got err %v and %d pending", err, ob.Pending())
+	}
+	down = false
+	if err := ob.Drain(); err != nil {
+		t.Fatalf("Drain: %v", err)
+	}
+	if strings.Join(sent, "") != "abc" || ob.Pending() != 0 || ob.Replayed() != 3 {
+		t.Errorf("expected a, b, c replayed in order, got %v (%d pending)", sent, ob.Pending())
+	}
+}
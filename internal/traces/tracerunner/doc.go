@@ -0,0 +1,3 @@
+// internal/traces/tracerunner/doc.go
+// Package tracerunner contains the trace pipeline runner
+package tracerunner
@@ -0,0 +1,125 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// internal/traces/tracerunner/runner.go
+
+package tracerunner
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aaronlmathis/gosight-agent/internal/config"
+	"github.com/aaronlmathis/gosight-agent/internal/meta"
+	"github.com/aaronlmathis/gosight-agent/internal/selfmetrics"
+	"github.com/aaronlmathis/gosight-agent/internal/traces/tracesender"
+	agentutils "github.com/aaronlmathis/gosight-agent/internal/utils"
+	"github.com/aaronlmathis/gosight-shared/model"
+	"github.com/aaronlmathis/gosight-shared/utils"
+)
+
+// TraceRunner owns the trace export queue. Unlike the metric and log runners
+// it does not collect on a ticker: spans are handed to it with Submit (by
+// the otlp_socket source, see custom.SetSpanSink) and exported by the
+// TraceSender's worker pool.
+type TraceRunner struct {
+	Config      *config.Config
+	TraceSender *tracesender.TraceSender
+	Meta        *model.Meta
+
+	queue chan *model.TracePayload
+}
+
+// NewRunner creates a TraceRunner and its sender. The sender starts
+// connecting immediately; payloads submitted before Run are buffered.
+func NewRunner(ctx context.Context, cfg *config.Config, baseMeta *model.Meta) (*TraceRunner, error) {
+	sender, err := tracesender.NewSender(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace sender: %w", err)
+	}
+
+	queueSize := cfg.Agent.TraceCollection.QueueSize
+	if queueSize <= 0 {
+		queueSize = 100
+	}
+
+	return &TraceRunner{
+		Config:      cfg,
+		TraceSender: sender,
+		Meta:        baseMeta,
+		queue:       make(chan *model.TracePayload, queueSize),
+	}, nil
+}
+
+// Submit queues spans for export with the agent's meta attached. It never
+// blocks: if the queue is full the spans are dropped and false is returned.
+func (r *TraceRunner) Submit(spans []model.TraceSpan) bool {
+	if len(spans) == 0 {
+		return true
+	}
+
+	config.RLock()
+	metaCopy := meta.CloneMetaWithTags(r.Meta, nil)
+	config.RUnlock()
+	metaCopy.EndpointID = meta.GenerateEndpointID(metaCopy)
+
+	payload := &model.TracePayload{
+		Meta:   metaCopy,
+		Traces: spans,
+	}
+
+	select {
+	case r.queue <- payload:
+		return true
+	default:
+		utils.Warn("Trace task queue full. Dropping %d spans", len(spans))
+		selfmetrics.RecordDropped("traces", len(spans))
+		return false
+	}
+}
+
+// Run starts the export workers and blocks until ctx is done.
+func (r *TraceRunner) Run(ctx context.Context) {
+	config.RLock()
+	workers := r.Config.Agent.TraceCollection.Workers
+	config.RUnlock()
+	if workers <= 0 {
+		workers = 1
+	}
+	selfmetrics.TrackQueue("traces", r.queue)
+	workerCtx, stopWorkers := agentutils.WorkerContext(ctx)
+	r.TraceSender.StartWorkerPool(workerCtx, r.queue, workers)
+	utils.Info("TraceRunner started with %d workers", workers)
+
+	<-ctx.Done()
+	utils.Warn("TraceRunner shutting down")
+	config.RLock()
+	timeout := r.Config.Agent.ShutdownDrainTimeout
+	config.RUnlock()
+	agentutils.DrainQueue("traces", r.queue, timeout, stopWorkers)
+}
+
+// Close waits for the export workers and closes the trace sender.
+func (r *TraceRunner) Close() {
+	if r.TraceSender != nil {
+		_ = r.TraceSender.Close()
+	}
+}
@@ -0,0 +1,3 @@
+// internal/traces/tracesender/doc.go
+// Package tracesender contains trace sending
+package tracesender
@@ -0,0 +1,174 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// internal/traces/tracesender/sender.go
+
+package tracesender
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aaronlmathis/gosight-agent/internal/config"
+	grpcconn "github.com/aaronlmathis/gosight-agent/internal/grpc"
+	"github.com/aaronlmathis/gosight-agent/internal/otelconvert"
+	"github.com/aaronlmathis/gosight-agent/internal/selfmetrics"
+	"github.com/aaronlmathis/gosight-agent/internal/sink"
+	agentutils "github.com/aaronlmathis/gosight-agent/internal/utils"
+	"github.com/aaronlmathis/gosight-shared/model"
+	"github.com/aaronlmathis/gosight-shared/utils"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	goproto "google.golang.org/protobuf/proto"
+)
+
+// TraceSender holds the gRPC client and connection for OTLP traces.
+type TraceSender struct {
+	traceClient coltracepb.TraceServiceClient
+	cc          *grpc.ClientConn
+	// disconnected is the DisconnectNotify channel current when traceClient
+	// was created; it is closed by the next global disconnect.
+	disconnected <-chan struct{}
+	wg           sync.WaitGroup
+	cfg          *config.Config
+	ctx          context.Context
+}
+
+// NewSender initializes a new TraceSender and starts the connection manager.
+// It returns immediately and launches the background connection manager.
+func NewSender(ctx context.Context, cfg *config.Config) (*TraceSender, error) {
+	s := &TraceSender{ctx: ctx, cfg: cfg}
+	if sink.Enabled(cfg) {
+		// Dry run: print exports instead of connecting
+		s.traceClient = sink.NewTracesClient(nil)
+		utils.Info("Trace exports are printed to stdout; not connecting to the server")
+		return s, nil
+	}
+	go s.manageConnection()
+	return s, nil
+}
+
+// manageConnection dials & maintains the connection, mirroring the metric sender:
+// it honors the global pause, drops the client on a global disconnect and retries
+// with the backoff shared with the other senders (see grpcconn.WaitForRetry). The
+// disconnect channel is captured when the client connects, so a disconnect that
+// happens between two passes of the loop is not missed, and it is only ever
+// checked alongside a timeout or shutdown so the loop never blocks on it.
+func (s *TraceSender) manageConnection() {
+	for {
+		// Check for context cancellation
+		select {
+		case <-s.ctx.Done():
+			utils.Info("Trace connection manager shutting down")
+			return
+		default:
+		}
+
+		// Honor any global pause
+		grpcconn.WaitForResume()
+
+		// Handle a global disconnect command
+		if s.traceClient != nil {
+			select {
+			case <-s.disconnected:
+				utils.Info("Global disconnect: closing trace connection")
+				s.traceClient = nil
+				continue
+			case <-s.ctx.Done():
+				return
+			case <-time.After(time.Second):
+				continue
+			}
+		}
+
+		// Ensure we have a live ClientConn from the shared helper (TLS, proxy, etc.)
+		disconnected := grpcconn.DisconnectNotify()
+		cc, err := grpcconn.GetGRPCConn(s.cfg)
+		if err != nil {
+			grpcconn.ReportFailure("traces dial")
+			if !grpcconn.WaitForRetry(s.ctx) {
+				return
+			}
+			continue
+		}
+
+		s.cc = cc
+		s.disconnected = disconnected
+		s.traceClient = coltracepb.NewTraceServiceClient(cc)
+		utils.Info("OTLP traces client connected")
+		grpcconn.ReportSuccess()
+	}
+}
+
+// SendTraces converts the TracePayload to OTLP format and exports it via unary call.
+// If there is no active client it returns Unavailable so the worker retries later.
+func (s *TraceSender) SendTraces(payload *model.TracePayload) error {
+	client := s.traceClient
+	if client == nil {
+		return status.Error(codes.Unavailable, "no active OTLP traces client")
+	}
+
+	req := otelconvert.ConvertToOTLPTraces(payload)
+	if req == nil {
+		utils.Warn("Dropping trace payload with no valid spans")
+		return nil
+	}
+	config.RLock()
+	if payload.Meta != nil {
+		otelconvert.MapTracesResourceTags(req, payload.Meta.Tags, s.cfg.Agent.ResourceAttributeMapping)
+	}
+	otelconvert.SetTracesScopeVersion(req, s.cfg.Agent.ScopeVersion)
+	config.RUnlock()
+
+	agentutils.DebugPayload(s.cfg, "traces", req)
+	selfmetrics.RecordExportSize("traces", goproto.Size(req))
+
+	ctx, cancel := context.WithTimeout(s.ctx, 30*time.Second)
+	defer cancel()
+
+	if _, err := client.Export(ctx, req, grpcconn.ExportCallOptions(s.cfg)...); err != nil {
+		utils.Warn("OTLP traces export failed: %v", err)
+		return err
+	}
+
+	utils.Debug("Successfully exported %d spans via OTLP", len(payload.Traces))
+	return nil
+}
+
+// Wait waits up to timeout for the stopped worker pool to finish its
+// in-flight exports. It returns false if they are still running.
+func (s *TraceSender) Wait(timeout time.Duration) bool {
+	return agentutils.WaitWorkers(&s.wg, timeout)
+}
+
+// Close waits for the workers and closes the gRPC connection.
+func (s *TraceSender) Close() error {
+	utils.Info("Closing TraceSender... waiting for workers")
+	s.wg.Wait()
+	utils.Info("All TraceSender workers finished")
+	if s.cc != nil {
+		return s.cc.Close()
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// internal/traces/tracesender/task.go
+
+package tracesender
+
+import (
+	"context"
+	"time"
+
+	"github.com/aaronlmathis/gosight-agent/internal/selfmetrics"
+	"github.com/aaronlmathis/gosight-shared/model"
+	"github.com/aaronlmathis/gosight-shared/utils"
+)
+
+// StartWorkerPool launches workerCount workers that export trace payloads from
+// queue. Workers wait while the sender is disconnected and run until ctx is done.
+func (s *TraceSender) StartWorkerPool(ctx context.Context, queue <-chan *model.TracePayload, workerCount int) {
+	for i := 0; i < workerCount; i++ {
+		s.wg.Add(1)
+		go func(id int) {
+			defer s.wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					utils.Info("Trace worker #%d shutting down", id)
+					return
+				default:
+				}
+
+				if s.traceClient == nil {
+					time.Sleep(500 * time.Millisecond)
+					continue
+				}
+
+				var payload *model.TracePayload
+				select {
+				case payload = <-queue:
+				case <-ctx.Done():
+					utils.Info("Trace worker #%d shutting down", id)
+					return
+				}
+
+				if err := s.SendTraces(payload); err != nil {
+					utils.Warn("Trace worker #%d failed to send payload: %v", id, err)
+					selfmetrics.RecordSendError("traces")
+				}
+			}
+		}(i + 1)
+	}
+}
@@ -39,7 +39,17 @@ func LoadCursor(path string) (string, error) {
 	return strings.TrimSpace(string(data)), nil
 }
 
-// SaveCursor writes the given journald cursor to a file.
+// SaveCursor writes the given journald cursor to a file. The cursor is
+// written to a temporary file and renamed into place, so a crash never
+// leaves a partially written cursor behind.
 func SaveCursor(path, cursor string) error {
-	return os.WriteFile(path, []byte(cursor), 0644)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(cursor), 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
 }
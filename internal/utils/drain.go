@@ -0,0 +1,89 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/utils/drain.go
+// drain.go - lets worker pools flush their queue on shutdown
+
+package agentutils
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aaronlmathis/gosight-shared/utils"
+)
+
+// drainPoll is how often DrainQueue checks whether the queue is empty.
+const drainPoll = 50 * time.Millisecond
+
+// WorkerContext returns the context a runner hands its worker pool. It is not
+// cancelled with ctx, so the workers keep exporting while the runner drains
+// its queue on shutdown; the runner calls the returned stop function once the
+// queue is empty (see DrainQueue).
+func WorkerContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithCancel(context.WithoutCancel(ctx))
+}
+
+// DrainQueue waits up to timeout for the workers consuming queue to empty it,
+// then stops them with stopWorkers. runner names the queue in the log. It
+// returns false if payloads were left in the queue when the timeout expired.
+// A zero timeout stops the workers right away.
+func DrainQueue[T any](runner string, queue chan T, timeout time.Duration, stopWorkers context.CancelFunc) bool {
+	defer stopWorkers()
+
+	if len(queue) == 0 {
+		return true
+	}
+	if timeout <= 0 {
+		utils.Warn("Discarding %d queued %s payloads on shutdown", len(queue), runner)
+		return false
+	}
+
+	utils.Info("Flushing %d queued %s payloads (up to %v)", len(queue), runner, timeout)
+	deadline := time.Now().Add(timeout)
+	for len(queue) > 0 {
+		if time.Now().After(deadline) {
+			utils.Warn("Shutdown drain timeout reached; discarding %d queued %s payloads", len(queue), runner)
+			return false
+		}
+		time.Sleep(drainPoll)
+	}
+	return true
+}
+
+// WaitWorkers waits up to timeout for a sender's worker pool to return,
+// which its workers do after finishing the export they are in once the pool
+// is stopped. It returns false if the workers were still busy at the timeout.
+func WaitWorkers(wg *sync.WaitGroup, timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
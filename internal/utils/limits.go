@@ -0,0 +1,41 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/utils/limits.go
+// limits.go - enforces caps on dynamically discovered sources
+
+package agentutils
+
+import (
+	"github.com/aaronlmathis/gosight-shared/utils"
+)
+
+// ApplyLimit returns at most max items, logging a clear warning naming the
+// limit when items are dropped. kind describes the source (e.g. "file tailers")
+// and setting names the config key that raises the cap.
+func ApplyLimit[T any](kind, setting string, items []T, max int) []T {
+	if max <= 0 || len(items) <= max {
+		return items
+	}
+	utils.Warn("%d %s requested but the limit is %d; ignoring %d (raise agent.limits.%s to allow more)",
+		len(items), kind, max, len(items)-max, setting)
+	return items[:max]
+}
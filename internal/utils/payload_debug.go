@@ -0,0 +1,64 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/utils/payload_debug.go
+// payload_debug.go - opt-in logging of outgoing payloads for troubleshooting
+
+package agentutils
+
+import (
+	"strings"
+
+	"github.com/aaronlmathis/gosight-agent/internal/config"
+	"github.com/aaronlmathis/gosight-shared/utils"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// defaultPayloadDebugMaxBytes caps logged payloads when no limit is configured.
+const defaultPayloadDebugMaxBytes = 64 * 1024
+
+// DebugPayload logs the JSON form of msg to the debug log just before it is sent.
+// It only does work when logs.payload_debug is enabled and the log level is debug,
+// and truncates the JSON to logs.payload_debug_max_bytes.
+func DebugPayload(cfg *config.Config, kind string, msg proto.Message) {
+	config.RLock()
+	defer config.RUnlock()
+	if cfg == nil || !cfg.Logs.PayloadDebug || !strings.EqualFold(cfg.Logs.LogLevel, "debug") {
+		return
+	}
+
+	data, err := protojson.Marshal(msg)
+	if err != nil {
+		utils.Debug("payload debug: failed to marshal %s payload: %v", kind, err)
+		return
+	}
+
+	limit := cfg.Logs.PayloadDebugMaxBytes
+	if limit <= 0 {
+		limit = defaultPayloadDebugMaxBytes
+	}
+	if len(data) > limit {
+		utils.Debug("payload debug: %s (%d bytes, truncated to %d): %s", kind, len(data), limit, data[:limit])
+		return
+	}
+	utils.Debug("payload debug: %s (%d bytes): %s", kind, len(data), data)
+}
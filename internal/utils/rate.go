@@ -0,0 +1,144 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/utils/rate.go
+// rate.go - timestamp convention for metrics computed over a window
+
+package agentutils
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/aaronlmathis/gosight-shared/utils"
+)
+
+// Rate timestamp conventions (agent.metric_collection.rate_timestamp).
+const (
+	RateTimestampEnd   = "end"
+	RateTimestampStart = "start"
+)
+
+// Clock step handling (agent.metric_collection.clock_step).
+const (
+	ClockStepMonotonic = "monotonic"
+	ClockStepReset     = "reset"
+)
+
+// clockStepTolerance is how far the wall clock may drift from the monotonic
+// clock over one window before it is treated as a step (e.g. NTP correction).
+const clockStepTolerance = time.Second
+
+// clockStepWarnEvery rate-limits the clock step warning.
+const clockStepWarnEvery = time.Minute
+
+var (
+	rateAtWindowStart atomic.Bool
+	resetOnClockStep  atomic.Bool
+	lastClockStepWarn atomic.Int64
+)
+
+// monoBase anchors Monotonic; time.Since uses the monotonic clock reading
+// carried by time.Now, so it is unaffected by wall clock steps.
+var monoBase = time.Now()
+
+// SetRateTimestamp selects whether rate metrics (CPU percent, network rates,
+// ...) are stamped at the start or the end (default) of the window their
+// value was measured over.
+func SetRateTimestamp(mode string) error {
+	switch mode {
+	case "", RateTimestampEnd:
+		rateAtWindowStart.Store(false)
+	case RateTimestampStart:
+		rateAtWindowStart.Store(true)
+	default:
+		return fmt.Errorf("rate_timestamp must be %q or %q, got %q", RateTimestampStart, RateTimestampEnd, mode)
+	}
+	return nil
+}
+
+// RateTimestamp returns the timestamp for a rate measured between start and
+// end under the configured convention. A zero start (no previous sample)
+// falls back to end.
+func RateTimestamp(start, end time.Time) time.Time {
+	if rateAtWindowStart.Load() && !start.IsZero() {
+		return start
+	}
+	return end
+}
+
+// SetClockStep selects how rates are handled when the wall clock steps
+// between two samples: "monotonic" (default) keeps computing them over the
+// monotonic interval, "reset" drops the rate for the window spanning the step.
+func SetClockStep(mode string) error {
+	switch mode {
+	case "", ClockStepMonotonic:
+		resetOnClockStep.Store(false)
+	case ClockStepReset:
+		resetOnClockStep.Store(true)
+	default:
+		return fmt.Errorf("clock_step must be %q or %q, got %q", ClockStepMonotonic, ClockStepReset, mode)
+	}
+	return nil
+}
+
+// Monotonic returns a reading of the monotonic clock. Store it next to a
+// sample's wall time and pass both to RateInterval.
+func Monotonic() time.Duration {
+	return time.Since(monoBase)
+}
+
+// RateInterval returns the seconds between two samples taken at monotonic
+// readings prevMono and curMono, with wall times prevWall and curWall, and
+// whether a rate should be computed over it. It is false for the first sample
+// (zero prevWall), a non-positive interval, or, with clock_step "reset", when
+// the wall clock stepped between the samples.
+func RateInterval(prevWall, curWall time.Time, prevMono, curMono time.Duration) (float64, bool) {
+	elapsed := curMono - prevMono
+	if prevWall.IsZero() || elapsed <= 0 {
+		return 0, false
+	}
+
+	// Compare wall clock readings only; Sub would use the monotonic ones
+	step := curWall.Round(0).Sub(prevWall.Round(0)) - elapsed
+	if step > clockStepTolerance || step < -clockStepTolerance {
+		warnClockStep(step)
+		if resetOnClockStep.Load() {
+			return 0, false
+		}
+	}
+	return elapsed.Seconds(), true
+}
+
+// warnClockStep logs a detected wall clock step at most once per minute.
+func warnClockStep(step time.Duration) {
+	now := time.Now().UnixNano()
+	last := lastClockStepWarn.Load()
+	if now-last < int64(clockStepWarnEvery) || !lastClockStepWarn.CompareAndSwap(last, now) {
+		return
+	}
+	if resetOnClockStep.Load() {
+		utils.Warn("System clock stepped by %v; dropping rates spanning the step", step.Round(time.Millisecond))
+	} else {
+		utils.Warn("System clock stepped by %v; rates use the monotonic interval", step.Round(time.Millisecond))
+	}
+}
@@ -0,0 +1,75 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/utils/series.go
+// series.go identifies metric series independently of their values.
+
+package agentutils
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aaronlmathis/gosight-shared/model"
+)
+
+// SeriesFingerprintDimension is the dimension that carries a metric's
+// fingerprint when agent.metric_collection.series_fingerprint is enabled.
+const SeriesFingerprintDimension = "series_id"
+
+// SeriesKey identifies a metric series by its lowercased
+// "namespace.subnamespace.name" and sorted dimensions, e.g.
+// "system.disk.used_percent|mountpoint=/". The fingerprint dimension itself
+// is ignored so the key is the same before and after it is attached.
+func SeriesKey(m model.Metric) string {
+	parts := []string{m.Namespace}
+	if m.SubNamespace != "" {
+		parts = append(parts, m.SubNamespace)
+	}
+	name := strings.ToLower(strings.Join(append(parts, m.Name), "."))
+
+	keys := make([]string, 0, len(m.Dimensions))
+	for k := range m.Dimensions {
+		if k != SeriesFingerprintDimension {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(name)
+	for _, k := range keys {
+		b.WriteString("|" + k + "=" + m.Dimensions[k])
+	}
+	return b.String()
+}
+
+// SeriesFingerprint returns a deterministic 16 hex digit hash of SeriesKey,
+// stable across agent restarts and hosts, for grouping and de-duplicating
+// series without re-hashing names and dimensions.
+func SeriesFingerprint(m model.Metric) string {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(SeriesKey(m)))
+	s := strconv.FormatUint(h.Sum64(), 16)
+	return strings.Repeat("0", 16-len(s)) + s
+}
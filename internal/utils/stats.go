@@ -0,0 +1,81 @@
+/*
+SPDX-License-Identifier: GPL-3.0-or-later
+
+Copyright (C) 2025 Aaron Mathis aaron.mathis@gmail.com
+
+This file is part of GoSight.
+
+GoSight is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+GoSight is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with GoSight. If not, see https://www.gnu.org/licenses/.
+*/
+
+// gosight/agent/internal/utils/stats.go
+// stats.go aggregates samples over a reporting window into StatisticValues.
+
+package agentutils
+
+import (
+	"sync"
+	"time"
+
+	"github.com/aaronlmathis/gosight-shared/model"
+)
+
+// Window accumulates samples between reports into min/max/sum/count. It is
+// safe for concurrent use; the zero value is ready to use.
+type Window struct {
+	mu    sync.Mutex
+	stats model.StatisticValues
+}
+
+// Observe adds a sample to the current window.
+func (w *Window) Observe(v float64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.stats.SampleCount == 0 || v < w.stats.Minimum {
+		w.stats.Minimum = v
+	}
+	if w.stats.SampleCount == 0 || v > w.stats.Maximum {
+		w.stats.Maximum = v
+	}
+	w.stats.Sum += v
+	w.stats.SampleCount++
+}
+
+// Take returns the statistics of the current window and starts a new one.
+// It returns nil when no samples were observed.
+func (w *Window) Take() *model.StatisticValues {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.stats.SampleCount == 0 {
+		return nil
+	}
+	out := w.stats
+	w.stats = model.StatisticValues{}
+	return &out
+}
+
+// SummaryMetric builds a gauge whose value is the mean of stats and which
+// carries stats so exporters can report the full distribution (the OTLP
+// exporter turns it into a histogram point).
+func SummaryMetric(ns, sub, name string, stats *model.StatisticValues, unit string, dims map[string]string, ts time.Time) model.Metric {
+	var mean float64
+	if stats != nil && stats.SampleCount > 0 {
+		mean = stats.Sum / float64(stats.SampleCount)
+	}
+	m := Metric(ns, sub, name, mean, "gauge", unit, dims, ts)
+	m.StatisticValues = stats
+	return m
+}
@@ -30,8 +30,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
 
 	"github.com/aaronlmathis/gosight-agent/internal/config"
+	"github.com/aaronlmathis/gosight-shared/utils"
 )
 
 // LoadTLSConfig loads the TLS configuration for the agent.
@@ -61,14 +64,80 @@ func LoadTLSConfig(cfg *config.Config) (*tls.Config, error) {
 		MinVersion: tls.VersionTLS12,
 	}
 
-	// Add client cert for mTLS if provided
+	// Add client cert for mTLS if provided. It is presented through a
+	// callback so a certificate rotated on disk is used on the next handshake.
 	if cfg.TLS.CertFile != "" && cfg.TLS.KeyFile != "" {
-		cert, err := tls.LoadX509KeyPair(cfg.TLS.CertFile, cfg.TLS.KeyFile)
+		reloader, err := newCertReloader(cfg.TLS.CertFile, cfg.TLS.KeyFile)
 		if err != nil {
 			return nil, fmt.Errorf("failed to load client cert/key: %w", err)
 		}
-		tlsCfg.Certificates = []tls.Certificate{cert}
+		tlsCfg.GetClientCertificate = reloader.GetClientCertificate
 	}
 
 	return tlsCfg, nil
 }
+
+// certReloader caches a client key pair and reloads it from disk when the
+// cert or key file's modification time changes.
+type certReloader struct {
+	certFile, keyFile string
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	certMod time.Time
+	keyMod  time.Time
+}
+
+// newCertReloader loads the key pair once so a bad path or key fails at
+// startup rather than on the first handshake.
+func newCertReloader(certFile, keyFile string) (*certReloader, error) {
+	r := &certReloader{certFile: certFile, keyFile: keyFile}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetClientCertificate implements tls.Config.GetClientCertificate. If the
+// files changed but can't be loaded (e.g. mid-rotation, with only the cert
+// written), the previous key pair keeps being presented.
+func (r *certReloader) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.changed() {
+		if err := r.reload(); err != nil {
+			utils.Warn("Failed to reload client certificate %s: %v; keeping the previous one", r.certFile, err)
+		} else {
+			utils.Info("Reloaded client certificate %s", r.certFile)
+		}
+	}
+	return r.cert, nil
+}
+
+// changed reports whether either file's modification time differs from the
+// loaded key pair's.
+func (r *certReloader) changed() bool {
+	certMod, keyMod := modTime(r.certFile), modTime(r.keyFile)
+	return !certMod.Equal(r.certMod) || !keyMod.Equal(r.keyMod)
+}
+
+func (r *certReloader) reload() error {
+	certMod, keyMod := modTime(r.certFile), modTime(r.keyFile)
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return err
+	}
+	r.cert, r.certMod, r.keyMod = &cert, certMod, keyMod
+	return nil
+}
+
+// modTime returns the file's modification time, or the zero time if it
+// can't be stat'ed.
+func modTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}